@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	kafkaAdapter "rag_imagetotext_texttoimage/internal/adapter/kafka"
+	"rag_imagetotext_texttoimage/internal/application/ports"
+	infraKafka "rag_imagetotext_texttoimage/internal/infra/kafka"
+	"rag_imagetotext_texttoimage/internal/util"
+)
+
+// kafka_dlq_replay moves the messages of <topic>.dlq back to the topic they
+// failed on, without the retry and error headers, so they go through the
+// handler and its retries again. The replay group remembers its position, so
+// a message is replayed once; it stops after -max messages or when the DLQ
+// has been idle for -idle-timeout.
+func main() {
+	topic := flag.String("topic", "", "source topic whose <topic>.dlq is replayed")
+	group := flag.String("group", "", "consumer group for the dlq (default dlq-replay-<topic>)")
+	maxMessages := flag.Int("max", 0, "maximum messages to replay, 0 for all")
+	idleTimeout := flag.Duration("idle-timeout", 10*time.Second, "stop after no message arrived for this long")
+	dryRun := flag.Bool("dry-run", false, "log the messages without publishing them, using a throwaway group")
+	flag.Parse()
+
+	sourceTopic := strings.TrimSpace(*topic)
+	if sourceTopic == "" {
+		util.Fatalf("-topic is required")
+	}
+	dlqTopic := kafkaAdapter.DLQTopic(sourceTopic)
+	groupID := strings.TrimSpace(*group)
+	if groupID == "" {
+		groupID = "dlq-replay-" + sourceTopic
+	}
+	if *dryRun {
+		groupID = fmt.Sprintf("%s-dry-run-%d", groupID, time.Now().UnixNano())
+	}
+
+	// Config and logger are built directly: the bootstrap package links the
+	// ONNX runtime, which this tool does not need.
+	cfg, err := util.NewConfigLoader("./config/.env", "./config/config.yaml").Load()
+	if err != nil {
+		util.Fatalf("failed to load config: %v", err)
+	}
+	appLogger, err := util.NewFileLogger("logs/kafka_dlq_replay.log", slog.LevelInfo)
+	if err != nil {
+		util.Fatalf("failed to create logger: %v", err)
+	}
+	defer appLogger.Close()
+
+	kafkaClient, err := infraKafka.NewKafkaClient(infraKafka.KafkaConfig{
+		Brokers:     cfg.Kafka.Brokers,
+		DialTimeout: 10 * time.Second,
+	}, appLogger)
+	if err != nil {
+		util.Fatalf("failed to create kafka client: %v", err)
+	}
+	publisher, err := infraKafka.NewPublisher(kafkaClient, infraKafka.PublisherConfig{
+		RequiredAcks: -1,
+	}, appLogger)
+	if err != nil {
+		util.Fatalf("failed to create kafka publisher: %v", err)
+	}
+	defer publisher.Close()
+	consumer, err := infraKafka.NewKafkaConsumer(kafkaClient, infraKafka.ConsumerConfig{
+		MinBytes:    1,
+		MaxBytes:    10e6,
+		StartOffset: -2,
+	}, appLogger)
+	if err != nil {
+		util.Fatalf("failed to create kafka consumer: %v", err)
+	}
+	defer consumer.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	var (
+		mu         sync.Mutex
+		replayed   int
+		lastActive = time.Now()
+	)
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				mu.Lock()
+				idle := time.Since(lastActive)
+				mu.Unlock()
+				if idle >= *idleTimeout {
+					appLogger.Info("dlq replay idle, stopping", "dlq_topic", dlqTopic, "idle_ms", idle.Milliseconds())
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	appLogger.Info("dlq replay started", "dlq_topic", dlqTopic, "group_id", groupID, "max", *maxMessages, "dry_run", *dryRun)
+	consumeErr := consumer.Consume(ctx, dlqTopic, groupID, func(hctx context.Context, msg ports.ConsumeMessage) error {
+		mu.Lock()
+		lastActive = time.Now()
+		mu.Unlock()
+
+		target := strings.TrimSpace(msg.Message.Headers[kafkaAdapter.HeaderOriginalTopic])
+		if target == "" {
+			target = sourceTopic
+		}
+		if *dryRun {
+			appLogger.Info(
+				"dlq message",
+				"offset", msg.Offset,
+				"target_topic", target,
+				"error", msg.Message.Headers[kafkaAdapter.HeaderError],
+				"error_class", msg.Message.Headers[kafkaAdapter.HeaderErrorClass],
+				"failed_at", msg.Message.Headers[kafkaAdapter.HeaderFailedAt],
+			)
+			return countReplayed(&mu, &replayed, *maxMessages)
+		}
+
+		if err := publisher.Publish(hctx, ports.PublishMessageInput{
+			Topic: target,
+			Message: ports.KafkaMessage{
+				Key:     msg.Message.Key,
+				Value:   msg.Message.Value,
+				Headers: replayHeaders(msg.Message.Headers, dlqTopic),
+			},
+		}); err != nil {
+			return fmt.Errorf("replay offset %d to %s: %w", msg.Offset, target, err)
+		}
+		appLogger.Info("dlq message replayed", "offset", msg.Offset, "target_topic", target)
+		return countReplayed(&mu, &replayed, *maxMessages)
+	})
+	if consumeErr != nil {
+		util.Fatalf("dlq replay failed after %d messages: %v", replayed, consumeErr)
+	}
+	appLogger.Info("dlq replay finished", "dlq_topic", dlqTopic, "replayed", replayed, "dry_run", *dryRun)
+}
+
+// countReplayed stops the replay once max messages have been handled. It
+// returns ports.ErrStopConsuming rather than cancelling the context, so the
+// last message is committed before the consumer stops and is not replayed
+// again by the next run.
+func countReplayed(mu *sync.Mutex, replayed *int, max int) error {
+	mu.Lock()
+	*replayed++
+	done := max > 0 && *replayed >= max
+	mu.Unlock()
+	if done {
+		return ports.ErrStopConsuming
+	}
+	return nil
+}
+
+// replayHeaders drops the retry and error headers so the message starts over
+// at attempt 0, and records where it was replayed from.
+func replayHeaders(headers map[string]string, dlqTopic string) map[string]string {
+	out := make(map[string]string, len(headers)+2)
+	for k, v := range headers {
+		out[k] = v
+	}
+	for _, k := range []string{
+		kafkaAdapter.HeaderRetryAttempt,
+		kafkaAdapter.HeaderRetryNotBefore,
+		kafkaAdapter.HeaderOriginalTopic,
+		kafkaAdapter.HeaderOriginalPartition,
+		kafkaAdapter.HeaderOriginalOffset,
+		kafkaAdapter.HeaderError,
+		kafkaAdapter.HeaderErrorClass,
+		kafkaAdapter.HeaderFailedAt,
+	} {
+		delete(out, k)
+	}
+	out["x-replayed-from"] = dlqTopic
+	out["x-replayed-at"] = time.Now().UTC().Format(time.RFC3339)
+	return out
+}
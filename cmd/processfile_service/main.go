@@ -19,6 +19,7 @@ import (
 	"rag_imagetotext_texttoimage/internal/application/ports"
 	trainingfile "rag_imagetotext_texttoimage/internal/application/use_cases/orchestrator/training_file"
 	"rag_imagetotext_texttoimage/internal/bootstrap"
+	"rag_imagetotext_texttoimage/internal/infra/docparser"
 	infraKafka "rag_imagetotext_texttoimage/internal/infra/kafka"
 	"rag_imagetotext_texttoimage/internal/infra/monitoring"
 	"rag_imagetotext_texttoimage/internal/util"
@@ -97,18 +98,21 @@ func main() {
 		util.Fatalf("failed to create kafka consumer: %v", err)
 	}
 	defer consumer.Close()
+	replies := kafkaAdapter.NewRequestReply(publisher, consumer, kafkaAdapter.ReplyGroupID("processfile"), appLogger)
+	defer replies.Close()
 	appLogger.Info("process file kafka adapters ready")
 
 	producerAdapter := kafkaAdapter.NewProducerAdapter(publisher)
 	consumerAdapter := kafkaAdapter.NewConsumerAdapter(consumer, appLogger)
-	trainingFileUseCase := trainingfile.NewTrainingFileUseCase(appLogger, publisher, consumer, *cfg)
+	trainingFileUseCase := trainingfile.NewTrainingFileUseCase(appLogger, publisher, replies, *cfg, docparser.NewDocumentParser(appLogger, cfg.FileTraining.DocumentParser))
 	metricsKafka := monitoring.NewMetrics()
 	metricsSrv := newProcessFileMetricsHTTPServer(cfg)
 
 	rootCtx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	consumerErrCh := consumerAdapter.Start(rootCtx, topics.ProcessFileRequest, topics.ProcessFileGroup, func(ctx context.Context, msg ports.ConsumeMessage) (handlerErr error) {
+	retry := kafkaAdapter.NewRetryMiddleware(producerAdapter, kafkaAdapter.RetryPolicyFromConfig(cfg.Kafka.Retry), metricsKafka, appLogger)
+	consumerErrCh := consumerAdapter.StartWithRetry(rootCtx, topics.ProcessFileRequest, topics.ProcessFileGroup, "process_file_ingest", func(ctx context.Context, msg ports.ConsumeMessage) (handlerErr error) {
 		handlerName := "process_file_ingest"
 		telemetry := newProcessFileTelemetry(metricsKafka, appLogger, msg, handlerName, "panic recovered in process file handler")
 		telemetry.start()
@@ -138,7 +142,7 @@ func main() {
 					appLogger.Error("publish invalid process file request result failed", publishErr, "result_topic", topics.ProcessFileResult, "correlation_id", correlationID)
 				}
 			}
-			return nil
+			return ports.Permanent(fmt.Errorf("decode process file request: %w", err))
 		}
 
 		correlationID = resolveCorrelationID(req.CorrelationID, msg)
@@ -150,6 +154,9 @@ func main() {
 			telemetry.observe("failed")
 			message = processErr.Error()
 			appLogger.Error("process and ingest failed", processErr, "uuid", req.UUID, "correlation_id", correlationID)
+			if !kafkaAdapter.FinalAttempt(ctx) {
+				return fmt.Errorf("process and ingest: %w", processErr)
+			}
 		}
 
 		if topics.ProcessFileResult != "" {
@@ -168,6 +175,7 @@ func main() {
 			if publishErr != nil {
 				telemetry.retry("publish_error")
 				appLogger.Error("publish process file result failed", publishErr, "result_topic", topics.ProcessFileResult, "correlation_id", correlationID)
+				return fmt.Errorf("publish process file result: %w", publishErr)
 			}
 		}
 		if success {
@@ -182,8 +190,8 @@ func main() {
 			"success", success,
 			"latency_ms", time.Since(telemetry.startedAt).Milliseconds(),
 		)
-		return nil
-	})
+		return processErr
+	}, retry)
 
 	metricsErrCh := make(chan error, 1)
 	go func() {
@@ -350,7 +358,8 @@ func (t *processFileTelemetry) recover(handlerErr *error) {
 		t.observe("panic")
 	}
 	if handlerErr != nil {
-		*handlerErr = panicErr
+		// A message that panics the handler is poison: skip the retries.
+		*handlerErr = ports.Permanent(panicErr)
 	}
 	if t.logger != nil {
 		t.logger.Error(t.panicLogMessage, panicErr, "topic", t.msg.Topic, "offset", t.msg.Offset)
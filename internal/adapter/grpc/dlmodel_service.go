@@ -11,18 +11,26 @@ import (
 	"rag_imagetotext_texttoimage/internal/application/ports"
 	"rag_imagetotext_texttoimage/internal/util"
 	pb "rag_imagetotext_texttoimage/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type EmbeddingService struct {
 	pb.UnimplementedDeepLearningServiceServer
 	appLogger util.Logger
 	infer     ports.Inference
+	reranker  ports.Reranker
+	batcher   *EmbeddingBatcher
 }
 
-func NewEmbeddingService(appLogger util.Logger, infer ports.Inference) *EmbeddingService {
+// NewEmbeddingService calls infer directly when batcher is nil.
+func NewEmbeddingService(appLogger util.Logger, infer ports.Inference, reranker ports.Reranker, batcher *EmbeddingBatcher) *EmbeddingService {
 	return &EmbeddingService{
 		appLogger: appLogger,
 		infer:     infer,
+		reranker:  reranker,
+		batcher:   batcher,
 	}
 }
 
@@ -49,7 +57,13 @@ func (s *EmbeddingService) EmbedText(ctx context.Context, req *pb.EmbedTextReque
 		return nil, err
 	}
 
-	embedding, err := s.infer.EmbedText(request.Text)
+	var embedding []float32
+	var err error
+	if s.batcher != nil {
+		embedding, err = s.batcher.EmbedText(ctx, request.Text)
+	} else {
+		embedding, err = s.infer.EmbedText(request.Text)
+	}
 	if err != nil {
 		s.appLogger.Error("internal.adapter.grpc.EmbeddingService.EmbedText inference failed", err)
 		return nil, err
@@ -109,7 +123,13 @@ func (s *EmbeddingService) EmbedImage(ctx context.Context, req *pb.EmbedImageReq
 		return nil, err
 	}
 
-	embedding, err := s.infer.EmbedImage(request.Pixels, request.Width, request.Height, request.Channels)
+	var embedding []float32
+	var err error
+	if s.batcher != nil {
+		embedding, err = s.batcher.EmbedImage(ctx, request.Pixels, request.Width, request.Height, request.Channels)
+	} else {
+		embedding, err = s.infer.EmbedImage(request.Pixels, request.Width, request.Height, request.Channels)
+	}
 	if err != nil {
 		s.appLogger.Error("internal.adapter.grpc.EmbeddingService.EmbedImage inference failed", err)
 		return nil, err
@@ -128,3 +148,52 @@ func (s *EmbeddingService) EmbedImage(ctx context.Context, req *pb.EmbedImageReq
 		Status:    response.Status,
 	}, nil
 }
+
+func (s *EmbeddingService) Rerank(ctx context.Context, req *pb.RerankRequest) (*pb.RerankResponse, error) {
+	startedAt := time.Now()
+	if req != nil {
+		s.appLogger.Info("embedding grpc Rerank started", "query_len", len(strings.TrimSpace(req.Query)), "document_count", len(req.Documents))
+	}
+	if err := ctx.Err(); err != nil {
+		s.appLogger.Error("internal.adapter.grpc.EmbeddingService.Rerank context error", err)
+		return nil, err
+	}
+
+	if req == nil {
+		err := errors.New("request is nil")
+		s.appLogger.Error("internal.adapter.grpc.EmbeddingService.Rerank invalid request", err)
+		return nil, err
+	}
+	if s.reranker == nil {
+		err := status.Error(codes.Unimplemented, ports.ErrRerankerNotConfigured.Error())
+		s.appLogger.Error("internal.adapter.grpc.EmbeddingService.Rerank reranker unavailable", err)
+		return nil, err
+	}
+
+	query := strings.TrimSpace(req.Query)
+	if query == "" {
+		err := errors.New("query is required")
+		s.appLogger.Error("internal.adapter.grpc.EmbeddingService.Rerank invalid payload", err)
+		return nil, err
+	}
+	if len(req.Documents) == 0 {
+		return &pb.RerankResponse{Scores: []float32{}, Status: true}, nil
+	}
+
+	scores, err := s.reranker.Rerank(query, req.Documents)
+	if err != nil {
+		s.appLogger.Error("internal.adapter.grpc.EmbeddingService.Rerank inference failed", err)
+		if errors.Is(err, ports.ErrRerankerNotConfigured) {
+			return nil, status.Error(codes.Unimplemented, err.Error())
+		}
+		return nil, err
+	}
+	if len(scores) != len(req.Documents) {
+		err := fmt.Errorf("reranker returned %d scores for %d documents", len(scores), len(req.Documents))
+		s.appLogger.Error("internal.adapter.grpc.EmbeddingService.Rerank invalid scores", err)
+		return nil, err
+	}
+	s.appLogger.Info("embedding grpc Rerank completed", "document_count", len(scores), "latency_ms", time.Since(startedAt).Milliseconds())
+
+	return &pb.RerankResponse{Scores: scores, Status: true}, nil
+}
@@ -0,0 +1,336 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"rag_imagetotext_texttoimage/internal/application/ports"
+	"rag_imagetotext_texttoimage/internal/infra/monitoring"
+	"rag_imagetotext_texttoimage/internal/util"
+)
+
+const (
+	defaultBatchMaxWait     = 5 * time.Millisecond
+	defaultBatchMaxSize     = 16
+	defaultBatchMaxInFlight = 2
+
+	batchKindText  = "text"
+	batchKindImage = "image"
+)
+
+var errEmbeddingBatcherClosed = errors.New("embedding batcher is closed")
+
+// EmbeddingBatcher coalesces concurrent EmbedText and EmbedImage calls into
+// EmbedBatchText/EmbedBatchImage calls. A batch runs once it is MaxWait old
+// or holds MaxBatch requests; images are only batched with images of the
+// same shape. When a batch call fails every request of it is retried on its
+// own, so one bad input fails only its caller.
+type EmbeddingBatcher struct {
+	text  *batchQueue[string]
+	image *batchQueue[batchImage]
+}
+
+type batchImage struct {
+	pixels   []byte
+	width    int
+	height   int
+	channels int
+}
+
+func NewEmbeddingBatcher(infer ports.Inference, cfg util.EmbeddingBatchSettings, metrics *monitoring.EmbeddingBatchMetrics, appLogger util.Logger) *EmbeddingBatcher {
+	maxWait := time.Duration(cfg.MaxWaitMs) * time.Millisecond
+	if maxWait <= 0 {
+		maxWait = defaultBatchMaxWait
+	}
+	maxBatch := cfg.MaxBatch
+	if maxBatch <= 0 {
+		maxBatch = defaultBatchMaxSize
+	}
+	maxInFlight := cfg.MaxInFlight
+	if maxInFlight <= 0 {
+		maxInFlight = defaultBatchMaxInFlight
+	}
+
+	b := &EmbeddingBatcher{
+		text: newBatchQueue(batchKindText, maxWait, maxBatch, maxInFlight, metrics, appLogger,
+			func(string) string { return "" },
+			infer.EmbedBatchText,
+			infer.EmbedText,
+		),
+		image: newBatchQueue(batchKindImage, maxWait, maxBatch, maxInFlight, metrics, appLogger,
+			func(img batchImage) string {
+				return strconv.Itoa(img.width) + "x" + strconv.Itoa(img.height) + "x" + strconv.Itoa(img.channels)
+			},
+			func(images []batchImage) ([][]float32, error) {
+				pixels := make([][]byte, len(images))
+				for i, img := range images {
+					pixels[i] = img.pixels
+				}
+				return infer.EmbedBatchImage(pixels, images[0].width, images[0].height, images[0].channels)
+			},
+			func(img batchImage) ([]float32, error) {
+				return infer.EmbedImage(img.pixels, img.width, img.height, img.channels)
+			},
+		),
+	}
+	appLogger.Info("embedding grpc batcher started", "max_wait_ms", maxWait.Milliseconds(), "max_batch", maxBatch, "max_in_flight", maxInFlight)
+	return b
+}
+
+func (b *EmbeddingBatcher) EmbedText(ctx context.Context, text string) ([]float32, error) {
+	return b.text.submit(ctx, text)
+}
+
+func (b *EmbeddingBatcher) EmbedImage(ctx context.Context, pixels []byte, width, height, channels int) ([]float32, error) {
+	return b.image.submit(ctx, batchImage{pixels: pixels, width: width, height: height, channels: channels})
+}
+
+// Close fails the requests that have not run yet and waits for the running
+// batches. Stop the gRPC server first.
+func (b *EmbeddingBatcher) Close() {
+	if b == nil {
+		return
+	}
+	b.text.close()
+	b.image.close()
+}
+
+type batchJob[T any] struct {
+	ctx   context.Context
+	item  T
+	reply chan batchReply
+}
+
+type batchReply struct {
+	embedding []float32
+	err       error
+}
+
+type pendingBatch[T any] struct {
+	jobs     []batchJob[T]
+	deadline time.Time
+}
+
+type batchQueue[T any] struct {
+	kind        string
+	maxWait     time.Duration
+	maxBatch    int
+	key         func(T) string
+	runBatch    func([]T) ([][]float32, error)
+	runOne      func(T) ([]float32, error)
+	metrics     *monitoring.EmbeddingBatchMetrics
+	logger      util.Logger
+	jobs        chan batchJob[T]
+	inFlight    chan struct{}
+	waiting     atomic.Int64
+	stop        chan struct{}
+	collectorWG sync.WaitGroup
+	runWG       sync.WaitGroup
+	mu          sync.RWMutex
+	closed      bool
+}
+
+func newBatchQueue[T any](
+	kind string,
+	maxWait time.Duration,
+	maxBatch int,
+	maxInFlight int,
+	metrics *monitoring.EmbeddingBatchMetrics,
+	appLogger util.Logger,
+	key func(T) string,
+	runBatch func([]T) ([][]float32, error),
+	runOne func(T) ([]float32, error),
+) *batchQueue[T] {
+	q := &batchQueue[T]{
+		kind:     kind,
+		maxWait:  maxWait,
+		maxBatch: maxBatch,
+		key:      key,
+		runBatch: runBatch,
+		runOne:   runOne,
+		metrics:  metrics,
+		logger:   appLogger,
+		jobs:     make(chan batchJob[T], maxBatch),
+		inFlight: make(chan struct{}, maxInFlight),
+		stop:     make(chan struct{}),
+	}
+	q.collectorWG.Add(1)
+	go q.collect()
+	return q
+}
+
+func (q *batchQueue[T]) submit(ctx context.Context, item T) ([]float32, error) {
+	job := batchJob[T]{ctx: ctx, item: item, reply: make(chan batchReply, 1)}
+
+	q.mu.RLock()
+	if q.closed {
+		q.mu.RUnlock()
+		return nil, errEmbeddingBatcherClosed
+	}
+	depth := q.waiting.Add(1) - 1
+	if q.metrics != nil {
+		q.metrics.QueueDepth.WithLabelValues(q.kind).Observe(float64(depth))
+	}
+	select {
+	case q.jobs <- job:
+	case <-ctx.Done():
+		q.waiting.Add(-1)
+		q.mu.RUnlock()
+		return nil, ctx.Err()
+	}
+	q.mu.RUnlock()
+
+	select {
+	case reply := <-job.reply:
+		return reply.embedding, reply.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// collect groups incoming jobs by key and hands a group to dispatch when it
+// is full or its deadline passes.
+func (q *batchQueue[T]) collect() {
+	defer q.collectorWG.Done()
+	pending := map[string]*pendingBatch[T]{}
+	timer := time.NewTimer(q.maxWait)
+	timer.Stop()
+	resetTimer := func() {
+		var next time.Time
+		for _, batch := range pending {
+			if next.IsZero() || batch.deadline.Before(next) {
+				next = batch.deadline
+			}
+		}
+		timer.Stop()
+		if !next.IsZero() {
+			timer.Reset(max(time.Until(next), 0))
+		}
+	}
+
+	for {
+		select {
+		case job := <-q.jobs:
+			key := q.key(job.item)
+			batch := pending[key]
+			if batch == nil {
+				batch = &pendingBatch[T]{deadline: time.Now().Add(q.maxWait)}
+				pending[key] = batch
+			}
+			batch.jobs = append(batch.jobs, job)
+			if len(batch.jobs) >= q.maxBatch {
+				delete(pending, key)
+				q.dispatch(batch.jobs)
+			}
+			resetTimer()
+		case <-timer.C:
+			now := time.Now()
+			for key, batch := range pending {
+				if !batch.deadline.After(now) {
+					delete(pending, key)
+					q.dispatch(batch.jobs)
+				}
+			}
+			resetTimer()
+		case <-q.stop:
+			timer.Stop()
+			for _, batch := range pending {
+				q.fail(batch.jobs, errEmbeddingBatcherClosed)
+			}
+			for {
+				select {
+				case job := <-q.jobs:
+					q.fail([]batchJob[T]{job}, errEmbeddingBatcherClosed)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// dispatch runs a batch on its own goroutine. It blocks while MaxInFlight
+// batches are running, so under load requests pile up into bigger batches.
+func (q *batchQueue[T]) dispatch(jobs []batchJob[T]) {
+	q.inFlight <- struct{}{}
+	q.waiting.Add(-int64(len(jobs)))
+	q.runWG.Add(1)
+	go func() {
+		defer q.runWG.Done()
+		defer func() { <-q.inFlight }()
+		q.run(jobs)
+	}()
+}
+
+func (q *batchQueue[T]) run(jobs []batchJob[T]) {
+	live := jobs[:0:0]
+	for _, job := range jobs {
+		if err := job.ctx.Err(); err != nil {
+			job.reply <- batchReply{err: err}
+			continue
+		}
+		live = append(live, job)
+	}
+	if len(live) == 0 {
+		return
+	}
+	if q.metrics != nil {
+		q.metrics.BatchSize.WithLabelValues(q.kind).Observe(float64(len(live)))
+	}
+	if len(live) == 1 {
+		embedding, err := q.runOne(live[0].item)
+		live[0].reply <- batchReply{embedding: embedding, err: err}
+		return
+	}
+
+	items := make([]T, len(live))
+	for i, job := range live {
+		items[i] = job.item
+	}
+	embeddings, err := q.runBatch(items)
+	if err == nil && len(embeddings) != len(live) {
+		err = fmt.Errorf("batch returned %d embeddings for %d requests", len(embeddings), len(live))
+	}
+	if err == nil {
+		for i, job := range live {
+			job.reply <- batchReply{embedding: embeddings[i]}
+		}
+		return
+	}
+
+	q.logger.Error("internal.adapter.grpc.EmbeddingBatcher batch failed, embedding one by one", err, "kind", q.kind, "batch_size", len(live))
+	for _, job := range live {
+		if ctxErr := job.ctx.Err(); ctxErr != nil {
+			job.reply <- batchReply{err: ctxErr}
+			continue
+		}
+		embedding, err := q.runOne(job.item)
+		job.reply <- batchReply{embedding: embedding, err: err}
+	}
+}
+
+func (q *batchQueue[T]) fail(jobs []batchJob[T], err error) {
+	q.waiting.Add(-int64(len(jobs)))
+	for _, job := range jobs {
+		job.reply <- batchReply{err: err}
+	}
+}
+
+func (q *batchQueue[T]) close() {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return
+	}
+	q.closed = true
+	q.mu.Unlock()
+
+	close(q.stop)
+	q.collectorWG.Wait()
+	q.runWG.Wait()
+}
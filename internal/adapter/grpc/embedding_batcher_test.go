@@ -0,0 +1,270 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"rag_imagetotext_texttoimage/internal/util"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Info(string, ...any)         {}
+func (nopLogger) Error(string, error, ...any) {}
+func (nopLogger) Debug(string, ...any)        {}
+func (nopLogger) Close() error                { return nil }
+
+// recordingInference embeds a text as {len(text)} and an image as {width},
+// and records every call. Texts listed in block wait for release; batches
+// fail when failBatch is set, and single texts fail when listed in bad.
+type recordingInference struct {
+	mu          sync.Mutex
+	textBatches [][]string
+	textSingles []string
+	imageShapes [][3]int
+	imageSizes  []int
+
+	failBatch bool
+	bad       map[string]bool
+	block     map[string]chan struct{}
+	started   chan string
+}
+
+func (f *recordingInference) EmbedText(text string) ([]float32, error) {
+	f.mu.Lock()
+	f.textSingles = append(f.textSingles, text)
+	release := f.block[text]
+	f.mu.Unlock()
+	if f.started != nil {
+		f.started <- text
+	}
+	if release != nil {
+		<-release
+	}
+	if f.bad[text] {
+		return nil, fmt.Errorf("bad input %q", text)
+	}
+	return []float32{float32(len(text))}, nil
+}
+
+func (f *recordingInference) EmbedBatchText(texts []string) ([][]float32, error) {
+	f.mu.Lock()
+	f.textBatches = append(f.textBatches, append([]string(nil), texts...))
+	f.mu.Unlock()
+	if f.failBatch {
+		return nil, errors.New("batch failed")
+	}
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		out[i] = []float32{float32(len(text))}
+	}
+	return out, nil
+}
+
+func (f *recordingInference) EmbedImage(_ []byte, width, height, channels int) ([]float32, error) {
+	f.mu.Lock()
+	f.imageShapes = append(f.imageShapes, [3]int{width, height, channels})
+	f.imageSizes = append(f.imageSizes, 1)
+	f.mu.Unlock()
+	return []float32{float32(width)}, nil
+}
+
+func (f *recordingInference) EmbedBatchImage(images [][]byte, width, height, channels int) ([][]float32, error) {
+	f.mu.Lock()
+	f.imageShapes = append(f.imageShapes, [3]int{width, height, channels})
+	f.imageSizes = append(f.imageSizes, len(images))
+	f.mu.Unlock()
+	out := make([][]float32, len(images))
+	for i := range images {
+		out[i] = []float32{float32(width)}
+	}
+	return out, nil
+}
+
+func (f *recordingInference) Close() {}
+
+func newTestBatcher(t *testing.T, infer *recordingInference, cfg util.EmbeddingBatchSettings) *EmbeddingBatcher {
+	t.Helper()
+	b := NewEmbeddingBatcher(infer, cfg, nil, nopLogger{})
+	t.Cleanup(b.Close)
+	return b
+}
+
+// waitQueued waits until n requests are waiting for a batch.
+func waitQueued[T any](t *testing.T, q *batchQueue[T], n int64) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for q.waiting.Load() < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d requests queued, want %d", q.waiting.Load(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+type textResult struct {
+	text      string
+	embedding []float32
+	err       error
+}
+
+func embedTexts(ctx context.Context, b *EmbeddingBatcher, texts ...string) chan textResult {
+	results := make(chan textResult, len(texts))
+	for _, text := range texts {
+		go func() {
+			embedding, err := b.EmbedText(ctx, text)
+			results <- textResult{text: text, embedding: embedding, err: err}
+		}()
+	}
+	return results
+}
+
+func TestEmbeddingBatcherFlushesOnSize(t *testing.T) {
+	infer := &recordingInference{}
+	b := newTestBatcher(t, infer, util.EmbeddingBatchSettings{MaxWaitMs: int(time.Hour.Milliseconds()), MaxBatch: 4})
+
+	results := embedTexts(context.Background(), b, "a", "bb", "ccc", "dddd")
+	for range 4 {
+		r := <-results
+		if r.err != nil || len(r.embedding) != 1 || r.embedding[0] != float32(len(r.text)) {
+			t.Fatalf("%q: embedding = %v, err = %v", r.text, r.embedding, r.err)
+		}
+	}
+	if len(infer.textBatches) != 1 || len(infer.textBatches[0]) != 4 || len(infer.textSingles) != 0 {
+		t.Fatalf("batches = %q, singles = %q", infer.textBatches, infer.textSingles)
+	}
+}
+
+func TestEmbeddingBatcherFlushesOnDeadline(t *testing.T) {
+	const maxWait = 100 * time.Millisecond
+	infer := &recordingInference{}
+	b := newTestBatcher(t, infer, util.EmbeddingBatchSettings{MaxWaitMs: int(maxWait.Milliseconds()), MaxBatch: 100})
+
+	startedAt := time.Now()
+	results := embedTexts(context.Background(), b, "a", "bb", "ccc")
+	for range 3 {
+		if r := <-results; r.err != nil {
+			t.Fatal(r.err)
+		}
+	}
+	if elapsed := time.Since(startedAt); elapsed < maxWait {
+		t.Fatalf("batch ran after %v, before max_wait %v", elapsed, maxWait)
+	}
+	infer.mu.Lock()
+	defer infer.mu.Unlock()
+	var total int
+	for _, batch := range infer.textBatches {
+		total += len(batch)
+	}
+	if total+len(infer.textSingles) != 3 {
+		t.Fatalf("batches = %q, singles = %q", infer.textBatches, infer.textSingles)
+	}
+}
+
+func TestEmbeddingBatcherGroupsImagesByShape(t *testing.T) {
+	infer := &recordingInference{}
+	b := newTestBatcher(t, infer, util.EmbeddingBatchSettings{MaxWaitMs: int(time.Hour.Milliseconds()), MaxBatch: 2})
+
+	shapes := [][3]int{{2, 2, 3}, {4, 4, 3}, {2, 2, 3}, {4, 4, 3}}
+	var wg sync.WaitGroup
+	for _, shape := range shapes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pixels := make([]byte, shape[0]*shape[1]*shape[2])
+			embedding, err := b.EmbedImage(context.Background(), pixels, shape[0], shape[1], shape[2])
+			if err != nil || len(embedding) != 1 || embedding[0] != float32(shape[0]) {
+				t.Errorf("shape %v: embedding = %v, err = %v", shape, embedding, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	infer.mu.Lock()
+	defer infer.mu.Unlock()
+	slices.SortFunc(infer.imageShapes, func(a, b [3]int) int { return a[0] - b[0] })
+	if !slices.Equal(infer.imageShapes, [][3]int{{2, 2, 3}, {4, 4, 3}}) || !slices.Equal(infer.imageSizes, []int{2, 2}) {
+		t.Fatalf("image calls: shapes = %v, sizes = %v", infer.imageShapes, infer.imageSizes)
+	}
+}
+
+func TestEmbeddingBatcherFallsBackToSingleCalls(t *testing.T) {
+	infer := &recordingInference{failBatch: true, bad: map[string]bool{"bad": true}}
+	b := newTestBatcher(t, infer, util.EmbeddingBatchSettings{MaxWaitMs: int(time.Hour.Milliseconds()), MaxBatch: 3})
+
+	results := embedTexts(context.Background(), b, "a", "bad", "ccc")
+	for range 3 {
+		r := <-results
+		if r.text == "bad" {
+			if r.err == nil {
+				t.Fatal("the bad input succeeded")
+			}
+			continue
+		}
+		if r.err != nil || r.embedding[0] != float32(len(r.text)) {
+			t.Fatalf("%q failed with the bad input: %v", r.text, r.err)
+		}
+	}
+	slices.Sort(infer.textSingles)
+	if len(infer.textBatches) != 1 || !slices.Equal(infer.textSingles, []string{"a", "bad", "ccc"}) {
+		t.Fatalf("batches = %q, singles = %q", infer.textBatches, infer.textSingles)
+	}
+}
+
+func TestEmbeddingBatcherSkipsCanceledRequests(t *testing.T) {
+	release := make(chan struct{})
+	infer := &recordingInference{
+		block:   map[string]chan struct{}{"first": release},
+		started: make(chan string, 2),
+	}
+	b := newTestBatcher(t, infer, util.EmbeddingBatchSettings{MaxWaitMs: 1, MaxBatch: 1, MaxInFlight: 1})
+
+	first := embedTexts(context.Background(), b, "first")
+	if got := <-infer.started; got != "first" {
+		t.Fatalf("started %q", got)
+	}
+
+	// The only in-flight slot is taken, so this request waits in dispatch
+	// until its caller gives up.
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := embedTexts(ctx, b, "canceled")
+	waitQueued(t, b.text, 1)
+	cancel()
+	if r := <-canceled; !errors.Is(r.err, context.Canceled) {
+		t.Fatalf("canceled request: err = %v", r.err)
+	}
+
+	close(release)
+	if r := <-first; r.err != nil {
+		t.Fatal(r.err)
+	}
+	b.Close()
+	if slices.Contains(infer.textSingles, "canceled") {
+		t.Fatal("a canceled request reached the backend")
+	}
+}
+
+func TestEmbeddingBatcherCloseFailsQueuedRequests(t *testing.T) {
+	infer := &recordingInference{}
+	b := newTestBatcher(t, infer, util.EmbeddingBatchSettings{MaxWaitMs: int(time.Hour.Milliseconds()), MaxBatch: 100})
+
+	results := embedTexts(context.Background(), b, "a", "bb")
+	waitQueued(t, b.text, 2)
+	b.Close()
+	for range 2 {
+		if r := <-results; !errors.Is(r.err, errEmbeddingBatcherClosed) {
+			t.Fatalf("%q: err = %v", r.text, r.err)
+		}
+	}
+	if _, err := b.EmbedText(context.Background(), "late"); !errors.Is(err, errEmbeddingBatcherClosed) {
+		t.Fatalf("EmbedText after Close: err = %v", err)
+	}
+	if len(infer.textBatches) != 0 || len(infer.textSingles) != 0 {
+		t.Fatalf("queued requests ran: batches = %q, singles = %q", infer.textBatches, infer.textSingles)
+	}
+}
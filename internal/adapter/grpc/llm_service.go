@@ -187,6 +187,92 @@ func (S *LLMService) GenerateTextToImage(ctx context.Context, req *pb.TextToImag
 	return parseLLMResponse(response), nil
 }
 
+func (S *LLMService) GenerateTextToTextStream(req *pb.TextToTextRequest, stream pb.LlmService_GenerateTextToTextStreamServer) error {
+	startedAt := time.Now()
+	S.appLogger.Info("llm grpc GenerateTextToTextStream started", "model", req.Model, "history_count", len(req.History))
+	request := &dtos.LlmRequest{
+		Temp:      req.Temperature,
+		Prompt:    req.Prompt,
+		Model:     req.Model,
+		History:   parseChatHistory(req.History),
+		ImageMode: false,
+	}
+
+	chunks := 0
+	response, err := S.llmClient.GenerateTextToTextStream(
+		stream.Context(),
+		request.Model,
+		request.Temp,
+		request.Prompt,
+		toPortsChatHistory(request.History),
+		func(delta string) error {
+			chunks++
+			return stream.Send(&pb.LLMStreamChunk{Delta: delta})
+		},
+	)
+	if err != nil {
+		S.appLogger.Error("generate text to text stream failed", err, "model", request.Model)
+		return err
+	}
+	if err := stream.Send(&pb.LLMStreamChunk{Done: true, Text: response.Text}); err != nil {
+		S.appLogger.Error("generate text to text stream send final chunk failed", err, "model", request.Model)
+		return err
+	}
+
+	S.appLogger.Info(
+		"llm grpc GenerateTextToTextStream completed",
+		"model", request.Model,
+		"history_count", len(request.History),
+		"chunks", chunks,
+		"latency_ms", time.Since(startedAt).Milliseconds(),
+	)
+	return nil
+}
+
+func (S *LLMService) GenerateTextToImageStream(req *pb.TextToImageRequest, stream pb.LlmService_GenerateTextToImageStreamServer) error {
+	startedAt := time.Now()
+	S.appLogger.Info("llm grpc GenerateTextToImageStream started", "model", req.Model, "history_count", len(req.History), "image_path", req.ImagePath)
+	request := &dtos.LlmRequest{
+		Temp:      req.Temperature,
+		Prompt:    req.Prompt,
+		Model:     req.Model,
+		History:   parseChatHistory(req.History),
+		ImageMode: true,
+	}
+
+	chunks := 0
+	response, err := S.llmClient.GenerateTextToImageStream(
+		stream.Context(),
+		request.Model,
+		request.Temp,
+		req.ImagePath,
+		request.Prompt,
+		toPortsChatHistory(request.History),
+		func(delta string) error {
+			chunks++
+			return stream.Send(&pb.LLMStreamChunk{Delta: delta})
+		},
+	)
+	if err != nil {
+		S.appLogger.Error("generate text to image stream failed", err, "model", request.Model)
+		return err
+	}
+	if err := stream.Send(&pb.LLMStreamChunk{Done: true, Text: response.Text}); err != nil {
+		S.appLogger.Error("generate text to image stream send final chunk failed", err, "model", request.Model)
+		return err
+	}
+
+	S.appLogger.Info(
+		"llm grpc GenerateTextToImageStream completed",
+		"model", request.Model,
+		"history_count", len(request.History),
+		"image_path", req.ImagePath,
+		"chunks", chunks,
+		"latency_ms", time.Since(startedAt).Milliseconds(),
+	)
+	return nil
+}
+
 func NewLLMService(appLogger util.Logger, llmClient ports.LLM) *LLMService {
 	return &LLMService{
 		appLogger: appLogger,
@@ -105,11 +105,6 @@ func (r *RagService) InsertPoint(ctx context.Context, req *pb.InsertPointRequest
 
 	points := make([]domain.PointObject, 0, len(req.Points))
 	for _, p := range req.Points {
-		id, err := uuid.NewUUID()
-		if err != nil {
-			return &pb.ResponseInsertPoint{CollectionName: req.CollectionName, Status: false}, err
-		}
-
 		vec := domain.VectorObject{}
 		for _, v := range p.VectorObject {
 			switch v.Name {
@@ -124,9 +119,13 @@ func (r *RagService) InsertPoint(ctx context.Context, req *pb.InsertPointRequest
 		}
 
 		payload := mapToPointPayload(p.Payload)
+		id, err := insertPointID(&payload)
+		if err != nil {
+			return &pb.ResponseInsertPoint{CollectionName: req.CollectionName, Status: false}, err
+		}
 
 		points = append(points, domain.PointObject{
-			ID:      id.String(),
+			ID:      id,
 			Vector:  vec,
 			Payload: payload,
 		})
@@ -140,6 +139,24 @@ func (r *RagService) InsertPoint(ctx context.Context, req *pb.InsertPointRequest
 	return &pb.ResponseInsertPoint{CollectionName: req.CollectionName, Status: true}, nil
 }
 
+// insertPointID derives the point id from doc_id and the chunk hash so
+// re-ingesting a document overwrites its points; the chunk hash is computed
+// from the payload when the caller did not send one. Points without doc_id
+// keep a random id.
+func insertPointID(payload *domain.PointPayload) (string, error) {
+	if strings.TrimSpace(payload.DocID) == "" {
+		id, err := uuid.NewUUID()
+		if err != nil {
+			return "", err
+		}
+		return id.String(), nil
+	}
+	if payload.ChunkHash == "" {
+		payload.ChunkHash = domain.ChunkContentHash(*payload)
+	}
+	return domain.DeterministicPointID(payload.DocID, payload.ChunkHash), nil
+}
+
 func (r *RagService) DeletePointFilter(ctx context.Context, req *pb.DeletePointFilterRequest) (*pb.ResponseDeletePointFilter, error) {
 	startedAt := time.Now()
 	r.appLogger.Info("rag grpc DeletePointFilter started", "collection", req.CollectionName)
@@ -175,7 +192,7 @@ func (r *RagService) DeletePointFilter(ctx context.Context, req *pb.DeletePointF
 
 func (r *RagService) SearchPoint(ctx context.Context, req *pb.SearchPointRequest) (*pb.ResponseSearchPoint, error) {
 	startedAt := time.Now()
-	r.appLogger.Info("rag grpc SearchPoint started", "collection", req.CollectionName, "vector_name", req.VectorName, "limit", req.Limit)
+	r.appLogger.Info("rag grpc SearchPoint started", "collection", req.CollectionName, "vector_name", req.VectorName, "limit", req.Limit, "fusion_strategy", req.FusionStrategy)
 
 	query := ports.SearchQuery{
 		CollectionName: req.CollectionName,
@@ -194,14 +211,19 @@ func (r *RagService) SearchPoint(ctx context.Context, req *pb.SearchPointRequest
 		query.Filter = &f
 	}
 
-	results, err := r.searchWithVectorDB.Search(ctx, query)
+	fusion := usecases.FusionOptions{
+		Strategy:      usecases.FusionStrategy(req.FusionStrategy),
+		VectorWeights: normalizeVectorWeights(req.VectorWeights),
+		RRFK:          req.GetRrfK(),
+	}
+	fused, err := r.searchWithVectorDB.SearchWithFusion(ctx, fusion, query)
 	if err != nil {
 		r.appLogger.Error("SearchPoint error", err)
 		return &pb.ResponseSearchPoint{CollectionName: req.CollectionName}, err
 	}
 
-	items := make([]*pb.SearchResultItem, 0, len(results))
-	for _, res := range results {
+	items := make([]*pb.SearchResultItem, 0, len(fused.Results))
+	for _, res := range fused.Results {
 		item := &pb.SearchResultItem{Score: res.Score}
 		if res.Point != nil {
 			item.Id = res.Point.ID
@@ -220,14 +242,30 @@ func (r *RagService) SearchPoint(ctx context.Context, req *pb.SearchPointRequest
 		"vector_name", req.VectorName,
 		"result_count", len(items),
 		"top_score", topScore,
+		"fusion_strategy", string(fused.Strategy),
+		"normalization_mode", fused.NormalizationMode,
 		"latency_ms", time.Since(startedAt).Milliseconds(),
 	)
 	return &pb.ResponseSearchPoint{
-		CollectionName: req.CollectionName,
-		Results:        items,
+		CollectionName:      req.CollectionName,
+		Results:             items,
+		FusionStrategy:      string(fused.Strategy),
+		NormalizationMode:   fused.NormalizationMode,
+		VectorNormalization: fused.VectorNormalization,
 	}, nil
 }
 
+func normalizeVectorWeights(in map[string]float32) map[string]float32 {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make(map[string]float32, len(in))
+	for name, weight := range in {
+		out[strings.ToLower(strings.TrimSpace(name))] = weight
+	}
+	return out
+}
+
 func pbFilterToPortsFilter(f *pb.Filter) ports.Filter {
 	if f == nil {
 		return ports.Filter{}
@@ -275,9 +313,15 @@ func mapToPointPayload(m map[string]string) domain.PointPayload {
 	p.Text = m["text"]
 	p.OCRText = m["ocr_text"]
 	p.ImagePath = m["image_path"]
+	p.ObjectKey = m["object_key"]
 	p.SectionTitle = m["section_title"]
+	p.HeadingPath = m["heading_path"]
+	p.TableCSV = m["table_csv"]
 	p.Lang = m["lang"]
 	p.ParentID = m["parent_id"]
+	p.DocHash = m["doc_hash"]
+	p.ChunkHash = m["chunk_hash"]
+	p.IngestRunID = m["ingest_run_id"]
 
 	if v, err := strconv.Atoi(m["page"]); err == nil {
 		p.Page = v
@@ -309,7 +353,7 @@ func mapToPointPayload(m map[string]string) domain.PointPayload {
 }
 
 func pointPayloadToMap(p domain.PointPayload) map[string]string {
-	m := make(map[string]string, 18)
+	m := make(map[string]string, 24)
 
 	if p.DocID != "" {
 		m["doc_id"] = p.DocID
@@ -332,15 +376,33 @@ func pointPayloadToMap(p domain.PointPayload) map[string]string {
 	if p.ImagePath != "" {
 		m["image_path"] = p.ImagePath
 	}
+	if p.ObjectKey != "" {
+		m["object_key"] = p.ObjectKey
+	}
 	if p.SectionTitle != "" {
 		m["section_title"] = p.SectionTitle
 	}
+	if p.HeadingPath != "" {
+		m["heading_path"] = p.HeadingPath
+	}
+	if p.TableCSV != "" {
+		m["table_csv"] = p.TableCSV
+	}
 	if p.Lang != "" {
 		m["lang"] = p.Lang
 	}
 	if p.ParentID != "" {
 		m["parent_id"] = p.ParentID
 	}
+	if p.DocHash != "" {
+		m["doc_hash"] = p.DocHash
+	}
+	if p.ChunkHash != "" {
+		m["chunk_hash"] = p.ChunkHash
+	}
+	if p.IngestRunID != "" {
+		m["ingest_run_id"] = p.IngestRunID
+	}
 
 	if p.Page != 0 {
 		m["page"] = strconv.Itoa(p.Page)
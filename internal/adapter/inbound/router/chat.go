@@ -32,45 +32,125 @@ func (H *HTTPHandlerChat) HTTPHandlerChatExecute(
 		return
 	}
 
+	req, ok := decodeChatRequest(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := H.chatbot.Execute(r.Context(), req.Query, strings.TrimSpace(req.ImagePath), req.SessionID, chatCollectionScope(req))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+			status = http.StatusRequestTimeout
+		}
+		if errors.Is(err, chat.ErrUnknownWorkspace) {
+			status = http.StatusBadRequest
+		}
+		util.WriteJSON(w, status, orchestrator.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, response)
+
+}
+
+func (H *HTTPHandlerChat) HTTPHandlerChatStreamExecute(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	if H == nil || H.chatbot == nil {
+		util.WriteJSON(w, http.StatusInternalServerError, orchestrator.ErrorResponse{Error: "chatbot handler is not configured"})
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		util.WriteJSON(w, http.StatusInternalServerError, orchestrator.ErrorResponse{Error: "streaming is not supported"})
+		return
+	}
+
+	req, ok := decodeChatRequest(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	_, err := H.chatbot.ExecuteStream(r.Context(), req.Query, strings.TrimSpace(req.ImagePath), req.SessionID, chatCollectionScope(req), func(event orchestrator.ChatStreamEvent) error {
+		// Writes to a closed connection can still succeed into the buffer;
+		// the request context is what reports the client went away.
+		if err := r.Context().Err(); err != nil {
+			return err
+		}
+		return writeSSEEvent(w, flusher, event.Event, event)
+	})
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return
+		}
+		_ = writeSSEEvent(w, flusher, "error", orchestrator.ChatStreamEvent{
+			SessionID: req.SessionID,
+			Error:     err.Error(),
+		})
+	}
+}
+
+func decodeChatRequest(w http.ResponseWriter, r *http.Request) (orchestrator.ChatRequest, bool) {
 	var req orchestrator.ChatRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		util.WriteJSON(w, http.StatusBadRequest, orchestrator.ErrorResponse{Error: "invalid request body"})
-		return
+		return req, false
 	}
 
 	req.Query = strings.TrimSpace(req.Query)
 	if req.Query == "" {
 		util.WriteJSON(w, http.StatusBadRequest, orchestrator.ErrorResponse{Error: "query is required"})
-		return
+		return req, false
 	}
 
 	req.SessionID = strings.TrimSpace(req.SessionID)
 	if req.SessionID == "" {
 		util.WriteJSON(w, http.StatusBadRequest, orchestrator.ErrorResponse{Error: "SessionID is required"})
-		return
+		return req, false
 	}
 
 	req.Uuid = strings.TrimSpace(req.Uuid)
-	if req.Uuid == "" {
-		util.WriteJSON(w, http.StatusBadRequest, orchestrator.ErrorResponse{Error: "Uuid is required"})
-		return
-	}
-
-	answer, err := H.chatbot.Execute(r.Context(), req.Query, strings.TrimSpace(req.ImagePath), req.SessionID, req.Uuid)
-	if err != nil {
-		status := http.StatusInternalServerError
-		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
-			status = http.StatusRequestTimeout
+	req.Workspace = strings.TrimSpace(req.Workspace)
+	collections := make([]string, 0, len(req.Collections))
+	for _, collection := range req.Collections {
+		if collection = strings.TrimSpace(collection); collection != "" {
+			collections = append(collections, collection)
 		}
-		util.WriteJSON(w, status, orchestrator.ErrorResponse{Error: err.Error()})
-		return
 	}
+	req.Collections = collections
+	if req.Uuid == "" && len(req.Collections) == 0 && req.Workspace == "" {
+		util.WriteJSON(w, http.StatusBadRequest, orchestrator.ErrorResponse{Error: "Uuid, collections or workspace is required"})
+		return req, false
+	}
+	return req, true
+}
 
-	fmt.Printf("TEST: %v\n", answer)
-
-	util.WriteJSON(w, http.StatusOK, orchestrator.ChatResponse{
-		Answer:    answer,
-		SessionID: req.SessionID,
-	})
+func chatCollectionScope(req orchestrator.ChatRequest) chat.CollectionScope {
+	return chat.CollectionScope{
+		Uuid:        req.Uuid,
+		Collections: req.Collections,
+		Workspace:   req.Workspace,
+	}
+}
 
+func writeSSEEvent(w http.ResponseWriter, flusher http.Flusher, event string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data); err != nil {
+		return err
+	}
+	flusher.Flush()
+	return nil
 }
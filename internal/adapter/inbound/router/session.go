@@ -0,0 +1,136 @@
+package router
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	orchestratordto "rag_imagetotext_texttoimage/internal/application/dtos/orchestrator"
+	portsOrchestrator "rag_imagetotext_texttoimage/internal/application/ports/orchestrator"
+	orchestratoruc "rag_imagetotext_texttoimage/internal/application/use_cases/orchestrator"
+	"rag_imagetotext_texttoimage/internal/util"
+)
+
+type HTTPHandlerSession struct {
+	sessions *orchestratoruc.SessionManager
+}
+
+func NewHTTPHandlerSession(sessions *orchestratoruc.SessionManager) *HTTPHandlerSession {
+	return &HTTPHandlerSession{sessions: sessions}
+}
+
+func (h *HTTPHandlerSession) HTTPHandlerListSessionsExecute(w http.ResponseWriter, r *http.Request) {
+	if h == nil || h.sessions == nil {
+		util.WriteJSON(w, http.StatusInternalServerError, orchestratordto.ErrorResponse{Error: "session handler is not configured"})
+		return
+	}
+
+	response, err := h.sessions.ListSessions()
+	if err != nil {
+		writeSessionError(w, err)
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, response)
+}
+
+func (h *HTTPHandlerSession) HTTPHandlerGetSessionHistoryExecute(w http.ResponseWriter, r *http.Request) {
+	if h == nil || h.sessions == nil {
+		util.WriteJSON(w, http.StatusInternalServerError, orchestratordto.ErrorResponse{Error: "session handler is not configured"})
+		return
+	}
+	sessionID, ok := sessionIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := h.sessions.GetSessionHistory(sessionID)
+	if err != nil {
+		writeSessionError(w, err)
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, response)
+}
+
+func (h *HTTPHandlerSession) HTTPHandlerClearSessionHistoryExecute(w http.ResponseWriter, r *http.Request) {
+	if h == nil || h.sessions == nil {
+		util.WriteJSON(w, http.StatusInternalServerError, orchestratordto.ErrorResponse{Error: "session handler is not configured"})
+		return
+	}
+	sessionID, ok := sessionIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.sessions.ClearSessionHistory(sessionID); err != nil {
+		writeSessionError(w, err)
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, orchestratordto.SessionStatusResponse{SessionID: sessionID, Status: true})
+}
+
+func (h *HTTPHandlerSession) HTTPHandlerDeleteSessionExecute(w http.ResponseWriter, r *http.Request) {
+	if h == nil || h.sessions == nil {
+		util.WriteJSON(w, http.StatusInternalServerError, orchestratordto.ErrorResponse{Error: "session handler is not configured"})
+		return
+	}
+	sessionID, ok := sessionIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.sessions.DeleteSession(sessionID); err != nil {
+		writeSessionError(w, err)
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, orchestratordto.SessionStatusResponse{SessionID: sessionID, Status: true})
+}
+
+func (h *HTTPHandlerSession) HTTPHandlerExtendSessionExecute(w http.ResponseWriter, r *http.Request) {
+	if h == nil || h.sessions == nil {
+		util.WriteJSON(w, http.StatusInternalServerError, orchestratordto.ErrorResponse{Error: "session handler is not configured"})
+		return
+	}
+	sessionID, ok := sessionIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req orchestratordto.ExtendSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		util.WriteJSON(w, http.StatusBadRequest, orchestratordto.ErrorResponse{Error: "invalid request body"})
+		return
+	}
+	if req.TTLSeconds < 0 {
+		util.WriteJSON(w, http.StatusBadRequest, orchestratordto.ErrorResponse{Error: "ttl_seconds must be positive"})
+		return
+	}
+
+	expiresAt, err := h.sessions.ExtendSession(sessionID, time.Duration(req.TTLSeconds)*time.Second)
+	if err != nil {
+		writeSessionError(w, err)
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, orchestratordto.SessionStatusResponse{SessionID: sessionID, Status: true, ExpiresAt: &expiresAt})
+}
+
+func sessionIDParam(w http.ResponseWriter, r *http.Request) (string, bool) {
+	sessionID := strings.TrimSpace(chi.URLParam(r, "session_id"))
+	if sessionID == "" {
+		util.WriteJSON(w, http.StatusBadRequest, orchestratordto.ErrorResponse{Error: "session_id is required"})
+		return "", false
+	}
+	return sessionID, true
+}
+
+func writeSessionError(w http.ResponseWriter, err error) {
+	httpStatus := http.StatusInternalServerError
+	if errors.Is(err, portsOrchestrator.ErrSessionNotFound) {
+		httpStatus = http.StatusNotFound
+	}
+	util.WriteJSON(w, httpStatus, orchestratordto.ErrorResponse{Error: err.Error()})
+}
@@ -1,10 +1,12 @@
 package router
 
 import (
-	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
 
 	orchestratordto "rag_imagetotext_texttoimage/internal/application/dtos/orchestrator"
 	"rag_imagetotext_texttoimage/internal/application/ports"
@@ -12,20 +14,22 @@ import (
 )
 
 type HTTPHandlerTrainingFile struct {
-	useCase ports.TrainingFileUseCase
+	jobs ports.IngestJobManager
 }
 
-func NewHTTPHandlerTrainingFile(useCase ports.TrainingFileUseCase) *HTTPHandlerTrainingFile {
+func NewHTTPHandlerTrainingFile(jobs ports.IngestJobManager) *HTTPHandlerTrainingFile {
 	return &HTTPHandlerTrainingFile{
-		useCase: useCase,
+		jobs: jobs,
 	}
 }
 
+// HTTPHandlerProcessAndIngestExecute queues the pipeline and answers 202 with
+// the job; progress is read from GET /api/v1/orchestrator/jobs/{job_id}.
 func (h *HTTPHandlerTrainingFile) HTTPHandlerProcessAndIngestExecute(
 	w http.ResponseWriter,
 	r *http.Request,
 ) {
-	if h == nil || h.useCase == nil {
+	if h == nil || h.jobs == nil {
 		util.WriteJSON(w, http.StatusInternalServerError, orchestratordto.ErrorResponse{Error: "training file handler is not configured"})
 		return
 	}
@@ -37,16 +41,73 @@ func (h *HTTPHandlerTrainingFile) HTTPHandlerProcessAndIngestExecute(
 		util.WriteJSON(w, http.StatusBadRequest, orchestratordto.ErrorResponse{Error: "invalid request body"})
 		return
 	}
+	if strings.TrimSpace(req.URLDownload) == "" {
+		util.WriteJSON(w, http.StatusBadRequest, orchestratordto.ErrorResponse{Error: "url_download is required"})
+		return
+	}
+
+	job, err := h.jobs.Submit(&req)
+	if err != nil {
+		writeIngestJobError(w, err)
+		return
+	}
+	util.WriteJSON(w, http.StatusAccepted, job)
+}
+
+func (h *HTTPHandlerTrainingFile) HTTPHandlerGetIngestJobExecute(w http.ResponseWriter, r *http.Request) {
+	if h == nil || h.jobs == nil {
+		util.WriteJSON(w, http.StatusInternalServerError, orchestratordto.ErrorResponse{Error: "training file handler is not configured"})
+		return
+	}
+	jobID, ok := jobIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	job, err := h.jobs.Get(jobID)
+	if err != nil {
+		writeIngestJobError(w, err)
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, job)
+}
 
-	result, err := h.useCase.ProcessAndIngest(r.Context(), &req)
+func (h *HTTPHandlerTrainingFile) HTTPHandlerCancelIngestJobExecute(w http.ResponseWriter, r *http.Request) {
+	if h == nil || h.jobs == nil {
+		util.WriteJSON(w, http.StatusInternalServerError, orchestratordto.ErrorResponse{Error: "training file handler is not configured"})
+		return
+	}
+	jobID, ok := jobIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	job, err := h.jobs.Cancel(jobID)
 	if err != nil {
-		httpStatus := http.StatusInternalServerError
-		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
-			httpStatus = http.StatusRequestTimeout
-		}
-		util.WriteJSON(w, httpStatus, orchestratordto.ErrorResponse{Error: err.Error()})
+		writeIngestJobError(w, err)
 		return
 	}
+	util.WriteJSON(w, http.StatusAccepted, job)
+}
 
-	util.WriteJSON(w, http.StatusOK, result)
+func jobIDParam(w http.ResponseWriter, r *http.Request) (string, bool) {
+	jobID := strings.TrimSpace(chi.URLParam(r, "job_id"))
+	if jobID == "" {
+		util.WriteJSON(w, http.StatusBadRequest, orchestratordto.ErrorResponse{Error: "job_id is required"})
+		return "", false
+	}
+	return jobID, true
+}
+
+func writeIngestJobError(w http.ResponseWriter, err error) {
+	httpStatus := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, ports.ErrIngestJobNotFound):
+		httpStatus = http.StatusNotFound
+	case errors.Is(err, ports.ErrIngestJobFinished), errors.Is(err, ports.ErrIngestJobConflict):
+		httpStatus = http.StatusConflict
+	case errors.Is(err, ports.ErrIngestQueueFull), errors.Is(err, ports.ErrIngestJobsClosed):
+		httpStatus = http.StatusServiceUnavailable
+	}
+	util.WriteJSON(w, httpStatus, orchestratordto.ErrorResponse{Error: err.Error()})
 }
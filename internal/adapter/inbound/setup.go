@@ -6,6 +6,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"rag_imagetotext_texttoimage/internal/util"
 
@@ -16,17 +17,20 @@ type HTTPHandler struct {
 	chat         *router.HTTPHandlerChat
 	vectordb     *router.HTTPHandlerVectordb
 	trainingFile *router.HTTPHandlerTrainingFile
+	session      *router.HTTPHandlerSession
 }
 
 func NewHTTPHandler(
 	chatHandler *router.HTTPHandlerChat,
 	vectordbHandler *router.HTTPHandlerVectordb,
 	trainingFileHandler *router.HTTPHandlerTrainingFile,
+	sessionHandler *router.HTTPHandlerSession,
 ) *HTTPHandler {
 	return &HTTPHandler{
 		chat:         chatHandler,
 		vectordb:     vectordbHandler,
 		trainingFile: trainingFileHandler,
+		session:      sessionHandler,
 	}
 }
 
@@ -35,46 +39,109 @@ func SetupRouter(handler *HTTPHandler) http.Handler {
 	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(60 * time.Second))
 
-	r.Get("/healthz", func(w http.ResponseWriter, _ *http.Request) {
-		util.WriteJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	// SSE responses outlive the default request timeout, so only the
+	// request/response routes get it.
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.Timeout(60 * time.Second))
+
+		r.Get("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+			util.WriteJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+		})
+		r.Handle("/metrics", promhttp.Handler())
+
+		r.Post("/api/v1/orchestrator/chat", func(w http.ResponseWriter, r *http.Request) {
+			if handler == nil || handler.chat == nil {
+				util.WriteJSON(w, http.StatusInternalServerError, map[string]string{"error": "chat handler is not configured"})
+				return
+			}
+			handler.chat.HTTPHandlerChatExecute(w, r)
+		})
+		r.Post("/api/v1/orchestrator/vectordb/collections", func(w http.ResponseWriter, r *http.Request) {
+			if handler == nil || handler.vectordb == nil {
+				util.WriteJSON(w, http.StatusInternalServerError, map[string]string{"error": "vectordb handler is not configured"})
+				return
+			}
+			handler.vectordb.HTTPHandlerCreateCollectionExecute(w, r)
+		})
+		r.Post("/api/v1/orchestrator/vectordb/collections/delete", func(w http.ResponseWriter, r *http.Request) {
+			if handler == nil || handler.vectordb == nil {
+				util.WriteJSON(w, http.StatusInternalServerError, map[string]string{"error": "vectordb handler is not configured"})
+				return
+			}
+			handler.vectordb.HTTPHandlerDeleteCollectionExecute(w, r)
+		})
+		r.Post("/api/v1/orchestrator/vectordb/points/delete-filter", func(w http.ResponseWriter, r *http.Request) {
+			if handler == nil || handler.vectordb == nil {
+				util.WriteJSON(w, http.StatusInternalServerError, map[string]string{"error": "vectordb handler is not configured"})
+				return
+			}
+			handler.vectordb.HTTPHandlerDeletePointFilterExecute(w, r)
+		})
+		r.Post("/api/v1/orchestrator/training-file/process-and-ingest", func(w http.ResponseWriter, r *http.Request) {
+			if handler == nil || handler.trainingFile == nil {
+				util.WriteJSON(w, http.StatusInternalServerError, map[string]string{"error": "training file handler is not configured"})
+				return
+			}
+			handler.trainingFile.HTTPHandlerProcessAndIngestExecute(w, r)
+		})
+		r.Get("/api/v1/orchestrator/jobs/{job_id}", func(w http.ResponseWriter, r *http.Request) {
+			if handler == nil || handler.trainingFile == nil {
+				util.WriteJSON(w, http.StatusInternalServerError, map[string]string{"error": "training file handler is not configured"})
+				return
+			}
+			handler.trainingFile.HTTPHandlerGetIngestJobExecute(w, r)
+		})
+		r.Delete("/api/v1/orchestrator/jobs/{job_id}", func(w http.ResponseWriter, r *http.Request) {
+			if handler == nil || handler.trainingFile == nil {
+				util.WriteJSON(w, http.StatusInternalServerError, map[string]string{"error": "training file handler is not configured"})
+				return
+			}
+			handler.trainingFile.HTTPHandlerCancelIngestJobExecute(w, r)
+		})
+		r.Get("/api/v1/orchestrator/sessions", func(w http.ResponseWriter, r *http.Request) {
+			if handler == nil || handler.session == nil {
+				util.WriteJSON(w, http.StatusInternalServerError, map[string]string{"error": "session handler is not configured"})
+				return
+			}
+			handler.session.HTTPHandlerListSessionsExecute(w, r)
+		})
+		r.Get("/api/v1/orchestrator/sessions/{session_id}", func(w http.ResponseWriter, r *http.Request) {
+			if handler == nil || handler.session == nil {
+				util.WriteJSON(w, http.StatusInternalServerError, map[string]string{"error": "session handler is not configured"})
+				return
+			}
+			handler.session.HTTPHandlerGetSessionHistoryExecute(w, r)
+		})
+		r.Delete("/api/v1/orchestrator/sessions/{session_id}", func(w http.ResponseWriter, r *http.Request) {
+			if handler == nil || handler.session == nil {
+				util.WriteJSON(w, http.StatusInternalServerError, map[string]string{"error": "session handler is not configured"})
+				return
+			}
+			handler.session.HTTPHandlerDeleteSessionExecute(w, r)
+		})
+		r.Delete("/api/v1/orchestrator/sessions/{session_id}/history", func(w http.ResponseWriter, r *http.Request) {
+			if handler == nil || handler.session == nil {
+				util.WriteJSON(w, http.StatusInternalServerError, map[string]string{"error": "session handler is not configured"})
+				return
+			}
+			handler.session.HTTPHandlerClearSessionHistoryExecute(w, r)
+		})
+		r.Post("/api/v1/orchestrator/sessions/{session_id}/extend", func(w http.ResponseWriter, r *http.Request) {
+			if handler == nil || handler.session == nil {
+				util.WriteJSON(w, http.StatusInternalServerError, map[string]string{"error": "session handler is not configured"})
+				return
+			}
+			handler.session.HTTPHandlerExtendSessionExecute(w, r)
+		})
 	})
 
-	r.Post("/api/v1/orchestrator/chat", func(w http.ResponseWriter, r *http.Request) {
+	r.With(middleware.Timeout(5*time.Minute)).Post("/api/v1/orchestrator/chat/stream", func(w http.ResponseWriter, r *http.Request) {
 		if handler == nil || handler.chat == nil {
 			util.WriteJSON(w, http.StatusInternalServerError, map[string]string{"error": "chat handler is not configured"})
 			return
 		}
-		handler.chat.HTTPHandlerChatExecute(w, r)
-	})
-	r.Post("/api/v1/orchestrator/vectordb/collections", func(w http.ResponseWriter, r *http.Request) {
-		if handler == nil || handler.vectordb == nil {
-			util.WriteJSON(w, http.StatusInternalServerError, map[string]string{"error": "vectordb handler is not configured"})
-			return
-		}
-		handler.vectordb.HTTPHandlerCreateCollectionExecute(w, r)
-	})
-	r.Post("/api/v1/orchestrator/vectordb/collections/delete", func(w http.ResponseWriter, r *http.Request) {
-		if handler == nil || handler.vectordb == nil {
-			util.WriteJSON(w, http.StatusInternalServerError, map[string]string{"error": "vectordb handler is not configured"})
-			return
-		}
-		handler.vectordb.HTTPHandlerDeleteCollectionExecute(w, r)
-	})
-	r.Post("/api/v1/orchestrator/vectordb/points/delete-filter", func(w http.ResponseWriter, r *http.Request) {
-		if handler == nil || handler.vectordb == nil {
-			util.WriteJSON(w, http.StatusInternalServerError, map[string]string{"error": "vectordb handler is not configured"})
-			return
-		}
-		handler.vectordb.HTTPHandlerDeletePointFilterExecute(w, r)
-	})
-	r.Post("/api/v1/orchestrator/training-file/process-and-ingest", func(w http.ResponseWriter, r *http.Request) {
-		if handler == nil || handler.trainingFile == nil {
-			util.WriteJSON(w, http.StatusInternalServerError, map[string]string{"error": "training file handler is not configured"})
-			return
-		}
-		handler.trainingFile.HTTPHandlerProcessAndIngestExecute(w, r)
+		handler.chat.HTTPHandlerChatStreamExecute(w, r)
 	})
 	return r
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
 	"rag_imagetotext_texttoimage/internal/application/ports"
@@ -194,6 +195,41 @@ func (a *ConsumerAdapter) Start(ctx context.Context, topic string, groupID strin
 	return errCh
 }
 
+// StartWithRetry consumes topic and its retry topics with the handler wrapped
+// by retry. Each retry topic gets its own group, derived from groupID. The
+// returned channel yields the first consumer error and is closed once every
+// consumer has stopped.
+func (a *ConsumerAdapter) StartWithRetry(ctx context.Context, topic string, groupID string, handlerName string, handler ports.MessageHandler, retry *RetryMiddleware) <-chan error {
+	if retry == nil {
+		return a.Start(ctx, topic, groupID, handler)
+	}
+	wrapped := retry.Wrap(topic, handlerName, handler)
+	errChs := []<-chan error{a.Start(ctx, topic, groupID, wrapped)}
+	for attempt, retryTopic := range retry.RetryTopics(topic) {
+		errChs = append(errChs, a.Start(ctx, retryTopic, fmt.Sprintf("%s.retry.%d", groupID, attempt+1), wrapped))
+	}
+
+	errCh := make(chan error, 1)
+	var wg sync.WaitGroup
+	for _, ch := range errChs {
+		wg.Add(1)
+		go func(ch <-chan error) {
+			defer wg.Done()
+			for err := range ch {
+				select {
+				case errCh <- err:
+				default:
+				}
+			}
+		}(ch)
+	}
+	go func() {
+		wg.Wait()
+		close(errCh)
+	}()
+	return errCh
+}
+
 func (a *ConsumerAdapter) Close() error {
 	if a == nil || a.consumer == nil {
 		return nil
@@ -0,0 +1,250 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"rag_imagetotext_texttoimage/internal/application/ports"
+	"rag_imagetotext_texttoimage/internal/util"
+)
+
+const (
+	defaultReplyTimeout = 90 * time.Second
+	replyListenerRetry  = 2 * time.Second
+	replyExpiredTTL     = 10 * time.Minute
+)
+
+var errRequestReplyClosed = errors.New("kafka request reply is closed")
+
+// RequestReply dispatches replies to waiting callers by correlation id. The
+// first request on a reply topic starts a consumer for it which then lives
+// until Close; replies nobody waits for any more are logged and dropped.
+type RequestReply struct {
+	publisher ports.KafkaPublisher
+	consumer  ports.KafkaConsumer
+	groupID   string
+	logger    util.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu        sync.Mutex
+	closed    bool
+	listeners map[string]bool
+	pending   map[string]chan ports.ConsumeMessage
+	expired   map[string]time.Time
+}
+
+var _ ports.KafkaRequestReply = (*RequestReply)(nil)
+
+func NewRequestReply(publisher ports.KafkaPublisher, consumer ports.KafkaConsumer, groupID string, appLogger util.Logger) *RequestReply {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &RequestReply{
+		publisher: publisher,
+		consumer:  consumer,
+		groupID:   groupID,
+		logger:    appLogger,
+		ctx:       ctx,
+		cancel:    cancel,
+		listeners: map[string]bool{},
+		pending:   map[string]chan ports.ConsumeMessage{},
+		expired:   map[string]time.Time{},
+	}
+}
+
+// ReplyGroupID names the reply consumer group of one service instance. Every
+// instance needs its own group, otherwise the broker splits the replies
+// between instances; the hostname keeps the name stable across restarts so
+// no orphan groups pile up.
+func ReplyGroupID(service string) string {
+	host, err := os.Hostname()
+	if err != nil || strings.TrimSpace(host) == "" {
+		host = "local"
+	}
+	return service + "-replies-" + host
+}
+
+func (r *RequestReply) Request(ctx context.Context, input ports.PublishMessageInput, replyTopic string, correlationID string, timeout time.Duration) (ports.ConsumeMessage, error) {
+	if r == nil || r.publisher == nil || r.consumer == nil {
+		return ports.ConsumeMessage{}, errors.New("kafka request reply is not initialized")
+	}
+	replyTopic = strings.TrimSpace(replyTopic)
+	correlationID = strings.TrimSpace(correlationID)
+	if replyTopic == "" {
+		return ports.ConsumeMessage{}, errors.New("reply topic is required")
+	}
+	if correlationID == "" {
+		return ports.ConsumeMessage{}, errors.New("correlation_id is required")
+	}
+	if timeout <= 0 {
+		timeout = defaultReplyTimeout
+	}
+
+	replyCh := make(chan ports.ConsumeMessage, 1)
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return ports.ConsumeMessage{}, errRequestReplyClosed
+	}
+	if _, exists := r.pending[correlationID]; exists {
+		r.mu.Unlock()
+		return ports.ConsumeMessage{}, fmt.Errorf("request with correlation_id=%s is already pending", correlationID)
+	}
+	r.pending[correlationID] = replyCh
+	if !r.listeners[replyTopic] {
+		r.listeners[replyTopic] = true
+		r.wg.Add(1)
+		go r.listen(replyTopic)
+	}
+	r.mu.Unlock()
+
+	if err := r.publisher.Publish(ctx, input); err != nil {
+		r.forget(correlationID, false)
+		return ports.ConsumeMessage{}, fmt.Errorf("publish to topic %s: %w", input.Topic, err)
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case msg := <-replyCh:
+		return msg, nil
+	case <-timer.C:
+		if msg, ok := r.forget(correlationID, true); ok {
+			return msg, nil
+		}
+		return ports.ConsumeMessage{}, fmt.Errorf("%w: correlation_id=%s topic=%s after %s", ports.ErrReplyTimeout, correlationID, replyTopic, timeout)
+	case <-ctx.Done():
+		r.forget(correlationID, true)
+		return ports.ConsumeMessage{}, ctx.Err()
+	case <-r.ctx.Done():
+		r.forget(correlationID, false)
+		return ports.ConsumeMessage{}, errRequestReplyClosed
+	}
+}
+
+// forget removes a pending request. A reply that was delivered in the
+// meantime is returned so it is not lost; otherwise the id is remembered as
+// expired when the reply may still arrive.
+func (r *RequestReply) forget(correlationID string, expired bool) (ports.ConsumeMessage, bool) {
+	r.mu.Lock()
+	replyCh := r.pending[correlationID]
+	delete(r.pending, correlationID)
+	if expired {
+		now := time.Now()
+		for id, at := range r.expired {
+			if now.Sub(at) > replyExpiredTTL {
+				delete(r.expired, id)
+			}
+		}
+		r.expired[correlationID] = now
+	}
+	r.mu.Unlock()
+
+	select {
+	case msg := <-replyCh:
+		return msg, true
+	default:
+		return ports.ConsumeMessage{}, false
+	}
+}
+
+func (r *RequestReply) listen(topic string) {
+	defer r.wg.Done()
+	if r.logger != nil {
+		r.logger.Info("kafka reply listener start", "topic", topic, "group_id", r.groupID)
+	}
+	for {
+		err := r.consumer.Consume(r.ctx, topic, r.groupID, func(ctx context.Context, msg ports.ConsumeMessage) error {
+			r.dispatch(msg)
+			return nil
+		})
+		if r.ctx.Err() != nil {
+			if r.logger != nil {
+				r.logger.Info("kafka reply listener stopped", "topic", topic, "group_id", r.groupID)
+			}
+			return
+		}
+		if err != nil && r.logger != nil {
+			r.logger.Error("kafka reply listener failed, restarting", err, "topic", topic, "group_id", r.groupID)
+		}
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-time.After(replyListenerRetry):
+		}
+	}
+}
+
+func (r *RequestReply) dispatch(msg ports.ConsumeMessage) {
+	correlationID := replyCorrelationID(msg)
+	r.mu.Lock()
+	replyCh, ok := r.pending[correlationID]
+	if ok {
+		delete(r.pending, correlationID)
+	}
+	_, late := r.expired[correlationID]
+	if late {
+		delete(r.expired, correlationID)
+	}
+	r.mu.Unlock()
+
+	switch {
+	case ok:
+		replyCh <- msg
+	case late:
+		if r.logger != nil {
+			r.logger.Info("kafka late reply dropped", "topic", msg.Topic, "offset", msg.Offset, "correlation_id", correlationID)
+		}
+	default:
+		if r.logger != nil {
+			r.logger.Debug("kafka unknown reply dropped", "topic", msg.Topic, "offset", msg.Offset, "correlation_id", correlationID)
+		}
+	}
+}
+
+// replyCorrelationID reads the correlation id from the headers, then the
+// message key, then the JSON body.
+func replyCorrelationID(msg ports.ConsumeMessage) string {
+	if cid := strings.TrimSpace(msg.Message.Headers["correlation_id"]); cid != "" {
+		return cid
+	}
+	if cid := strings.TrimSpace(string(msg.Message.Key)); cid != "" {
+		return cid
+	}
+	var body struct {
+		CorrelationID string `json:"correlation_id"`
+	}
+	if err := json.Unmarshal(msg.Message.Value, &body); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(body.CorrelationID)
+}
+
+// Close stops the reply listeners and fails the requests still waiting. The
+// consumer itself is owned by the caller.
+func (r *RequestReply) Close() error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	r.closed = true
+	r.mu.Unlock()
+
+	r.cancel()
+	r.wg.Wait()
+	if r.logger != nil {
+		r.logger.Info("kafka request reply closed", "group_id", r.groupID)
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"rag_imagetotext_texttoimage/internal/application/ports"
+	"rag_imagetotext_texttoimage/internal/infra/monitoring"
+	"rag_imagetotext_texttoimage/internal/util"
+)
+
+const (
+	HeaderRetryAttempt      = "x-retry-attempt"
+	HeaderRetryNotBefore    = "x-retry-not-before"
+	HeaderOriginalTopic     = "x-original-topic"
+	HeaderOriginalPartition = "x-original-partition"
+	HeaderOriginalOffset    = "x-original-offset"
+	HeaderError             = "x-error"
+	HeaderErrorClass        = "x-error-class"
+	HeaderFailedAt          = "x-failed-at"
+
+	errorClassRetryable = "retryable"
+	errorClassPermanent = "permanent"
+	errorClassExhausted = "exhausted"
+
+	defaultMaxRetries     = 3
+	defaultInitialBackoff = time.Second
+	defaultMaxBackoff     = 30 * time.Second
+)
+
+// RetryTopic is the topic holding the attempt-th retry of messages from
+// topic. Every attempt has its own topic so all messages in it share one
+// delay and can be consumed in order.
+func RetryTopic(topic string, attempt int) string {
+	return fmt.Sprintf("%s.retry.%d", topic, attempt)
+}
+
+func DLQTopic(topic string) string {
+	return topic + ".dlq"
+}
+
+type RetryPolicy struct {
+	MaxRetries     int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+func RetryPolicyFromConfig(cfg util.KafkaRetrySettings) RetryPolicy {
+	policy := RetryPolicy{
+		MaxRetries:     cfg.MaxRetries,
+		InitialBackoff: time.Duration(cfg.InitialBackoffMs) * time.Millisecond,
+		MaxBackoff:     time.Duration(cfg.MaxBackoffMs) * time.Millisecond,
+	}
+	if policy.MaxRetries < 0 {
+		policy.MaxRetries = defaultMaxRetries
+	}
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = defaultInitialBackoff
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = defaultMaxBackoff
+	}
+	return policy
+}
+
+// Backoff is the delay before the attempt-th retry: InitialBackoff doubled
+// per attempt, capped at MaxBackoff.
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	backoff := p.InitialBackoff
+	for i := 1; i < attempt && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, p.MaxBackoff)
+}
+
+type retryStateKey struct{}
+
+type retryState struct {
+	attempt    int
+	maxRetries int
+}
+
+// FinalAttempt reports whether a failure of the current delivery goes to the
+// dead-letter topic rather than to a retry topic. Handlers use it to publish
+// a failed result only once. Outside the retry middleware every delivery is
+// final.
+func FinalAttempt(ctx context.Context) bool {
+	state, ok := ctx.Value(retryStateKey{}).(retryState)
+	return !ok || state.attempt >= state.maxRetries
+}
+
+// RetryMiddleware retries failed messages through the retry topics of their
+// source topic and moves permanent or exhausted failures to <topic>.dlq, with
+// the error in the headers.
+type RetryMiddleware struct {
+	producer *ProducerAdapter
+	policy   RetryPolicy
+	metrics  *monitoring.Metrics
+	logger   util.Logger
+}
+
+func NewRetryMiddleware(producer *ProducerAdapter, policy RetryPolicy, metrics *monitoring.Metrics, appLogger util.Logger) *RetryMiddleware {
+	return &RetryMiddleware{
+		producer: producer,
+		policy:   policy,
+		metrics:  metrics,
+		logger:   appLogger,
+	}
+}
+
+func (m *RetryMiddleware) RetryTopics(topic string) []string {
+	topics := make([]string, 0, m.policy.MaxRetries)
+	for attempt := 1; attempt <= m.policy.MaxRetries; attempt++ {
+		topics = append(topics, RetryTopic(topic, attempt))
+	}
+	return topics
+}
+
+// Wrap returns the handler to consume topic and its retry topics with. A
+// retried message waits until its x-retry-not-before time before it is
+// handled again.
+func (m *RetryMiddleware) Wrap(topic string, handlerName string, handler ports.MessageHandler) ports.MessageHandler {
+	return func(ctx context.Context, msg ports.ConsumeMessage) error {
+		attempt := headerInt(msg.Message.Headers, HeaderRetryAttempt)
+		if err := waitRetryNotBefore(ctx, msg.Message.Headers); err != nil {
+			return err
+		}
+
+		handlerCtx := context.WithValue(ctx, retryStateKey{}, retryState{attempt: attempt, maxRetries: m.policy.MaxRetries})
+		err := handler(handlerCtx, msg)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			// Shutting down: leave the message uncommitted so it is redelivered.
+			return ctx.Err()
+		}
+		return m.route(ctx, topic, handlerName, msg, attempt, err)
+	}
+}
+
+func (m *RetryMiddleware) route(ctx context.Context, topic string, handlerName string, msg ports.ConsumeMessage, attempt int, handlerErr error) error {
+	next := attempt + 1
+	class := errorClassRetryable
+	target := RetryTopic(topic, next)
+	switch {
+	case ports.IsPermanent(handlerErr):
+		class = errorClassPermanent
+		target = DLQTopic(topic)
+	case next > m.policy.MaxRetries:
+		class = errorClassExhausted
+		target = DLQTopic(topic)
+	}
+
+	headers := make(map[string]string, len(msg.Message.Headers)+8)
+	for k, v := range msg.Message.Headers {
+		headers[k] = v
+	}
+	if headers[HeaderOriginalTopic] == "" {
+		headers[HeaderOriginalTopic] = topic
+		headers[HeaderOriginalPartition] = strconv.Itoa(msg.Partition)
+		headers[HeaderOriginalOffset] = strconv.FormatInt(msg.Offset, 10)
+	}
+	headers[HeaderError] = handlerErr.Error()
+	headers[HeaderErrorClass] = class
+	headers[HeaderFailedAt] = time.Now().UTC().Format(time.RFC3339)
+	if class == errorClassRetryable {
+		headers[HeaderRetryAttempt] = strconv.Itoa(next)
+		headers[HeaderRetryNotBefore] = strconv.FormatInt(time.Now().Add(m.policy.Backoff(next)).UnixMilli(), 10)
+	} else {
+		headers[HeaderRetryAttempt] = strconv.Itoa(attempt)
+		delete(headers, HeaderRetryNotBefore)
+	}
+
+	if err := m.producer.Publish(ctx, target, msg.Message.Key, msg.Message.Value, headers); err != nil {
+		if m.logger != nil {
+			m.logger.Error("kafka retry publish failed", err, "topic", topic, "target_topic", target, "offset", msg.Offset)
+		}
+		return fmt.Errorf("publish failed message to %s: %w", target, errors.Join(err, handlerErr))
+	}
+
+	if class == errorClassRetryable {
+		if m.metrics != nil {
+			m.metrics.RetryTotal.WithLabelValues(topic, handlerName, class).Inc()
+		}
+		if m.logger != nil {
+			m.logger.Info("kafka message scheduled for retry", "topic", topic, "handler", handlerName, "retry_topic", target, "attempt", next, "backoff_ms", m.policy.Backoff(next).Milliseconds(), "error", handlerErr.Error())
+		}
+		return nil
+	}
+	if m.metrics != nil {
+		m.metrics.DeadLetter.WithLabelValues(topic, handlerName, class).Inc()
+	}
+	if m.logger != nil {
+		m.logger.Error("kafka message moved to dead letter topic", handlerErr, "topic", topic, "handler", handlerName, "dlq_topic", target, "reason", class, "attempt", attempt)
+	}
+	return nil
+}
+
+func waitRetryNotBefore(ctx context.Context, headers map[string]string) error {
+	notBefore := headerInt(headers, HeaderRetryNotBefore)
+	if notBefore <= 0 {
+		return nil
+	}
+	wait := time.Until(time.UnixMilli(int64(notBefore)))
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func headerInt(headers map[string]string, key string) int {
+	v, err := strconv.Atoi(strings.TrimSpace(headers[key]))
+	if err != nil || v < 0 {
+		return 0
+	}
+	return v
+}
@@ -11,7 +11,8 @@ type EmbedTextResponse struct {
 }
 
 type EmbedBatchTextRequest struct {
-	Texts []string `json:"texts" validate:"required"`
+	CorrelationID string   `json:"correlation_id,omitempty"`
+	Texts         []string `json:"texts" validate:"required"`
 }
 
 type EmbedBatchTextResponse struct {
@@ -34,10 +35,11 @@ type EmbedImageResponse struct {
 }
 
 type EmbedBatchImageRequest struct {
-	Images   [][]byte `json:"images" validate:"required"`
-	Width    int      `json:"width" validate:"required"`
-	Height   int      `json:"height" validate:"required"`
-	Channels int      `json:"channels" validate:"required"`
+	CorrelationID string   `json:"correlation_id,omitempty"`
+	Images        [][]byte `json:"images" validate:"required"`
+	Width         int      `json:"width" validate:"required"`
+	Height        int      `json:"height" validate:"required"`
+	Channels      int      `json:"channels" validate:"required"`
 }
 
 type EmbedBatchImageResponse struct {
@@ -45,3 +47,42 @@ type EmbedBatchImageResponse struct {
 	Dimension  int         `json:"dimension"`
 	Status     bool        `json:"status"`
 }
+
+// EmbeddingResultSchemaVersion is the version of EmbedBatchResult. Version 1
+// was the unversioned {status, message, count, dimension, embeddings} body.
+const EmbeddingResultSchemaVersion = 2
+
+const (
+	EmbeddingStatusSuccess = "success"
+	EmbeddingStatusPartial = "partial"
+	EmbeddingStatusFailed  = "failed"
+
+	EmbeddingModeBatch   = "batch"
+	EmbeddingModePerItem = "per_item"
+)
+
+// EmbedBatchResult is published on the batch text and batch image result
+// topics. It echoes the correlation id, the requesting service and the request
+// headers, and carries one item per requested text or image in request order.
+// Mode is per_item when the batch call failed and every item was embedded on
+// its own.
+type EmbedBatchResult struct {
+	SchemaVersion  int                    `json:"schema_version"`
+	CorrelationID  string                 `json:"correlation_id"`
+	RequestedBy    string                 `json:"requested_by,omitempty"`
+	RequestHeaders map[string]string      `json:"request_headers,omitempty"`
+	Status         string                 `json:"status"`
+	Message        string                 `json:"message"`
+	Mode           string                 `json:"mode,omitempty"`
+	Count          int                    `json:"count"`
+	Failed         int                    `json:"failed"`
+	Dimension      int                    `json:"dimension"`
+	Items          []EmbedBatchItemResult `json:"items"`
+	At             string                 `json:"at"`
+}
+
+type EmbedBatchItemResult struct {
+	Index     int       `json:"index"`
+	Embedding []float32 `json:"embedding,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
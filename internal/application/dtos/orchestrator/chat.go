@@ -1,13 +1,77 @@
 package orchestrator
 
 type ChatRequest struct {
-	Query     string `json:"query"`
-	ImagePath string `json:"image_path,omitempty"`
-	SessionID string `json:"session_id,omitempty"`
-	Uuid string `json:"Uuid,omitempty"`
+	Query       string   `json:"query"`
+	ImagePath   string   `json:"image_path,omitempty"`
+	SessionID   string   `json:"session_id,omitempty"`
+	Uuid        string   `json:"Uuid,omitempty"`
+	Collections []string `json:"collections,omitempty"`
+	Workspace   string   `json:"workspace,omitempty"`
 }
 
 type ChatResponse struct {
-	Answer    string `json:"answer"`
-	SessionID string `json:"session_id"`
-}
\ No newline at end of file
+	Answer    string             `json:"answer"`
+	SessionID string             `json:"session_id"`
+	Intent    string             `json:"intent,omitempty"`
+	Sources   []ChatSource       `json:"sources"`
+	Context   *ChatContextReport `json:"context,omitempty"`
+}
+
+// ChatContextReport describes how the answer context was assembled: which
+// chunks made it into the prompt and which were dropped, and why.
+type ChatContextReport struct {
+	Stage       string             `json:"stage"`
+	TokenBudget int                `json:"token_budget"`
+	TokensUsed  int                `json:"tokens_used"`
+	Included    []ChatContextChunk `json:"included"`
+	Truncated   []ChatContextChunk `json:"truncated,omitempty"`
+}
+
+type ChatContextChunk struct {
+	Index      int     `json:"index,omitempty"`
+	DocID      string  `json:"doc_id"`
+	Collection string  `json:"collection,omitempty"`
+	ChunkIndex int     `json:"chunk_index"`
+	Score      float32 `json:"score"`
+	Tokens     int     `json:"tokens"`
+	Reason     string  `json:"reason,omitempty"`
+}
+
+type ChatRetrievalScores struct {
+	NewQuery        float32 `json:"new_query"`
+	CurrentQuery    float32 `json:"current_query"`
+	MultimodalQuery float32 `json:"multimodal_query"`
+}
+
+// ChatStreamEvent is one SSE event of the chat stream. Answer is only set on
+// the final event; it is the postprocessed answer and replaces the text
+// streamed in the delta events.
+type ChatStreamEvent struct {
+	Event         string               `json:"-"`
+	Stage         string               `json:"stage,omitempty"`
+	Delta         string               `json:"delta,omitempty"`
+	Answer        string               `json:"answer,omitempty"`
+	Sources       []ChatSource         `json:"sources,omitempty"`
+	SessionID     string               `json:"session_id,omitempty"`
+	CurrentQuery  string               `json:"current_query,omitempty"`
+	NewQuery      string               `json:"new_query,omitempty"`
+	Intent        string               `json:"intent,omitempty"`
+	SkipRetrieval *bool                `json:"skip_retrieval,omitempty"`
+	ContextSource string               `json:"context_source,omitempty"`
+	Scores        *ChatRetrievalScores `json:"scores,omitempty"`
+	Context       *ChatContextReport   `json:"context,omitempty"`
+	Error         string               `json:"error,omitempty"`
+}
+
+type ChatSource struct {
+	Index         int      `json:"index"`
+	DocID         string   `json:"doc_id"`
+	Collection    string   `json:"collection,omitempty"`
+	ChunkIndex    int      `json:"chunk_index"`
+	Page          int      `json:"page"`
+	SectionTitle  string   `json:"section_title,omitempty"`
+	Score         float32  `json:"score"`
+	ContextSource string   `json:"context_source"`
+	ImagePath     string   `json:"image_path,omitempty"`
+	ImageURLs     []string `json:"image_urls,omitempty"`
+}
@@ -0,0 +1,37 @@
+package orchestrator
+
+import "time"
+
+type SessionInfo struct {
+	SessionID    string    `json:"session_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	MessageCount int       `json:"message_count"`
+	Collection   string    `json:"collection,omitempty"`
+	Summary      string    `json:"summary,omitempty"`
+}
+
+type ListSessionsResponse struct {
+	Sessions []SessionInfo `json:"sessions"`
+	Total    int           `json:"total"`
+}
+
+type SessionMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type SessionHistoryResponse struct {
+	SessionInfo
+	Messages []SessionMessage `json:"messages"`
+}
+
+type ExtendSessionRequest struct {
+	TTLSeconds int `json:"ttl_seconds,omitempty"`
+}
+
+type SessionStatusResponse struct {
+	SessionID string     `json:"session_id"`
+	Status    bool       `json:"status"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
@@ -4,3 +4,4 @@ import "rag_imagetotext_texttoimage/internal/application/dtos"
 
 type ProcessAndIngestRequest = dtos.ProcessAndIngestRequest
 type ProcessAndIngestResponse = dtos.ProcessAndIngestResult
+type IngestJobResponse = dtos.IngestJob
@@ -1,5 +1,7 @@
 package dtos
 
+import "time"
+
 type DownFileTrainingRequest struct {
 	UrlDownFile string `json:"url_down_file"`
 	Uuid        string `json:"uuid"`
@@ -19,7 +21,23 @@ type AnalysisFileRequest struct {
 }
 
 type AnalysisFileResult struct {
-	Success bool `json:"success"`
+	Success      bool   `json:"success"`
+	Parser       string `json:"parser,omitempty"`
+	MarkdownPath string `json:"markdown_path,omitempty"`
+}
+
+// ParseDocumentRequest.Dev lets parsers that need heavy tooling (marker) skip
+// the work; native parsers ignore it.
+type ParseDocumentRequest struct {
+	FilePath  string `json:"file_path"`
+	OutputDir string `json:"output_dir"`
+	Dev       bool   `json:"dev"`
+}
+
+type ParseDocumentResult struct {
+	Parser       string   `json:"parser"`
+	MarkdownPath string   `json:"markdown_path,omitempty"`
+	ImagePaths   []string `json:"image_paths,omitempty"`
 }
 
 type TrainingEmbeddingBatchTextRequest struct {
@@ -63,22 +81,76 @@ type UploadVectorDBResult struct {
 	LatencyMs      int64 `json:"latency_ms"`
 }
 
+// ProcessAndIngestRequest.Resume continues from the first step the last run of
+// the uuid did not finish; Force discards earlier state and starts over, even
+// when the downloaded document is unchanged.
 type ProcessAndIngestRequest struct {
 	UUID           string `json:"uuid"`
 	URLDownload    string `json:"url_download"`
 	Lang           string `json:"lang,omitempty"`
 	TimeoutSeconds int    `json:"timeout_seconds,omitempty"`
+	Resume         bool   `json:"resume,omitempty"`
+	Force          bool   `json:"force,omitempty"`
 }
 
 type ProcessAndIngestResult struct {
-	Success        bool   `json:"success"`
-	UUID           string `json:"uuid"`
-	DownloadPath   string `json:"download_path,omitempty"`
-	ProcessDir     string `json:"process_dir,omitempty"`
-	MarkdownPath   string `json:"markdown_path,omitempty"`
-	UploadedFiles  int    `json:"uploaded_files"`
-	InsertedPoints int    `json:"inserted_points"`
-	SkippedPoints  int    `json:"skipped_points"`
-	Verified       bool   `json:"verified"`
-	LatencyMs      int64  `json:"latency_ms"`
+	Success        bool                    `json:"success"`
+	UUID           string                  `json:"uuid"`
+	DownloadPath   string                  `json:"download_path,omitempty"`
+	ProcessDir     string                  `json:"process_dir,omitempty"`
+	MarkdownPath   string                  `json:"markdown_path,omitempty"`
+	UploadedFiles  int                     `json:"uploaded_files"`
+	InsertedPoints int                     `json:"inserted_points"`
+	SkippedPoints  int                     `json:"skipped_points"`
+	Verified       bool                    `json:"verified"`
+	Unchanged      bool                    `json:"unchanged,omitempty"`
+	ResumedSteps   []string                `json:"resumed_steps,omitempty"`
+	FailedImages   []ImageEmbeddingFailure `json:"failed_images,omitempty"`
+	LatencyMs      int64                   `json:"latency_ms"`
+}
+
+// ImageEmbeddingFailure is a figure that was left out of the index because
+// its image could not be embedded.
+type ImageEmbeddingFailure struct {
+	ImagePath string `json:"image_path"`
+	Reason    string `json:"reason"`
+}
+
+const (
+	IngestJobQueued    = "queued"
+	IngestJobRunning   = "running"
+	IngestJobSucceeded = "succeeded"
+	IngestJobFailed    = "failed"
+	IngestJobCanceled  = "canceled"
+)
+
+const (
+	IngestStepPending   = "pending"
+	IngestStepRunning   = "running"
+	IngestStepCompleted = "completed"
+	IngestStepFailed    = "failed"
+	IngestStepCanceled  = "canceled"
+	IngestStepSkipped   = "skipped"
+)
+
+type IngestJobStep struct {
+	Name       string         `json:"name"`
+	Status     string         `json:"status"`
+	StartedAt  *time.Time     `json:"started_at,omitempty"`
+	FinishedAt *time.Time     `json:"finished_at,omitempty"`
+	LatencyMs  int64          `json:"latency_ms"`
+	Counts     map[string]int `json:"counts,omitempty"`
+	Error      string         `json:"error,omitempty"`
+}
+
+type IngestJob struct {
+	JobID      string                  `json:"job_id"`
+	Status     string                  `json:"status"`
+	UUID       string                  `json:"uuid"`
+	CreatedAt  time.Time               `json:"created_at"`
+	StartedAt  *time.Time              `json:"started_at,omitempty"`
+	FinishedAt *time.Time              `json:"finished_at,omitempty"`
+	Steps      []IngestJobStep         `json:"steps"`
+	Result     *ProcessAndIngestResult `json:"result,omitempty"`
+	Error      string                  `json:"error,omitempty"`
 }
@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"rag_imagetotext_texttoimage/internal/application/dtos"
+)
+
+// DocumentParser turns a source document into a markdown file plus the image
+// files it references, written under req.OutputDir/<file name without ext>/.
+type DocumentParser interface {
+	Name() string
+	Parse(ctx context.Context, req dtos.ParseDocumentRequest) (dtos.ParseDocumentResult, error)
+}
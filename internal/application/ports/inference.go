@@ -1,6 +1,10 @@
 package ports
 
+import "errors"
 
+// ErrRerankerNotConfigured is returned by Reranker implementations when no
+// reranking model was loaded.
+var ErrRerankerNotConfigured = errors.New("reranker is not configured")
 
 type InferenceResponse struct {
 	Embedding []float32
@@ -14,3 +18,9 @@ type Inference interface {
 	EmbedBatchImage(images [][]byte, width, height, channels int) ([][]float32, error)
 	Close()
 }
+
+// Reranker scores (query, document) pairs; higher scores are more relevant.
+// Scores are returned in the same order as documents.
+type Reranker interface {
+	Rerank(query string, documents []string) ([]float32, error)
+}
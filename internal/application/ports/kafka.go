@@ -1,6 +1,16 @@
 package ports
 
-import "context"
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrReplyTimeout = errors.New("kafka reply timeout")
+
+// ErrStopConsuming is returned by a handler that is done with the message and
+// wants Consume to stop: the message is committed and Consume returns nil.
+var ErrStopConsuming = errors.New("stop consuming")
 
 type KafkaMessage struct {
 	Key     []byte
@@ -23,6 +33,32 @@ type ConsumeMessage struct {
 
 type MessageHandler func(ctx context.Context, msg ConsumeMessage) error
 
+// PermanentError marks a handler error that retrying cannot fix, such as an
+// invalid payload. Any other handler error is treated as retryable.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+func IsPermanent(err error) bool {
+	var permanent *PermanentError
+	return errors.As(err, &permanent)
+}
+
 type KafkaPublisher interface {
 	Publish(ctx context.Context, input PublishMessageInput) error
 	Close() error
@@ -32,3 +68,11 @@ type KafkaConsumer interface {
 	Consume(ctx context.Context, topic string, groupID string, handler MessageHandler) error
 	Close() error
 }
+
+// KafkaRequestReply publishes a request and waits for the message carrying the
+// same correlation id on replyTopic. Replies are read by one long-lived
+// consumer per reply topic, shared by all callers.
+type KafkaRequestReply interface {
+	Request(ctx context.Context, input PublishMessageInput, replyTopic string, correlationID string, timeout time.Duration) (ConsumeMessage, error)
+	Close() error
+}
@@ -1,5 +1,7 @@
 package ports
 
+import "context"
+
 type LLMResponse struct {
 	Text string
 	JSON map[string]any
@@ -10,7 +12,13 @@ type ChatHistory struct {
 	Content string
 }
 
+// LLMStreamHandler receives each text delta as it is generated. Returning an
+// error aborts the stream.
+type LLMStreamHandler func(delta string) error
+
 type LLM interface {
 	GenerateTextToText(model string, temp float32, prompt string, history []ChatHistory, structureOutput map[string]any) (*LLMResponse, error)
 	GenerateTextToImage(model string, temp float32, imagePath string, prompt string, history []ChatHistory, structureOutput map[string]any) (*LLMResponse, error)
+	GenerateTextToTextStream(ctx context.Context, model string, temp float32, prompt string, history []ChatHistory, onDelta LLMStreamHandler) (*LLMResponse, error)
+	GenerateTextToImageStream(ctx context.Context, model string, temp float32, imagePath string, prompt string, history []ChatHistory, onDelta LLMStreamHandler) (*LLMResponse, error)
 }
@@ -5,3 +5,8 @@ type MetricsInstrumentor interface {
 	RecordModelInferenceTime(modelName string, duration float64)
 }
 
+// ChatMetrics records how chat requests are routed by intent.
+type ChatMetrics interface {
+	RecordChatIntent(intent string, source string)
+	RecordChatPipeline(intent string, status string, duration float64)
+}
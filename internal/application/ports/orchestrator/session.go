@@ -1,10 +1,15 @@
 package orchestrator
 
-import "time"
+import (
+	"errors"
+	"time"
+)
+
+var ErrSessionNotFound = errors.New("session not found")
 
 type ChatMessage struct {
-	Role string
-	Content string
+	Role    string `json:"role"`
+	Content string `json:"content"`
 }
 
 type ChatMessageManager interface {
@@ -14,7 +19,16 @@ type ChatMessageManager interface {
 }
 
 type Metadata struct {
-	CreatedAt time.Time
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Collection string    `json:"collection,omitempty"`
+	// Summary is a running LLM summary of the first SummarizedMessages
+	// messages of the chat history.
+	Summary            string `json:"summary,omitempty"`
+	SummarizedMessages int    `json:"summarized_messages,omitempty"`
+	// HistoryGeneration is bumped every time the chat history is cleared, so
+	// a summary built from the old history can tell it is stale.
+	HistoryGeneration int `json:"history_generation,omitempty"`
 }
 
 type SessionData struct {
@@ -23,9 +37,30 @@ type SessionData struct {
 	Metadata    Metadata
 }
 
+type SessionSummary struct {
+	SessionID    string
+	Metadata     Metadata
+	MessageCount int
+}
+
 type SessionStore interface {
 	CreateSession(sessionID string) error
 	GetSession(sessionID string) (SessionData, error)
 	DeleteSession(sessionID string) error
 	SessionExists(sessionID string) (bool, error)
-}
\ No newline at end of file
+}
+
+// ManagedSessionStore is a SessionStore that expires sessions on its own and
+// reports every session it releases (expiry or delete) to the callback.
+type ManagedSessionStore interface {
+	SessionStore
+	// ListSessions returns the live sessions without refreshing their TTL.
+	ListSessions() ([]SessionSummary, error)
+	// ExtendSession moves the expiry to now+ttl and returns it.
+	ExtendSession(sessionID string, ttl time.Duration) (time.Time, error)
+	// UpdateMetadata applies update to the stored metadata. CreatedAt and
+	// ExpiresAt are owned by the store and changes to them are ignored.
+	UpdateMetadata(sessionID string, update func(metadata *Metadata)) error
+	SetOnSessionReleased(callback func(sessionID string))
+	Close()
+}
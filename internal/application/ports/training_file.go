@@ -2,10 +2,19 @@ package ports
 
 import (
 	"context"
+	"errors"
 
 	"rag_imagetotext_texttoimage/internal/application/dtos"
 )
 
+var (
+	ErrIngestJobNotFound = errors.New("ingest job not found")
+	ErrIngestJobFinished = errors.New("ingest job already finished")
+	ErrIngestJobConflict = errors.New("an ingest job for this uuid is already active")
+	ErrIngestQueueFull   = errors.New("ingest job queue is full")
+	ErrIngestJobsClosed  = errors.New("ingest job manager is closed")
+)
+
 type TrainingFileUseCase interface {
 	Download(ctx context.Context, req *dtos.DownFileTrainingRequest) (dtos.DownFileTrainingResult, error)
 	AnalysisFile(ctx context.Context, req *dtos.AnalysisFileRequest) (dtos.AnalysisFileResult, error)
@@ -15,3 +24,12 @@ type TrainingFileUseCase interface {
 	ProcessAndIngest(ctx context.Context, req *dtos.ProcessAndIngestRequest) (dtos.ProcessAndIngestResult, error)
 	DoSemanticChunking(ctx context.Context, emb [][]float32, threshold float32) error
 }
+
+// IngestJobManager runs ProcessAndIngest in the background. Jobs are kept in
+// memory, so they do not survive a restart.
+type IngestJobManager interface {
+	Submit(req *dtos.ProcessAndIngestRequest) (dtos.IngestJob, error)
+	Get(jobID string) (dtos.IngestJob, error)
+	Cancel(jobID string) (dtos.IngestJob, error)
+	Close()
+}
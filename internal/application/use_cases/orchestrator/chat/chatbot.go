@@ -5,35 +5,43 @@ import (
 	"errors"
 	"strings"
 	"sync"
+	"time"
 
+	dtoOrchestrator "rag_imagetotext_texttoimage/internal/application/dtos/orchestrator"
+	"rag_imagetotext_texttoimage/internal/application/ports/monitoring"
 	portsOrchestrator "rag_imagetotext_texttoimage/internal/application/ports/orchestrator"
-	"rag_imagetotext_texttoimage/internal/application/use_cases/orchestrator"
 	"rag_imagetotext_texttoimage/internal/util"
 	pb "rag_imagetotext_texttoimage/proto"
 )
 
 type ChatbotHandler struct {
-	Session              *orchestrator.InMemorySessionStore
+	Session              portsOrchestrator.ManagedSessionStore
 	appLogger            util.Logger
 	Config               util.Config
 	RagServiceClient     pb.RagServiceClient
 	ModelDLServiceClient pb.DeepLearningServiceClient
 	LLMServiceClient     pb.LlmServiceClient
+	MinioServiceClient   pb.MinioServiceClient
 	PromptPreprocessing  string
 	PromptPostprocessing string
 	PromptAnswer         string
+	metrics              monitoring.ChatMetrics
+
+	summarizing sync.Map
 }
 
 func NewChatbotHandler(
-	session *orchestrator.InMemorySessionStore,
+	session portsOrchestrator.ManagedSessionStore,
 	appLogger util.Logger,
 	config util.Config,
 	ragClient pb.RagServiceClient,
 	modelDLClient pb.DeepLearningServiceClient,
 	llmClient pb.LlmServiceClient,
+	minioClient pb.MinioServiceClient,
 	PromptPreprocessing string,
 	PromptPostprocessing string,
 	PromptAnswer string,
+	metrics monitoring.ChatMetrics,
 ) *ChatbotHandler {
 	handler := &ChatbotHandler{
 		Session:              session,
@@ -42,9 +50,11 @@ func NewChatbotHandler(
 		RagServiceClient:     ragClient,
 		ModelDLServiceClient: modelDLClient,
 		LLMServiceClient:     llmClient,
+		MinioServiceClient:   minioClient,
 		PromptPreprocessing:  PromptPreprocessing,
 		PromptPostprocessing: PromptPostprocessing,
 		PromptAnswer:         PromptAnswer,
+		metrics:              metrics,
 	}
 	if session != nil {
 		session.SetOnSessionReleased(func(sessionID string) {
@@ -81,41 +91,109 @@ type RetrievalResult struct {
 	NewQuery        *pb.SearchResultItem
 	CurrentQuery    *pb.SearchResultItem
 	MultimodelQuery *pb.SearchResultItem
+
+	NewQueryHits        []*pb.SearchResultItem
+	CurrentQueryHits    []*pb.SearchResultItem
+	MultimodalQueryHits []*pb.SearchResultItem
+
+	Contexts map[string]dtoOrchestrator.ChatContextReport
 }
 
-const minContextScore = 0.55
+const (
+	// minContextScore is the weighted_minmax score a hit needs to be used as
+	// context; contextScoreFloor converts it for the other fusion strategies.
+	minContextScore = 0.55
+	// dbsfMinContextScore is one σ above the result list mean on the dbsf
+	// scale.
+	dbsfMinContextScore = 0.5 + 1.0/6
+	// defaultRRFK is the rag service's rrf_k when none is configured.
+	defaultRRFK = 60
+)
+
+// StreamEmitter receives stage and answer delta events while ExecuteStream runs.
+// Returning an error (e.g. the client went away) aborts the pipeline.
+type StreamEmitter func(event dtoOrchestrator.ChatStreamEvent) error
 
 func (c *ChatbotHandler) Execute(
 	ctx context.Context,
 	query string,
 	imagePath string,
 	session_id string,
-	uuid string,
-) (string, error) {
+	scope CollectionScope,
+) (*dtoOrchestrator.ChatResponse, error) {
+	return c.execute(ctx, query, imagePath, session_id, scope, nil)
+}
+
+func (c *ChatbotHandler) ExecuteStream(
+	ctx context.Context,
+	query string,
+	imagePath string,
+	session_id string,
+	scope CollectionScope,
+	emit StreamEmitter,
+) (*dtoOrchestrator.ChatResponse, error) {
+	if emit == nil {
+		return nil, errors.New("stream emitter is required")
+	}
+	return c.execute(ctx, query, imagePath, session_id, scope, emit)
+}
+
+func (c *ChatbotHandler) execute(
+	ctx context.Context,
+	query string,
+	imagePath string,
+	session_id string,
+	scope CollectionScope,
+	emit StreamEmitter,
+) (response *dtoOrchestrator.ChatResponse, err error) {
+	startedAt := time.Now()
+	intent := ""
+	defer func() {
+		if c.metrics == nil || intent == "" {
+			return
+		}
+		status := "ok"
+		if err != nil {
+			status = "error"
+		}
+		c.metrics.RecordChatPipeline(intent, status, time.Since(startedAt).Seconds())
+	}()
+
+	collectionNames, err := c.resolveCollections(scope)
+	if err != nil {
+		return nil, err
+	}
 	exist, err := c.Session.SessionExists(session_id)
 	if err != nil {
 		if c.appLogger != nil {
 			c.appLogger.Error("internal.application.use_cases.orchestrator.chat.SessionExists failed", err)
 		}
-		return "", err
+		return nil, err
 	}
 	if !exist {
 		if err := c.Session.CreateSession(session_id); err != nil {
 			if c.appLogger != nil {
 				c.appLogger.Error("internal.application.use_cases.orchestrator.chat.CreateSession failed", err)
 			}
-			return "", err
+			return nil, err
+		}
+	}
+	if err := c.Session.UpdateMetadata(session_id, func(metadata *portsOrchestrator.Metadata) {
+		metadata.Collection = strings.Join(collectionNames, ",")
+	}); err != nil {
+		if c.appLogger != nil {
+			c.appLogger.Error("internal.application.use_cases.orchestrator.chat.UpdateMetadata failed", err, "session_id", session_id)
 		}
+		return nil, err
 	}
 	imagePath, err = c.prepareImageForSession(ctx, strings.TrimSpace(imagePath), session_id)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	topK := memoryTopK(c.Config.OrchestratorService.MemoryHistoryTopK)
 	sessionHistory, err := c.getSessionHistory(session_id)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	if c.appLogger != nil {
 		c.appLogger.Info(
@@ -139,32 +217,64 @@ func (c *ChatbotHandler) Execute(
 		"preprocess",
 	)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	var executeQueries ExecuteQueries
 	executeQueries.CurrentQuery.TextDense = strings.TrimSpace(responsePreprocess.Json["CurrentQuery"])
 	executeQueries.NewQuery.TextDense = strings.TrimSpace(responsePreprocess.Json["NewQuery"])
-	skipRetrieval := shouldSkipRetrieval(query, imagePath)
+	decision := c.classifyIntent(query, imagePath, responsePreprocess.Json["Intent"])
+	c.recordIntent(decision)
+	intent = decision.Intent
+	skipRetrieval := decision.Intent == IntentChitChat || decision.Intent == IntentOutOfScope
 	if c.appLogger != nil {
 		c.appLogger.Info(
 			"internal.application.use_cases.orchestrator.chat.Execute preprocess output",
 			"session_id", session_id,
 			"current_query", executeQueries.CurrentQuery.TextDense,
 			"new_query", executeQueries.NewQuery.TextDense,
+			"intent", decision.Intent,
+			"intent_source", decision.Source,
+			"llm_intent", responsePreprocess.Json["Intent"],
 			"skip_retrieval", skipRetrieval,
 		)
 	}
 	if strings.TrimSpace(imagePath) != "" {
 		executeQueries.MultimodalQuery = &QueryPayload{ImageDense: imagePath}
 	}
+	if emit != nil {
+		if err := emit(dtoOrchestrator.ChatStreamEvent{
+			Event:         "stage",
+			Stage:         "preprocess",
+			SessionID:     session_id,
+			CurrentQuery:  executeQueries.CurrentQuery.TextDense,
+			NewQuery:      executeQueries.NewQuery.TextDense,
+			Intent:        decision.Intent,
+			SkipRetrieval: &skipRetrieval,
+		}); err != nil {
+			return nil, err
+		}
+	}
 
 	contextText := ""
 	contextSource := ""
+	var sources []dtoOrchestrator.ChatSource
+	var contextReport *dtoOrchestrator.ChatContextReport
 	newQueryScore := float32(-1)
 	currentQueryScore := float32(-1)
 	multimodalQueryScore := float32(-1)
-	retrievalLimit := ragRetrievalTopK(c.Config.OrchestratorService.RAGRetrievalTopK)
+	retrievalLimit := c.retrievalCandidateLimit()
+	if decision.Intent == IntentSummarizeDocument && c.summaryTopK() > retrievalLimit {
+		retrievalLimit = c.summaryTopK()
+	}
+	mergeLimit := int(retrievalLimit)
+	if decision.Intent == IntentCompareDocuments {
+		mergeLimit *= len(collectionNames)
+	}
+	minScore := c.contextScoreFloor(collectionNames)
+	if decision.Intent == IntentSummarizeDocument {
+		minScore = 0
+	}
 
 	if !skipRetrieval {
 		var wg sync.WaitGroup
@@ -180,88 +290,205 @@ func (c *ChatbotHandler) Execute(
 		}()
 		wg.Wait()
 		if embedErr != nil {
-			return "", embedErr
-		}
-
-		collectionName := strings.TrimSpace(uuid)
-		if collectionName == "" {
-			return "", errors.New("collection name is required")
+			return nil, embedErr
 		}
 
-		retrievalReq := RetrievalRequest{
-			NewQuery: &pb.SearchPointRequest{
-				CollectionName: collectionName,
-				VectorName:     "text_dense",
-				Vector:         embedResults.NewText.Embedding,
-				Limit:          retrievalLimit,
-				WithPayload:    true,
-			},
-			CurrentQuery: &pb.SearchPointRequest{
-				CollectionName: collectionName,
-				VectorName:     "text_dense",
-				Vector:         embedResults.CurrentText.Embedding,
-				Limit:          retrievalLimit,
-				WithPayload:    true,
-			},
-		}
-		if embedResults.Image != nil {
-			retrievalReq.MultimodalQuery = &pb.SearchPointRequest{
-				CollectionName: collectionName,
-				VectorName:     "image_dense",
-				Vector:         embedResults.Image.Embedding,
-				Limit:          retrievalLimit,
-				WithPayload:    true,
+		retrievalReqs := make([]RetrievalRequest, 0, len(collectionNames))
+		for _, collectionName := range collectionNames {
+			retrievalReq := RetrievalRequest{
+				NewQuery: &pb.SearchPointRequest{
+					CollectionName: collectionName,
+					VectorName:     "text_dense",
+					Vector:         embedResults.NewText.Embedding,
+					Limit:          retrievalLimit,
+					WithPayload:    true,
+				},
+				CurrentQuery: &pb.SearchPointRequest{
+					CollectionName: collectionName,
+					VectorName:     "text_dense",
+					Vector:         embedResults.CurrentText.Embedding,
+					Limit:          retrievalLimit,
+					WithPayload:    true,
+				},
+			}
+			if embedResults.Image != nil {
+				retrievalReq.MultimodalQuery = &pb.SearchPointRequest{
+					CollectionName: collectionName,
+					VectorName:     "image_dense",
+					Vector:         embedResults.Image.Embedding,
+					Limit:          retrievalLimit,
+					WithPayload:    true,
+				}
 			}
+			fusion := c.fusionFor(collectionName)
+			applyFusion(retrievalReq.NewQuery, fusion)
+			applyFusion(retrievalReq.CurrentQuery, fusion)
+			applyFusion(retrievalReq.MultimodalQuery, fusion)
+			retrievalReqs = append(retrievalReqs, retrievalReq)
 		}
 
 		var retrievalResults RetrievalResult
 		var retrievalErr error
 		wg.Add(1)
 		go func() {
-			retrievalResults, retrievalErr = c.retrieval(ctx, &wg, retrievalReq)
+			retrievalResults, retrievalErr = c.retrieval(ctx, &wg, retrievalReqs, mergeLimit, decision.Intent == IntentCompareDocuments)
 		}()
 		wg.Wait()
 		if retrievalErr != nil {
-			return "", retrievalErr
+			return nil, retrievalErr
+		}
+		// Summaries and comparisons want coverage, not the top_n most relevant chunks.
+		if decision.Intent == IntentKnowledge {
+			retrievalResults = c.rerankRetrieval(ctx, session_id, executeQueries, retrievalResults)
 		}
+		retrievalResults = c.assembleContexts(session_id, retrievalResults, decision.Intent == IntentSummarizeDocument)
 
 		newQueryScore = retrievalScore(retrievalResults.NewQuery)
 		currentQueryScore = retrievalScore(retrievalResults.CurrentQuery)
 		multimodalQueryScore = retrievalScore(retrievalResults.MultimodelQuery)
-		contextText, contextSource = selectContextFromRetrieval(strings.TrimSpace(imagePath), retrievalResults)
+		contextText, contextSource = selectContextFromRetrieval(strings.TrimSpace(imagePath), retrievalResults, minScore)
+		sources = c.buildSources(ctx, contextHits(retrievalResults, contextSource), contextSource)
+		if report, ok := retrievalResults.Contexts[contextSource]; ok {
+			contextReport = &report
+		}
+	}
+	if emit != nil {
+		if err := emit(dtoOrchestrator.ChatStreamEvent{
+			Event:         "stage",
+			Stage:         "retrieval",
+			SessionID:     session_id,
+			SkipRetrieval: &skipRetrieval,
+			ContextSource: contextSource,
+			Sources:       sources,
+			Scores: &dtoOrchestrator.ChatRetrievalScores{
+				NewQuery:        newQueryScore,
+				CurrentQuery:    currentQueryScore,
+				MultimodalQuery: multimodalQueryScore,
+			},
+			Context: contextReport,
+		}); err != nil {
+			return nil, err
+		}
 	}
 
-	finalQuery := query
+	finalQuery := intentInstruction(decision.Intent) + query
 	if strings.TrimSpace(contextText) != "" {
-		finalQuery = query + "\n\nContext:\n" + contextText
+		finalQuery = finalQuery + "\n\nContext:\n" + contextText
 	}
 	if c.appLogger != nil {
 		c.appLogger.Info(
 			"internal.application.use_cases.orchestrator.chat.Execute full prompt for answer",
 			"session_id", session_id,
-			"collection_name", strings.TrimSpace(uuid),
+			"collection_names", strings.Join(collectionNames, ","),
 			"new_query_score", newQueryScore,
 			"current_query_score", currentQueryScore,
 			"multimodal_query_score", multimodalQueryScore,
 			"context_source", contextSource,
+			"source_count", len(sources),
 			"retrieval_top_k", retrievalLimit,
+			"rerank_strategy", c.rerankStrategy(),
 			"skip_retrieval", skipRetrieval,
-			"min_context_score", minContextScore,
+			"min_context_score", minScore,
+			"intent", decision.Intent,
 			"full_prompt", finalQuery,
 		)
 	}
 
-	responseAnswer, err := c.llmChat(
-		ctx,
-		c.PromptAnswer,
-		c.Config.OrchestratorService.PreProcessing.Temperature,
-		c.Config.OrchestratorService.PreProcessing.Model,
-		finalQuery,
-		nil,
-		imagePath,
-		session_id,
-		"answer",
+	var finalAnswer string
+	if decision.Intent == IntentOutOfScope {
+		finalAnswer = c.refusalMessage()
+		c.logAnswerPipeline(decision.Intent, "", "", "refusal", finalAnswer)
+		if emit != nil {
+			if err := emit(dtoOrchestrator.ChatStreamEvent{
+				Event:     "delta",
+				Stage:     "answer",
+				SessionID: session_id,
+				Delta:     finalAnswer,
+			}); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		finalAnswer, err = c.generateAnswer(ctx, query, finalQuery, imagePath, session_id, sessionHistory, decision.Intent, emit)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// A stream only counts as answered once the final event reached the
+	// client, so the turn is stored after it.
+	if emit != nil {
+		if err := emit(dtoOrchestrator.ChatStreamEvent{
+			Event:     "final",
+			Stage:     "postprocess",
+			SessionID: session_id,
+			Answer:    finalAnswer,
+			Sources:   sources,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.appendConversation(session_id, query, finalAnswer); err != nil {
+		return nil, err
+	}
+	c.summarizeHistoryAsync(session_id)
+
+	return &dtoOrchestrator.ChatResponse{
+		Answer:    finalAnswer,
+		SessionID: session_id,
+		Intent:    decision.Intent,
+		Sources:   sources,
+		Context:   contextReport,
+	}, nil
+}
+
+// generateAnswer runs the answer stage (streamed when emit is set) and the
+// optional postprocess stage, returning the answer to store and send.
+func (c *ChatbotHandler) generateAnswer(
+	ctx context.Context,
+	query string,
+	finalQuery string,
+	imagePath string,
+	session_id string,
+	sessionHistory []portsOrchestrator.ChatMessage,
+	intent string,
+	emit StreamEmitter,
+) (string, error) {
+	var (
+		responseAnswer *pb.LLMResponse
+		err            error
 	)
+	if emit != nil {
+		responseAnswer, err = c.llmChatStream(
+			ctx,
+			c.PromptAnswer,
+			c.Config.OrchestratorService.PreProcessing.Temperature,
+			c.Config.OrchestratorService.PreProcessing.Model,
+			finalQuery,
+			imagePath,
+			session_id,
+			"answer",
+			func(delta string) error {
+				return emit(dtoOrchestrator.ChatStreamEvent{
+					Event:     "delta",
+					Stage:     "answer",
+					SessionID: session_id,
+					Delta:     delta,
+				})
+			},
+		)
+	} else {
+		responseAnswer, err = c.llmChat(
+			ctx,
+			c.PromptAnswer,
+			c.Config.OrchestratorService.PreProcessing.Temperature,
+			c.Config.OrchestratorService.PreProcessing.Model,
+			finalQuery,
+			nil,
+			imagePath,
+			session_id,
+			"answer",
+		)
+	}
 	if err != nil {
 		return "", err
 	}
@@ -305,7 +532,7 @@ func (c *ChatbotHandler) Execute(
 					"postprocess_raw", postRaw,
 				)
 			}
-			lastAssistant := latestAssistantMessage(sessionHistory, topK)
+			lastAssistant := latestAssistantMessage(sessionHistory, memoryTopK(c.Config.OrchestratorService.MemoryHistoryTopK))
 			if ok, reason := shouldAcceptPostprocess(responseAnswer.Text, postRaw, lastAssistant); ok {
 				finalAnswer = postRaw
 				postprocessReason = "accepted"
@@ -321,11 +548,7 @@ func (c *ChatbotHandler) Execute(
 			}
 		}
 	}
-	c.logAnswerPipeline("knowledge", responseAnswer.Text, postprocessRaw, postprocessReason, finalAnswer)
-	if err := c.appendConversation(session_id, query, finalAnswer); err != nil {
-		return "", err
-	}
-
+	c.logAnswerPipeline(intent, responseAnswer.Text, postprocessRaw, postprocessReason, finalAnswer)
 	return finalAnswer, nil
 }
 
@@ -392,21 +615,21 @@ func ragRetrievalTopK(topK int) uint64 {
 	return uint64(topK)
 }
 
-func selectContextFromRetrieval(imagePath string, results RetrievalResult) (string, string) {
+func selectContextFromRetrieval(imagePath string, results RetrievalResult, minScore float32) (string, string) {
 	hasImage := strings.TrimSpace(imagePath) != ""
 	if hasImage {
-		if results.MultimodelQuery != nil && results.MultimodelQuery.Score >= minContextScore {
+		if results.MultimodelQuery != nil && results.MultimodelQuery.Score >= minScore {
 			return strings.TrimSpace(results.MultimodelQuery.Payload["text"]), "multimodal_query"
 		}
 		return "", ""
 	}
-	if results.NewQuery != nil && results.NewQuery.Score >= minContextScore {
+	if results.NewQuery != nil && results.NewQuery.Score >= minScore {
 		return strings.TrimSpace(results.NewQuery.Payload["text"]), "new_query"
 	}
-	if results.CurrentQuery != nil && results.CurrentQuery.Score >= minContextScore {
+	if results.CurrentQuery != nil && results.CurrentQuery.Score >= minScore {
 		return strings.TrimSpace(results.CurrentQuery.Payload["text"]), "current_query"
 	}
-	if results.MultimodelQuery != nil && results.MultimodelQuery.Score >= minContextScore {
+	if results.MultimodelQuery != nil && results.MultimodelQuery.Score >= minScore {
 		return strings.TrimSpace(results.MultimodelQuery.Payload["text"]), "multimodal_query"
 	}
 	return "", ""
@@ -0,0 +1,139 @@
+package chat
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	pb "rag_imagetotext_texttoimage/proto"
+)
+
+var ErrUnknownWorkspace = errors.New("unknown workspace")
+
+// CollectionScope selects the Qdrant collections searched for one chat turn.
+// Uuid, Collections and the collections of Workspace are combined.
+type CollectionScope struct {
+	Uuid        string
+	Collections []string
+	Workspace   string
+}
+
+// resolveCollections flattens a scope into distinct collection names, keeping
+// the order they were given in. Workspaces come from orchestrator_service.workspaces.
+func (c *ChatbotHandler) resolveCollections(scope CollectionScope) ([]string, error) {
+	names := make([]string, 0, len(scope.Collections)+1)
+	names = append(names, scope.Uuid)
+	names = append(names, scope.Collections...)
+	if workspace := strings.TrimSpace(scope.Workspace); workspace != "" {
+		members, ok := c.Config.OrchestratorService.Workspaces[workspace]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownWorkspace, workspace)
+		}
+		names = append(names, members...)
+	}
+
+	out := make([]string, 0, len(names))
+	seen := map[string]struct{}{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		out = append(out, name)
+	}
+	if len(out) == 0 {
+		return nil, errors.New("collection name is required")
+	}
+	return out, nil
+}
+
+// tagCollection records the source collection on each hit so context blocks
+// and sources can report where they came from after merging.
+func tagCollection(results []*pb.SearchResultItem, collectionName string) {
+	for _, item := range results {
+		if item == nil {
+			continue
+		}
+		if item.Payload == nil {
+			item.Payload = map[string]string{}
+		}
+		item.Payload["collection"] = collectionName
+	}
+}
+
+// mergeCollectionHits combines hits from several collections into one list
+// ordered by score, keeps the best hit for each distinct text and cuts the
+// list to limit.
+func mergeCollectionHits(results []*pb.SearchResultItem, limit int) []*pb.SearchResultItem {
+	sorted := make([]*pb.SearchResultItem, 0, len(results))
+	for _, item := range results {
+		if item != nil {
+			sorted = append(sorted, item)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Score > sorted[j].Score
+	})
+	hits := dedupeResultsByText(sorted)
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// mergeBalancedHits interleaves the per-collection rankings (best of each
+// collection first, then the second best, ...) so every collection is
+// represented when documents are compared. Duplicate texts and the limit are
+// handled as in mergeCollectionHits.
+func mergeBalancedHits(results []*pb.SearchResultItem, limit int) []*pb.SearchResultItem {
+	order := make([]string, 0)
+	byCollection := map[string][]*pb.SearchResultItem{}
+	for _, item := range mergeCollectionHits(results, 0) {
+		collection := item.Payload["collection"]
+		if _, ok := byCollection[collection]; !ok {
+			order = append(order, collection)
+		}
+		byCollection[collection] = append(byCollection[collection], item)
+	}
+
+	hits := make([]*pb.SearchResultItem, 0, len(results))
+	for rank := 0; ; rank++ {
+		added := false
+		for _, collection := range order {
+			if rank < len(byCollection[collection]) {
+				hits = append(hits, byCollection[collection][rank])
+				added = true
+			}
+		}
+		if !added {
+			break
+		}
+	}
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+func spansCollections(results []*pb.SearchResultItem) bool {
+	first := ""
+	for _, item := range results {
+		if item == nil {
+			continue
+		}
+		collection := item.Payload["collection"]
+		if first == "" {
+			first = collection
+			continue
+		}
+		if collection != first {
+			return true
+		}
+	}
+	return false
+}
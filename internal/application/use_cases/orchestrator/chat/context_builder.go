@@ -0,0 +1,319 @@
+package chat
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	dtoOrchestrator "rag_imagetotext_texttoimage/internal/application/dtos/orchestrator"
+	pb "rag_imagetotext_texttoimage/proto"
+)
+
+const (
+	contextReasonNearDuplicate = "near_duplicate"
+	contextReasonTokenBudget   = "token_budget"
+	contextReasonClipped       = "clipped"
+)
+
+const contextBlockSeparator = "\n\n---\n\n"
+
+// contextSentencePattern matches the splitter used at ingest, so overlap
+// sentences copied between neighbouring chunks compare equal here.
+var contextSentencePattern = regexp.MustCompile(`(?m)([^.!?\n]+[.!?]?)(\s+|$)`)
+
+type assembledContext struct {
+	Item     *pb.SearchResultItem
+	Included []*pb.SearchResultItem
+	Report   dtoOrchestrator.ChatContextReport
+}
+
+// assembleContexts builds the prompt context for each retrieval stage from
+// its ranked hits. NewQueryHits and friends are replaced by the included
+// hits so source indexes line up with the [n] markers in the context. With
+// documentOrder the chunks are still chosen by rank but laid out in reading
+// order (collection, doc_id, chunk_index), which suits document summaries.
+func (c *ChatbotHandler) assembleContexts(sessionID string, results RetrievalResult, documentOrder bool) RetrievalResult {
+	results.Contexts = map[string]dtoOrchestrator.ChatContextReport{}
+	stages := []struct {
+		name string
+		hits *[]*pb.SearchResultItem
+		item **pb.SearchResultItem
+	}{
+		{"new_query", &results.NewQueryHits, &results.NewQuery},
+		{"current_query", &results.CurrentQueryHits, &results.CurrentQuery},
+		{"multimodal_query", &results.MultimodalQueryHits, &results.MultimodelQuery},
+	}
+	for _, stage := range stages {
+		if len(*stage.hits) == 0 {
+			continue
+		}
+		budget := c.contextTokenBudget(stage.name)
+		threshold := c.Config.OrchestratorService.Context.NearDuplicateThreshold
+		assembled := buildContext(stage.name, *stage.hits, budget, threshold)
+		if documentOrder && len(assembled.Included) > 1 {
+			truncated := assembled.Report.Truncated
+			assembled = buildContext(stage.name, sortByDocumentPosition(assembled.Included), budget, threshold)
+			assembled.Report.Truncated = append(truncated, assembled.Report.Truncated...)
+		}
+		*stage.hits = assembled.Included
+		*stage.item = assembled.Item
+		results.Contexts[stage.name] = assembled.Report
+		c.logContextReport(sessionID, assembled.Report)
+	}
+	return results
+}
+
+func (c *ChatbotHandler) contextTokenBudget(stage string) int {
+	settings := c.Config.OrchestratorService.Context
+	if budget, ok := settings.StageBudgets[stage]; ok && budget > 0 {
+		return budget
+	}
+	return settings.TokenBudget
+}
+
+func (c *ChatbotHandler) logContextReport(sessionID string, report dtoOrchestrator.ChatContextReport) {
+	if c.appLogger == nil {
+		return
+	}
+	c.appLogger.Info(
+		"internal.application.use_cases.orchestrator.chat.context assembled",
+		"session_id", sessionID,
+		"stage", report.Stage,
+		"token_budget", report.TokenBudget,
+		"tokens_used", report.TokensUsed,
+		"included_count", len(report.Included),
+		"truncated_count", len(report.Truncated),
+		"included", describeContextChunks(report.Included),
+		"truncated", describeContextChunks(report.Truncated),
+	)
+}
+
+// buildContext walks hits in rank order and packs them into numbered blocks
+// until the token budget runs out. Sentences already present in an included
+// block are stripped (neighbouring chunks share an overlap sentence), and a
+// hit whose remaining text is mostly contained in earlier blocks is dropped.
+// The first block is clipped rather than dropped when it alone exceeds the
+// budget. The item's Score is the best retrieval score among the hits so
+// the context score floor keeps gating on it.
+func buildContext(stage string, hits []*pb.SearchResultItem, budget int, nearDuplicateThreshold float64) assembledContext {
+	out := assembledContext{
+		Report: dtoOrchestrator.ChatContextReport{
+			Stage:       stage,
+			TokenBudget: budget,
+			Included:    []dtoOrchestrator.ChatContextChunk{},
+		},
+	}
+
+	seenSentences := map[string]struct{}{}
+	includedShingles := make([]map[string]struct{}, 0, len(hits))
+	bodies := make([]string, 0, len(hits))
+	topScore := float32(-1)
+
+	for _, hit := range hits {
+		if hit == nil {
+			continue
+		}
+		if hit.Score > topScore {
+			topScore = hit.Score
+		}
+		text := strings.TrimSpace(hit.Payload["text"])
+		if text == "" {
+			continue
+		}
+
+		kept := make([]string, 0)
+		for _, sentence := range splitContextSentences(text) {
+			if _, ok := seenSentences[sentenceKey(sentence)]; ok {
+				continue
+			}
+			kept = append(kept, sentence)
+		}
+		body := strings.Join(kept, " ")
+		shingles := wordShingles(body)
+		if len(kept) == 0 || isNearDuplicate(shingles, includedShingles, nearDuplicateThreshold) {
+			out.Report.Truncated = append(out.Report.Truncated, contextChunk(hit, countContextTokens(text), 0, contextReasonNearDuplicate))
+			continue
+		}
+
+		tokens := countContextTokens(body)
+		reason := ""
+		if budget > 0 && out.Report.TokensUsed+tokens > budget {
+			if len(out.Included) > 0 {
+				out.Report.Truncated = append(out.Report.Truncated, contextChunk(hit, tokens, 0, contextReasonTokenBudget))
+				continue
+			}
+			body = clipToTokens(body, budget)
+			tokens = countContextTokens(body)
+			reason = contextReasonClipped
+		}
+
+		for _, sentence := range kept {
+			seenSentences[sentenceKey(sentence)] = struct{}{}
+		}
+		includedShingles = append(includedShingles, shingles)
+		bodies = append(bodies, body)
+		out.Included = append(out.Included, hit)
+		out.Report.TokensUsed += tokens
+		out.Report.Included = append(out.Report.Included, contextChunk(hit, tokens, len(out.Included), reason))
+	}
+
+	if len(out.Included) == 0 {
+		return out
+	}
+
+	labelCollection := spansCollections(out.Included)
+	parts := make([]string, 0, len(bodies))
+	for i, body := range bodies {
+		if labelCollection {
+			parts = append(parts, fmt.Sprintf("[%d] (collection: %s) %s", i+1, out.Included[i].Payload["collection"], body))
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("[%d] %s", i+1, body))
+	}
+
+	base := out.Included[0]
+	payload := map[string]string{}
+	for k, v := range base.Payload {
+		payload[k] = v
+	}
+	payload["text"] = strings.Join(parts, contextBlockSeparator)
+	out.Item = &pb.SearchResultItem{
+		Id:      base.Id,
+		Score:   topScore,
+		Payload: payload,
+	}
+	return out
+}
+
+func sortByDocumentPosition(hits []*pb.SearchResultItem) []*pb.SearchResultItem {
+	out := append([]*pb.SearchResultItem(nil), hits...)
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i].Payload, out[j].Payload
+		if a["collection"] != b["collection"] {
+			return a["collection"] < b["collection"]
+		}
+		if a["doc_id"] != b["doc_id"] {
+			return a["doc_id"] < b["doc_id"]
+		}
+		ai, _ := strconv.Atoi(strings.TrimSpace(a["chunk_index"]))
+		bi, _ := strconv.Atoi(strings.TrimSpace(b["chunk_index"]))
+		return ai < bi
+	})
+	return out
+}
+
+func contextChunk(hit *pb.SearchResultItem, tokens int, index int, reason string) dtoOrchestrator.ChatContextChunk {
+	chunk := dtoOrchestrator.ChatContextChunk{
+		Index:      index,
+		DocID:      strings.TrimSpace(hit.Payload["doc_id"]),
+		Collection: strings.TrimSpace(hit.Payload["collection"]),
+		Score:      hit.Score,
+		Tokens:     tokens,
+		Reason:     reason,
+	}
+	if chunkIndex, err := strconv.Atoi(strings.TrimSpace(hit.Payload["chunk_index"])); err == nil {
+		chunk.ChunkIndex = chunkIndex
+	}
+	return chunk
+}
+
+func describeContextChunks(chunks []dtoOrchestrator.ChatContextChunk) string {
+	parts := make([]string, 0, len(chunks))
+	for _, chunk := range chunks {
+		part := fmt.Sprintf("%s#%d(%.3f,%dt)", chunk.DocID, chunk.ChunkIndex, chunk.Score, chunk.Tokens)
+		if chunk.Reason != "" {
+			part += ":" + chunk.Reason
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " ")
+}
+
+func splitContextSentences(text string) []string {
+	matches := contextSentencePattern.FindAllStringSubmatch(text, -1)
+	out := make([]string, 0, len(matches))
+	for _, m := range matches {
+		if len(m) < 2 {
+			continue
+		}
+		if sentence := strings.Join(strings.Fields(m[1]), " "); sentence != "" {
+			out = append(out, sentence)
+		}
+	}
+	if len(out) == 0 {
+		return []string{strings.Join(strings.Fields(text), " ")}
+	}
+	return out
+}
+
+func sentenceKey(sentence string) string {
+	return strings.ToLower(strings.Join(strings.Fields(sentence), " "))
+}
+
+// wordShingles returns the set of lower-cased word trigrams; texts shorter
+// than three words fall back to single words.
+func wordShingles(text string) map[string]struct{} {
+	words := strings.Fields(strings.ToLower(text))
+	out := map[string]struct{}{}
+	if len(words) < 3 {
+		for _, w := range words {
+			out[w] = struct{}{}
+		}
+		return out
+	}
+	for i := 0; i+3 <= len(words); i++ {
+		out[strings.Join(words[i:i+3], " ")] = struct{}{}
+	}
+	return out
+}
+
+// isNearDuplicate reports whether at least threshold of the candidate's
+// shingles already appear in one included block.
+func isNearDuplicate(candidate map[string]struct{}, included []map[string]struct{}, threshold float64) bool {
+	if len(candidate) == 0 || threshold <= 0 {
+		return false
+	}
+	for _, block := range included {
+		shared := 0
+		for shingle := range candidate {
+			if _, ok := block[shingle]; ok {
+				shared++
+			}
+		}
+		if float64(shared)/float64(len(candidate)) >= threshold {
+			return true
+		}
+	}
+	return false
+}
+
+// countContextTokens approximates the LLM token count as the larger of the
+// word count and one token per four characters, which errs on the high side
+// for both Latin and Vietnamese text.
+func countContextTokens(text string) int {
+	text = strings.TrimSpace(text)
+	words := len(strings.Fields(text))
+	chars := (utf8.RuneCountInString(text) + 3) / 4
+	if words > chars {
+		return words
+	}
+	return chars
+}
+
+func clipToTokens(text string, budget int) string {
+	words := strings.Fields(text)
+	for len(words) > 0 && countContextTokens(strings.Join(words, " ")) > budget {
+		cut := len(words) * budget / countContextTokens(strings.Join(words, " "))
+		if cut >= len(words) {
+			cut = len(words) - 1
+		}
+		words = words[:cut]
+	}
+	if len(words) == 0 {
+		return ""
+	}
+	return strings.Join(words, " ") + " ..."
+}
@@ -0,0 +1,134 @@
+package chat
+
+import (
+	"strings"
+
+	"rag_imagetotext_texttoimage/internal/util"
+)
+
+const (
+	IntentKnowledge         = "knowledge"
+	IntentChitChat          = "chit_chat"
+	IntentOutOfScope        = "out_of_scope"
+	IntentSummarizeDocument = "summarize_document"
+	IntentCompareDocuments  = "compare_documents"
+)
+
+const (
+	intentSourceRule    = "rule"
+	intentSourceLLM     = "llm"
+	intentSourceDefault = "default"
+)
+
+// IntentOutputSchema is the preprocessing structured-output field the LLM
+// fills with one of the known intents.
+const IntentOutputSchema = `{"type":"string","enum":["knowledge","chit_chat","out_of_scope","summarize_document","compare_documents"]}`
+
+const defaultRefusalMessage = "Xin loi, cau hoi nay nam ngoai pham vi tai lieu ma toi ho tro. Ban hay dat cau hoi lien quan den noi dung tai lieu nhe."
+
+const defaultSummaryTopK = 20
+
+type intentDecision struct {
+	Intent string
+	Source string
+}
+
+func isKnownIntent(intent string) bool {
+	switch intent {
+	case IntentKnowledge, IntentChitChat, IntentOutOfScope, IntentSummarizeDocument, IntentCompareDocuments:
+		return true
+	}
+	return false
+}
+
+// classifyIntent picks the intent for one turn: the first matching config
+// rule, then the intent from the preprocessing output, then the configured
+// default. Queries with an image always go through retrieval.
+func (c *ChatbotHandler) classifyIntent(query string, imagePath string, llmIntent string) intentDecision {
+	settings := c.Config.OrchestratorService.Intent
+	hasImage := strings.TrimSpace(imagePath) != ""
+
+	decision := intentDecision{Intent: IntentKnowledge, Source: intentSourceDefault}
+	if fallback := normalizeIntentName(settings.Default); isKnownIntent(fallback) {
+		decision.Intent = fallback
+	}
+	if intent, ok := matchIntentRules(query, settings.Rules); ok {
+		decision = intentDecision{Intent: intent, Source: intentSourceRule}
+	} else if intent := normalizeIntentName(llmIntent); isKnownIntent(intent) {
+		decision = intentDecision{Intent: intent, Source: intentSourceLLM}
+	}
+
+	if hasImage && decision.Intent == IntentChitChat {
+		decision.Intent = IntentKnowledge
+	}
+	return decision
+}
+
+func matchIntentRules(query string, rules []util.IntentRule) (string, bool) {
+	q := normalizeForOverlap(query)
+	if q == "" {
+		return IntentChitChat, true
+	}
+	wordCount := len(strings.Fields(q))
+	for _, rule := range rules {
+		intent := normalizeIntentName(rule.Intent)
+		if !isKnownIntent(intent) {
+			continue
+		}
+		if rule.MaxWords > 0 && wordCount > rule.MaxWords {
+			continue
+		}
+		for _, pattern := range rule.Patterns {
+			if containsPhrase(q, normalizeForOverlap(pattern)) {
+				return intent, true
+			}
+		}
+	}
+	return "", false
+}
+
+// containsPhrase matches phrase on word boundaries, so "hi" does not match
+// inside "this".
+func containsPhrase(text string, phrase string) bool {
+	if phrase == "" {
+		return false
+	}
+	return strings.Contains(" "+text+" ", " "+phrase+" ")
+}
+
+func normalizeIntentName(intent string) string {
+	intent = strings.ToLower(strings.TrimSpace(intent))
+	return strings.NewReplacer("-", "_", " ", "_").Replace(intent)
+}
+
+func (c *ChatbotHandler) refusalMessage() string {
+	if message := strings.TrimSpace(c.Config.OrchestratorService.Intent.RefusalMessage); message != "" {
+		return message
+	}
+	return defaultRefusalMessage
+}
+
+func (c *ChatbotHandler) summaryTopK() uint64 {
+	if topK := c.Config.OrchestratorService.Intent.SummaryTopK; topK > 0 {
+		return uint64(topK)
+	}
+	return defaultSummaryTopK
+}
+
+// intentInstruction is prepended to the user query in the answer prompt for
+// intents whose answer has a specific shape.
+func intentInstruction(intent string) string {
+	switch intent {
+	case IntentSummarizeDocument:
+		return "Hay tom tat noi dung tai lieu theo trinh tu cac doan context, neu cac y chinh. Yeu cau: "
+	case IntentCompareDocuments:
+		return "Hay so sanh cac tai lieu dua tren context, chi ro diem giong va khac nhau va ghi ro collection cua tung y. Yeu cau: "
+	}
+	return ""
+}
+
+func (c *ChatbotHandler) recordIntent(decision intentDecision) {
+	if c.metrics != nil {
+		c.metrics.RecordChatIntent(decision.Intent, decision.Source)
+	}
+}
@@ -6,26 +6,6 @@ import (
 	portsOrchestrator "rag_imagetotext_texttoimage/internal/application/ports/orchestrator"
 )
 
-func shouldSkipRetrieval(query string, imagePath string) bool {
-	if strings.TrimSpace(imagePath) != "" {
-		return false
-	}
-	q := normalizeForOverlap(query)
-	if q == "" {
-		return true
-	}
-	// Keep LLM flow but avoid pulling unrelated RAG context for casual greetings.
-	greetingPatterns := []string{
-		"xin chao", "chao ban", "hello", "hi", "hey", "alo", "good morning", "good afternoon",
-	}
-	for _, pat := range greetingPatterns {
-		if strings.Contains(q, pat) {
-			return true
-		}
-	}
-	return false
-}
-
 func memoryTopK(v int) int {
 	if v <= 0 {
 		return 5
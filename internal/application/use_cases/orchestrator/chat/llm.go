@@ -3,6 +3,7 @@ package chat
 import (
 	"context"
 	"errors"
+	"io"
 	"strings"
 
 	portsOrchestrator "rag_imagetotext_texttoimage/internal/application/ports/orchestrator"
@@ -20,30 +21,10 @@ func (c *ChatbotHandler) llmChat(
 	session_id string,
 	stage string,
 ) (*pb.LLMResponse, error) {
-	if c == nil || c.Session == nil {
-		return nil, errors.New("session store is not configured")
-	}
-
-	sessionData, err := c.Session.GetSession(session_id)
+	history, err := c.llmHistory(session_id)
 	if err != nil {
 		return nil, err
 	}
-
-	chatHistory := make([]portsOrchestrator.ChatMessage, 0)
-	if sessionData.ChatHistory != nil {
-		chatHistory, err = sessionData.ChatHistory.GetChatHistory()
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	history := make([]*pb.ChatHistory, 0, len(chatHistory))
-	for _, h := range chatHistory {
-		history = append(history, &pb.ChatHistory{
-			Role:    h.Role,
-			Content: h.Content,
-		})
-	}
 	if c.appLogger != nil {
 		c.appLogger.Info(
 			"internal.application.use_cases.orchestrator.chat.llmChat request context",
@@ -90,3 +71,108 @@ func (c *ChatbotHandler) llmChat(
 
 	return resp, nil
 }
+
+func (c *ChatbotHandler) llmChatStream(
+	ctx context.Context,
+	prompt string,
+	temperature float32,
+	model string,
+	query string,
+	imagePath string,
+	session_id string,
+	stage string,
+	onDelta func(delta string) error,
+) (*pb.LLMResponse, error) {
+	history, err := c.llmHistory(session_id)
+	if err != nil {
+		return nil, err
+	}
+	if c.appLogger != nil {
+		c.appLogger.Info(
+			"internal.application.use_cases.orchestrator.chat.llmChatStream request context",
+			"stage", stage,
+			"session_id", session_id,
+			"history_count", len(history),
+			"query_len", len(strings.TrimSpace(query)),
+		)
+	}
+
+	fullPrompt := strings.TrimSpace(prompt + " " + query)
+
+	var stream pb.LlmService_GenerateTextToTextStreamClient
+	if strings.TrimSpace(imagePath) != "" {
+		stream, err = c.LLMServiceClient.GenerateTextToImageStream(
+			ctx,
+			&pb.TextToImageRequest{
+				Model:       model,
+				Temperature: temperature,
+				Prompt:      fullPrompt,
+				History:     history,
+				ImagePath:   imagePath,
+			},
+		)
+	} else {
+		stream, err = c.LLMServiceClient.GenerateTextToTextStream(
+			ctx,
+			&pb.TextToTextRequest{
+				Model:       model,
+				Temperature: temperature,
+				Prompt:      fullPrompt,
+				History:     history,
+			},
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var builder strings.Builder
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if chunk.Done {
+			if strings.TrimSpace(chunk.Text) != "" {
+				builder.Reset()
+				builder.WriteString(chunk.Text)
+			}
+			continue
+		}
+		if chunk.Delta == "" {
+			continue
+		}
+		builder.WriteString(chunk.Delta)
+		if onDelta != nil {
+			if err := onDelta(chunk.Delta); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &pb.LLMResponse{Text: builder.String()}, nil
+}
+
+func (c *ChatbotHandler) llmHistory(session_id string) ([]*pb.ChatHistory, error) {
+	if c == nil || c.Session == nil {
+		return nil, errors.New("session store is not configured")
+	}
+
+	sessionData, err := c.Session.GetSession(session_id)
+	if err != nil {
+		return nil, err
+	}
+
+	chatHistory := make([]portsOrchestrator.ChatMessage, 0)
+	if sessionData.ChatHistory != nil {
+		chatHistory, err = sessionData.ChatHistory.GetChatHistory()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return historyWindow(chatHistory, sessionData.Metadata, c.Config.OrchestratorService.MemoryHistoryTopK), nil
+}
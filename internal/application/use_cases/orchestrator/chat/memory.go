@@ -0,0 +1,140 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	portsOrchestrator "rag_imagetotext_texttoimage/internal/application/ports/orchestrator"
+	pb "rag_imagetotext_texttoimage/proto"
+)
+
+const memorySummaryTimeout = 60 * time.Second
+
+const memorySummaryPrompt = "Ban dang duy tri ban tom tat cua mot cuoc hoi thoai giua nguoi dung va chatbot. " +
+	"Hay cap nhat ban tom tat hien tai bang cac luot hoi thoai moi ben duoi. " +
+	"Giu lai chu de, tai lieu, ten rieng, so lieu va cac yeu cau con dang mo; bo qua loi chao hoi. " +
+	"Chi tra ve ban tom tat, viet bang tieng Viet, khong qua %d ky tu."
+
+const memorySummaryHistoryPrefix = "Tom tat cac luot hoi thoai truoc do: "
+
+// historyWindow keeps the last topK turns (user + assistant pairs) verbatim
+// and puts the running summary of older turns in front of them.
+func historyWindow(messages []portsOrchestrator.ChatMessage, metadata portsOrchestrator.Metadata, topK int) []*pb.ChatHistory {
+	start := len(messages) - 2*memoryTopK(topK)
+	if start < 0 {
+		start = 0
+	}
+
+	history := make([]*pb.ChatHistory, 0, len(messages)-start+1)
+	summary := strings.TrimSpace(metadata.Summary)
+	if start > 0 && summary != "" && metadata.SummarizedMessages <= len(messages) {
+		history = append(history, &pb.ChatHistory{
+			Role:    "user",
+			Content: memorySummaryHistoryPrefix + summary,
+		})
+	}
+	for _, message := range messages[start:] {
+		history = append(history, &pb.ChatHistory{
+			Role:    message.Role,
+			Content: message.Content,
+		})
+	}
+	return history
+}
+
+// summarizeHistoryAsync folds the turns that fell out of the verbatim window
+// into the session summary. It runs after the answer is returned and at most
+// once per session at a time; a failed run is retried on the next turn.
+func (c *ChatbotHandler) summarizeHistoryAsync(sessionID string) {
+	if _, running := c.summarizing.LoadOrStore(sessionID, struct{}{}); running {
+		return
+	}
+	go func() {
+		defer c.summarizing.Delete(sessionID)
+		ctx, cancel := context.WithTimeout(context.Background(), memorySummaryTimeout)
+		defer cancel()
+		if err := c.summarizeHistory(ctx, sessionID); err != nil && c.appLogger != nil {
+			c.appLogger.Error("internal.application.use_cases.orchestrator.chat.summarizeHistory failed", err, "session_id", sessionID)
+		}
+	}()
+}
+
+func (c *ChatbotHandler) summarizeHistory(ctx context.Context, sessionID string) error {
+	sessionData, err := c.Session.GetSession(sessionID)
+	if err != nil {
+		return err
+	}
+	if sessionData.ChatHistory == nil {
+		return nil
+	}
+	messages, err := sessionData.ChatHistory.GetChatHistory()
+	if err != nil {
+		return err
+	}
+
+	metadata := sessionData.Metadata
+	from := metadata.SummarizedMessages
+	if from > len(messages) {
+		from = 0
+		metadata.Summary = ""
+	}
+	to := len(messages) - 2*memoryTopK(c.Config.OrchestratorService.MemoryHistoryTopK)
+	if to-from < 2 {
+		return nil
+	}
+
+	settings := c.Config.OrchestratorService.MemorySummary
+	var prompt strings.Builder
+	fmt.Fprintf(&prompt, memorySummaryPrompt, settings.MaxChars)
+	prompt.WriteString("\n\nBan tom tat hien tai:\n")
+	if summary := strings.TrimSpace(metadata.Summary); summary != "" {
+		prompt.WriteString(summary)
+	} else {
+		prompt.WriteString("(chua co)")
+	}
+	prompt.WriteString("\n\nCac luot hoi thoai moi:")
+	for _, message := range messages[from:to] {
+		fmt.Fprintf(&prompt, "\n%s: %s", message.Role, strings.TrimSpace(message.Content))
+	}
+
+	startedAt := time.Now()
+	resp, err := c.LLMServiceClient.GenerateTextToText(ctx, &pb.TextToTextRequest{
+		Model:       settings.Model,
+		Temperature: settings.Temperature,
+		Prompt:      prompt.String(),
+	})
+	if err != nil {
+		return err
+	}
+	summary := truncateRunes(strings.TrimSpace(resp.GetText()), settings.MaxChars)
+	if summary == "" {
+		return fmt.Errorf("llm returned an empty summary")
+	}
+
+	if err := c.Session.UpdateMetadata(sessionID, func(current *portsOrchestrator.Metadata) {
+		// Another summary landed or the history was cleared since we read
+		// it; drop ours. A clear can leave SummarizedMessages unchanged at 0,
+		// so the generation is what catches it.
+		if current.SummarizedMessages != sessionData.Metadata.SummarizedMessages ||
+			current.HistoryGeneration != sessionData.Metadata.HistoryGeneration {
+			return
+		}
+		current.Summary = summary
+		current.SummarizedMessages = to
+	}); err != nil {
+		return err
+	}
+	if c.appLogger != nil {
+		c.appLogger.Info(
+			"internal.application.use_cases.orchestrator.chat.summarizeHistory completed",
+			"session_id", sessionID,
+			"folded_messages", to-from,
+			"summarized_messages", to,
+			"summary_len", len(summary),
+			"latency_ms", time.Since(startedAt).Milliseconds(),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package chat
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	portsOrchestrator "rag_imagetotext_texttoimage/internal/application/ports/orchestrator"
+	"rag_imagetotext_texttoimage/internal/application/use_cases/orchestrator"
+	"rag_imagetotext_texttoimage/internal/util"
+	pb "rag_imagetotext_texttoimage/proto"
+)
+
+// summaryLLM answers every prompt with text after running during, which lets
+// a test change the session while a summary is being generated.
+type summaryLLM struct {
+	pb.LlmServiceClient
+	text   string
+	during func()
+}
+
+func (l *summaryLLM) GenerateTextToText(context.Context, *pb.TextToTextRequest, ...grpc.CallOption) (*pb.LLMResponse, error) {
+	if l.during != nil {
+		l.during()
+	}
+	return &pb.LLMResponse{Text: l.text}, nil
+}
+
+func newMemoryTestHandler(t *testing.T, llm pb.LlmServiceClient) (*ChatbotHandler, *orchestrator.InMemorySessionStore) {
+	t.Helper()
+	store := orchestrator.NewInMemorySessionStore(time.Hour)
+	t.Cleanup(store.Close)
+	var config util.Config
+	config.OrchestratorService.MemoryHistoryTopK = 1
+	config.OrchestratorService.MemorySummary.MaxChars = 500
+	return &ChatbotHandler{Session: store, Config: config, LLMServiceClient: llm}, store
+}
+
+func addTestMessages(t *testing.T, store *orchestrator.InMemorySessionStore, sessionID string, contents ...string) {
+	t.Helper()
+	data, err := store.GetSession(sessionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, content := range contents {
+		role := "user"
+		if i%2 == 1 {
+			role = "assistant"
+		}
+		if err := data.ChatHistory.AddMessage(portsOrchestrator.ChatMessage{Role: role, Content: content}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSummarizeHistorySavesSummary(t *testing.T) {
+	handler, store := newMemoryTestHandler(t, &summaryLLM{text: "summary"})
+	if err := store.CreateSession("s1"); err != nil {
+		t.Fatal(err)
+	}
+	addTestMessages(t, store, "s1", "q1", "a1", "q2", "a2", "q3", "a3")
+
+	if err := handler.summarizeHistory(context.Background(), "s1"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := store.GetSession("s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Metadata.Summary != "summary" || data.Metadata.SummarizedMessages != 4 {
+		t.Fatalf("metadata = %+v", data.Metadata)
+	}
+}
+
+func TestSummarizeHistoryDropsSummaryAfterClear(t *testing.T) {
+	llm := &summaryLLM{text: "summary of the cleared conversation"}
+	handler, store := newMemoryTestHandler(t, llm)
+	if err := store.CreateSession("s1"); err != nil {
+		t.Fatal(err)
+	}
+	addTestMessages(t, store, "s1", "q1", "a1", "q2", "a2", "q3", "a3")
+
+	sessions := orchestrator.NewSessionManager(store)
+	llm.during = func() {
+		if err := sessions.ClearSessionHistory("s1"); err != nil {
+			t.Error(err)
+		}
+		addTestMessages(t, store, "s1", "n1", "b1", "n2", "b2", "n3", "b3")
+	}
+	if err := handler.summarizeHistory(context.Background(), "s1"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := store.GetSession("s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Metadata.Summary != "" || data.Metadata.SummarizedMessages != 0 {
+		t.Fatalf("summary of the cleared history was saved: %+v", data.Metadata)
+	}
+	messages, err := data.ChatHistory.GetChatHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, message := range historyWindow(messages, data.Metadata, 1) {
+		if message.Content == memorySummaryHistoryPrefix+llm.text {
+			t.Fatal("cleared history leaked into the prompt")
+		}
+	}
+}
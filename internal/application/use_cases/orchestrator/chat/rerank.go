@@ -0,0 +1,241 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	pb "rag_imagetotext_texttoimage/proto"
+)
+
+const (
+	RerankNone         = "none"
+	RerankLLM          = "llm"
+	RerankCrossEncoder = "cross_encoder"
+)
+
+// llmRerankPassageMaxRunes caps each passage shown to the listwise LLM
+// reranker so a wide candidate list still fits in one prompt.
+const llmRerankPassageMaxRunes = 1200
+
+const llmRerankPrompt = `You are a search relevance judge. Rank the passages below by how well they help answer the question.
+Return JSON with a single field "Ranking": the passage numbers ordered from most to least relevant.
+Omit passages that are unrelated to the question.`
+
+func (c *ChatbotHandler) rerankStrategy() string {
+	strategy := strings.ToLower(strings.TrimSpace(c.Config.OrchestratorService.Rerank.Strategy))
+	switch strategy {
+	case RerankLLM, RerankCrossEncoder:
+		return strategy
+	default:
+		return RerankNone
+	}
+}
+
+// retrievalCandidateLimit is the Qdrant limit for each search: the wider
+// rerank.candidate_top_k when a reranker is enabled, otherwise rag_retrieval_top_k.
+func (c *ChatbotHandler) retrievalCandidateLimit() uint64 {
+	limit := ragRetrievalTopK(c.Config.OrchestratorService.RAGRetrievalTopK)
+	if c.rerankStrategy() == RerankNone {
+		return limit
+	}
+	if candidates := uint64(c.Config.OrchestratorService.Rerank.CandidateTopK); candidates > limit {
+		return candidates
+	}
+	return limit
+}
+
+// rerankRetrieval reorders each hit list against the query that produced it
+// and keeps the best rerank.top_n. Context assembly runs afterwards on the
+// reordered lists.
+func (c *ChatbotHandler) rerankRetrieval(
+	ctx context.Context,
+	sessionID string,
+	queries ExecuteQueries,
+	results RetrievalResult,
+) RetrievalResult {
+	if c.rerankStrategy() == RerankNone {
+		return results
+	}
+	imageQuery := queries.CurrentQuery.TextDense
+	if strings.TrimSpace(imageQuery) == "" {
+		imageQuery = queries.NewQuery.TextDense
+	}
+
+	results.NewQueryHits = c.rerankHits(ctx, sessionID, "new_query", queries.NewQuery.TextDense, results.NewQueryHits)
+	results.CurrentQueryHits = c.rerankHits(ctx, sessionID, "current_query", queries.CurrentQuery.TextDense, results.CurrentQueryHits)
+	results.MultimodalQueryHits = c.rerankHits(ctx, sessionID, "multimodal_query", imageQuery, results.MultimodalQueryHits)
+	return results
+}
+
+func (c *ChatbotHandler) rerankHits(
+	ctx context.Context,
+	sessionID string,
+	retrievalType string,
+	query string,
+	hits []*pb.SearchResultItem,
+) []*pb.SearchResultItem {
+	if len(hits) == 0 {
+		return hits
+	}
+	strategy := c.rerankStrategy()
+	startedAt := time.Now()
+
+	ranked := hits
+	query = strings.TrimSpace(query)
+	if query != "" && len(hits) > 1 {
+		var (
+			scores []float32
+			err    error
+		)
+		switch strategy {
+		case RerankLLM:
+			scores, err = c.rerankWithLLM(ctx, query, hits)
+		case RerankCrossEncoder:
+			scores, err = c.rerankWithCrossEncoder(ctx, query, hits)
+		}
+		if err != nil {
+			if c.appLogger != nil {
+				c.appLogger.Error(
+					"internal.application.use_cases.orchestrator.chat.rerank failed, keeping retrieval order",
+					err,
+					"session_id", sessionID,
+					"retrieval_type", retrievalType,
+					"strategy", strategy,
+				)
+			}
+		} else {
+			ranked = orderByScores(hits, scores)
+		}
+	}
+
+	topN := c.Config.OrchestratorService.Rerank.TopN
+	if topN <= 0 {
+		topN = int(ragRetrievalTopK(c.Config.OrchestratorService.RAGRetrievalTopK))
+	}
+	if len(ranked) > topN {
+		ranked = ranked[:topN]
+	}
+
+	if c.appLogger != nil {
+		c.appLogger.Debug(
+			"internal.application.use_cases.orchestrator.chat.rerank completed",
+			"session_id", sessionID,
+			"retrieval_type", retrievalType,
+			"strategy", strategy,
+			"candidate_count", len(hits),
+			"kept_count", len(ranked),
+			"top_n", topN,
+			"latency_ms", time.Since(startedAt).Milliseconds(),
+		)
+	}
+	return ranked
+}
+
+func (c *ChatbotHandler) rerankWithCrossEncoder(ctx context.Context, query string, hits []*pb.SearchResultItem) ([]float32, error) {
+	documents := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		documents = append(documents, strings.TrimSpace(hit.Payload["text"]))
+	}
+	resp, err := c.ModelDLServiceClient.Rerank(ctx, &pb.RerankRequest{
+		Query:     query,
+		Documents: documents,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.GetScores()) != len(hits) {
+		return nil, fmt.Errorf("cross encoder returned %d scores for %d documents", len(resp.GetScores()), len(hits))
+	}
+	return resp.GetScores(), nil
+}
+
+// rerankWithLLM asks the LLM for a listwise ranking of passage numbers and
+// turns it into descending scores. Passages the model leaves out score below
+// every ranked passage and keep their retrieval order.
+func (c *ChatbotHandler) rerankWithLLM(ctx context.Context, query string, hits []*pb.SearchResultItem) ([]float32, error) {
+	var prompt strings.Builder
+	prompt.WriteString(llmRerankPrompt)
+	prompt.WriteString("\n\nQuestion: ")
+	prompt.WriteString(query)
+	prompt.WriteString("\n\nPassages:")
+	for i, hit := range hits {
+		fmt.Fprintf(&prompt, "\n\n[%d] %s", i+1, truncateRunes(strings.TrimSpace(hit.Payload["text"]), llmRerankPassageMaxRunes))
+	}
+
+	settings := c.Config.OrchestratorService.Rerank
+	resp, err := c.LLMServiceClient.GenerateTextToText(ctx, &pb.TextToTextRequest{
+		Model:       settings.Model,
+		Temperature: settings.Temperature,
+		Prompt:      prompt.String(),
+		StructureOutput: map[string]string{
+			"Ranking": `{"type":"array","items":{"type":"integer"}}`,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	ranking, err := parseLLMRanking(resp.GetText())
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make([]float32, len(hits))
+	for i := range scores {
+		scores[i] = -1
+	}
+	position := 0
+	for _, n := range ranking {
+		idx := n - 1
+		if idx < 0 || idx >= len(hits) || scores[idx] >= 0 {
+			continue
+		}
+		scores[idx] = 1 - float32(position)/float32(len(hits))
+		position++
+	}
+	if position == 0 {
+		return nil, fmt.Errorf("llm ranking has no valid passage numbers: %v", ranking)
+	}
+	return scores, nil
+}
+
+func parseLLMRanking(raw string) ([]int, error) {
+	text := strings.TrimSpace(raw)
+	text = strings.TrimPrefix(text, "```json")
+	text = strings.TrimPrefix(text, "```")
+	text = strings.TrimSuffix(text, "```")
+	var out struct {
+		Ranking []int `json:"Ranking"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(text)), &out); err != nil {
+		return nil, fmt.Errorf("decode llm ranking: %w", err)
+	}
+	return out.Ranking, nil
+}
+
+// orderByScores sorts hits by score, highest first; ties keep retrieval order.
+func orderByScores(hits []*pb.SearchResultItem, scores []float32) []*pb.SearchResultItem {
+	order := make([]int, len(hits))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return scores[order[a]] > scores[order[b]]
+	})
+	out := make([]*pb.SearchResultItem, 0, len(hits))
+	for _, idx := range order {
+		out = append(out, hits[idx])
+	}
+	return out
+}
+
+func truncateRunes(text string, max int) string {
+	if utf8.RuneCountInString(text) <= max {
+		return text
+	}
+	return string([]rune(text)[:max]) + "..."
+}
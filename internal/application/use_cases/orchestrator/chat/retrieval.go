@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"rag_imagetotext_texttoimage/internal/util"
 	pb "rag_imagetotext_texttoimage/proto"
 )
 
@@ -24,70 +25,52 @@ func runRetrieval[Resp any](
 	assign(resp)
 }
 
+// retrieval runs every per-collection search concurrently, then merges each
+// query type across collections by score (or round-robin per collection when
+// balanced) and cuts it back to limit. The merged context items are filled in
+// later by assembleContexts.
 func (c *ChatbotHandler) retrieval(
 	ctx context.Context,
 	wg *sync.WaitGroup,
-	retrievalRequest RetrievalRequest,
+	retrievalRequests []RetrievalRequest,
+	limit int,
+	balanced bool,
 ) (RetrievalResult, error) {
 	var results RetrievalResult
 	var resultsMu sync.Mutex
-	errCh := make(chan error, 3)
+	var newQueryHits, currentQueryHits, multimodalQueryHits []*pb.SearchResultItem
+	errCh := make(chan error, 3*len(retrievalRequests))
 	defer wg.Done()
 
 	var retrievalWg sync.WaitGroup
 
-	if retrievalRequest.NewQuery != nil {
-		retrievalWg.Add(1)
-		go runRetrieval(
-			&retrievalWg,
-			errCh,
-			func() (*pb.ResponseSearchPoint, error) {
-				return c.searchPointWithDebug(ctx, "new_query", retrievalRequest.NewQuery)
-			},
-			func(resp *pb.ResponseSearchPoint) {
-				if resp != nil && len(resp.Results) > 0 {
-					resultsMu.Lock()
-					defer resultsMu.Unlock()
-					results.NewQuery = mergeResultsForContext(resp.Results)
-				}
-			},
-		)
-	}
-
-	if retrievalRequest.CurrentQuery != nil {
+	search := func(retrievalType string, req *pb.SearchPointRequest, hits *[]*pb.SearchResultItem) {
+		if req == nil {
+			return
+		}
 		retrievalWg.Add(1)
 		go runRetrieval(
 			&retrievalWg,
 			errCh,
 			func() (*pb.ResponseSearchPoint, error) {
-				return c.searchPointWithDebug(ctx, "current_query", retrievalRequest.CurrentQuery)
+				return c.searchPointWithDebug(ctx, retrievalType, req)
 			},
 			func(resp *pb.ResponseSearchPoint) {
 				if resp != nil && len(resp.Results) > 0 {
+					tagCollection(resp.Results, req.CollectionName)
+					resolveFigureHits(resp.Results)
 					resultsMu.Lock()
 					defer resultsMu.Unlock()
-					results.CurrentQuery = mergeResultsForContext(resp.Results)
+					*hits = append(*hits, resp.Results...)
 				}
 			},
 		)
 	}
 
-	if retrievalRequest.MultimodalQuery != nil {
-		retrievalWg.Add(1)
-		go runRetrieval(
-			&retrievalWg,
-			errCh,
-			func() (*pb.ResponseSearchPoint, error) {
-				return c.searchPointWithDebug(ctx, "multimodal_query", retrievalRequest.MultimodalQuery)
-			},
-			func(resp *pb.ResponseSearchPoint) {
-				if resp != nil && len(resp.Results) > 0 {
-					resultsMu.Lock()
-					defer resultsMu.Unlock()
-					results.MultimodelQuery = mergeResultsForContext(resp.Results)
-				}
-			},
-		)
+	for _, retrievalRequest := range retrievalRequests {
+		search("new_query", retrievalRequest.NewQuery, &newQueryHits)
+		search("current_query", retrievalRequest.CurrentQuery, &currentQueryHits)
+		search("multimodal_query", retrievalRequest.MultimodalQuery, &multimodalQueryHits)
 	}
 
 	retrievalWg.Wait()
@@ -99,6 +82,14 @@ func (c *ChatbotHandler) retrieval(
 		}
 	}
 
+	merge := mergeCollectionHits
+	if balanced {
+		merge = mergeBalancedHits
+	}
+	results.NewQueryHits = merge(newQueryHits, limit)
+	results.CurrentQueryHits = merge(currentQueryHits, limit)
+	results.MultimodalQueryHits = merge(multimodalQueryHits, limit)
+
 	return results, nil
 }
 
@@ -157,12 +148,85 @@ func (c *ChatbotHandler) searchPointWithDebug(
 			"result_count", resultCount,
 			"top_score", topScore,
 			"top_payload_preview", payloadPreview,
+			"fusion_strategy", resp.GetFusionStrategy(),
+			"normalization_mode", resp.GetNormalizationMode(),
+			"vector_normalization", resp.GetVectorNormalization(),
 			"latency_ms", latency,
 		)
 	}
 	return resp, nil
 }
 
+// fusionFor returns the fusion settings for a collection: the global
+// retrieval.fusion block with any retrieval.collections.<name> overrides.
+func (c *ChatbotHandler) fusionFor(collectionName string) util.RetrievalFusion {
+	retrieval := c.Config.OrchestratorService.Retrieval
+	fusion := retrieval.Fusion
+	override, ok := retrieval.Collections[collectionName]
+	if !ok {
+		return fusion
+	}
+	if strings.TrimSpace(override.Strategy) != "" {
+		fusion.Strategy = override.Strategy
+	}
+	if override.RRFK > 0 {
+		fusion.RRFK = override.RRFK
+	}
+	if len(override.VectorWeights) > 0 {
+		fusion.VectorWeights = override.VectorWeights
+	}
+	return fusion
+}
+
+// contextScoreFloor puts minContextScore on the scale of the fusion strategy
+// used for the collections. With several collections the lowest floor is
+// used, so no collection loses its hits only because of its strategy.
+func (c *ChatbotHandler) contextScoreFloor(collectionNames []string) float32 {
+	if len(collectionNames) == 0 {
+		return minContextScore
+	}
+	floor := fusionContextScore(c.fusionFor(collectionNames[0]))
+	for _, collectionName := range collectionNames[1:] {
+		floor = min(floor, fusionContextScore(c.fusionFor(collectionName)))
+	}
+	return floor
+}
+
+// fusionContextScore maps minContextScore to one fusion strategy. The rag
+// service normalizes the vector weights to sum to 1, so:
+//   - weighted_minmax scores lie in [0,1] and are used as is;
+//   - rrf scores are at most 1/(rrf_k+1), for a point ranked first by every
+//     vector, and the floor is scaled down by the same factor;
+//   - dbsf puts the mean of each result list at 0.5 and mean+3σ at 1, so a
+//     fixed 0.55 would pass nearly any top hit; a hit must instead stand one
+//     σ above the mean.
+func fusionContextScore(fusion util.RetrievalFusion) float32 {
+	switch strings.ToLower(strings.TrimSpace(fusion.Strategy)) {
+	case "rrf":
+		k := fusion.RRFK
+		if k <= 0 {
+			k = defaultRRFK
+		}
+		return minContextScore / (k + 1)
+	case "dbsf":
+		return dbsfMinContextScore
+	default:
+		return minContextScore
+	}
+}
+
+func applyFusion(req *pb.SearchPointRequest, fusion util.RetrievalFusion) {
+	if req == nil {
+		return
+	}
+	req.FusionStrategy = strings.TrimSpace(fusion.Strategy)
+	req.VectorWeights = fusion.VectorWeights
+	if fusion.RRFK > 0 {
+		rrfK := fusion.RRFK
+		req.RrfK = &rrfK
+	}
+}
+
 func safeCollectionName(req *pb.SearchPointRequest) string {
 	if req == nil {
 		return ""
@@ -196,21 +260,27 @@ func previewPayload(in string) string {
 	return trimmed[:max] + "..."
 }
 
-func mergeResultsForContext(results []*pb.SearchResultItem) *pb.SearchResultItem {
-	if len(results) == 0 {
-		return nil
-	}
-	base := results[0]
-	if base == nil {
-		return nil
-	}
-
-	payload := map[string]string{}
-	for k, v := range base.Payload {
-		payload[k] = v
+// resolveFigureHits answers a figure hit with its parent chunk. The figure
+// payload already carries the parent text; taking the parent id makes the hit
+// collapse with a hit on the chunk itself, while image_path and object_key
+// still point at the matched figure.
+func resolveFigureHits(results []*pb.SearchResultItem) {
+	for _, item := range results {
+		if item == nil || item.Payload["unit_type"] != "figure" {
+			continue
+		}
+		parentID := strings.TrimSpace(item.Payload["parent_id"])
+		if parentID == "" {
+			continue
+		}
+		item.Payload["figure_id"] = item.Id
+		item.Id = parentID
 	}
+}
 
-	parts := make([]string, 0, len(results))
+// dedupeResultsByText keeps the first hit for each distinct text, in rank order.
+func dedupeResultsByText(results []*pb.SearchResultItem) []*pb.SearchResultItem {
+	out := make([]*pb.SearchResultItem, 0, len(results))
 	seen := map[string]struct{}{}
 	for _, item := range results {
 		if item == nil {
@@ -224,15 +294,7 @@ func mergeResultsForContext(results []*pb.SearchResultItem) *pb.SearchResultItem
 			continue
 		}
 		seen[text] = struct{}{}
-		parts = append(parts, text)
-	}
-	if len(parts) > 0 {
-		payload["text"] = strings.Join(parts, "\n\n---\n\n")
-	}
-
-	return &pb.SearchResultItem{
-		Id:      base.Id,
-		Score:   base.Score,
-		Payload: payload,
+		out = append(out, item)
 	}
+	return out
 }
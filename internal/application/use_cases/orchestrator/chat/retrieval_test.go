@@ -0,0 +1,96 @@
+package chat
+
+import (
+	"math"
+	"testing"
+
+	"rag_imagetotext_texttoimage/internal/util"
+)
+
+func TestFusionContextScore(t *testing.T) {
+	tests := []struct {
+		name   string
+		fusion util.RetrievalFusion
+		want   float64
+	}{
+		{name: "default", fusion: util.RetrievalFusion{}, want: 0.55},
+		{name: "weighted_minmax", fusion: util.RetrievalFusion{Strategy: "weighted_minmax"}, want: 0.55},
+		{name: "unknown strategy", fusion: util.RetrievalFusion{Strategy: "borda"}, want: 0.55},
+		{name: "rrf default k", fusion: util.RetrievalFusion{Strategy: "rrf"}, want: 0.55 / 61},
+		{name: "rrf configured k", fusion: util.RetrievalFusion{Strategy: " RRF ", RRFK: 10}, want: 0.05},
+		{name: "dbsf", fusion: util.RetrievalFusion{Strategy: "dbsf"}, want: 2.0 / 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fusionContextScore(tt.fusion); math.Abs(float64(got)-tt.want) > 1e-6 {
+				t.Fatalf("floor = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFusionContextScoreHits pins which fused scores reach the LLM. The
+// scores are what the rag service returns for the named result lists.
+func TestFusionContextScoreHits(t *testing.T) {
+	const k = 60
+	tests := []struct {
+		name     string
+		strategy string
+		score    float64
+		pass     bool
+	}{
+		{name: "minmax: top of a list", strategy: "weighted_minmax", score: 1, pass: true},
+		{name: "minmax: single result", strategy: "weighted_minmax", score: 1, pass: true},
+		{name: "minmax: flat list", strategy: "weighted_minmax", score: 1, pass: true},
+		{name: "minmax: below the floor", strategy: "weighted_minmax", score: 0.5, pass: false},
+
+		{name: "rrf: single result in a single vector", strategy: "rrf", score: 1.0 / (k + 1), pass: true},
+		{name: "rrf: first in both of two vectors", strategy: "rrf", score: 0.5/(k+1) + 0.5/(k+1), pass: true},
+		{name: "rrf: flat list, first and second in both vectors", strategy: "rrf", score: 0.5/(k+1) + 0.5/(k+2), pass: true},
+		{name: "rrf: first in one of two vectors", strategy: "rrf", score: 0.5 / (k + 1), pass: false},
+
+		{name: "dbsf: one σ above the mean", strategy: "dbsf", score: 0.5 + 1.0/6, pass: true},
+		{name: "dbsf: top of two results", strategy: "dbsf", score: 2.0 / 3, pass: true},
+		{name: "dbsf: at the mean", strategy: "dbsf", score: 0.5, pass: false},
+		// A single result or a flat list keeps its raw similarity, which
+		// is then held to the dbsf floor.
+		{name: "dbsf: single result, raw 0.8", strategy: "dbsf", score: 0.8, pass: true},
+		{name: "dbsf: flat list, raw 0.6", strategy: "dbsf", score: 0.6, pass: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			floor := fusionContextScore(util.RetrievalFusion{Strategy: tt.strategy, RRFK: k})
+			if pass := float32(tt.score) >= floor; pass != tt.pass {
+				t.Fatalf("score %v against floor %v: pass = %v, want %v", tt.score, floor, pass, tt.pass)
+			}
+		})
+	}
+}
+
+func TestContextScoreFloor(t *testing.T) {
+	handler := &ChatbotHandler{}
+	handler.Config.OrchestratorService.Retrieval = util.RetrievalSettings{
+		Fusion: util.RetrievalFusion{Strategy: "weighted_minmax"},
+		Collections: map[string]util.RetrievalFusion{
+			"ranked": {Strategy: "rrf", RRFK: 10},
+			"dist":   {Strategy: "dbsf"},
+		},
+	}
+	tests := []struct {
+		name        string
+		collections []string
+		want        float64
+	}{
+		{name: "no collections", collections: nil, want: 0.55},
+		{name: "global strategy", collections: []string{"plain"}, want: 0.55},
+		{name: "override", collections: []string{"dist"}, want: 2.0 / 3},
+		{name: "lowest floor wins", collections: []string{"plain", "dist", "ranked"}, want: 0.05},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := handler.contextScoreFloor(tt.collections); math.Abs(float64(got)-tt.want) > 1e-6 {
+				t.Fatalf("floor = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package chat
+
+import (
+	"context"
+	"path"
+	"strconv"
+	"strings"
+
+	dtoOrchestrator "rag_imagetotext_texttoimage/internal/application/dtos/orchestrator"
+	pb "rag_imagetotext_texttoimage/proto"
+)
+
+func contextHits(results RetrievalResult, contextSource string) []*pb.SearchResultItem {
+	switch contextSource {
+	case "new_query":
+		return results.NewQueryHits
+	case "current_query":
+		return results.CurrentQueryHits
+	case "multimodal_query":
+		return results.MultimodalQueryHits
+	}
+	return nil
+}
+
+func (c *ChatbotHandler) buildSources(ctx context.Context, hits []*pb.SearchResultItem, contextSource string) []dtoOrchestrator.ChatSource {
+	if len(hits) == 0 {
+		return nil
+	}
+	sources := make([]dtoOrchestrator.ChatSource, 0, len(hits))
+	for i, hit := range hits {
+		if hit == nil {
+			continue
+		}
+		source := dtoOrchestrator.ChatSource{
+			Index:         i + 1,
+			DocID:         strings.TrimSpace(hit.Payload["doc_id"]),
+			Collection:    strings.TrimSpace(hit.Payload["collection"]),
+			SectionTitle:  strings.TrimSpace(hit.Payload["section_title"]),
+			Score:         hit.Score,
+			ContextSource: contextSource,
+			ImagePath:     strings.TrimSpace(hit.Payload["image_path"]),
+		}
+		if chunkIndex, err := strconv.Atoi(strings.TrimSpace(hit.Payload["chunk_index"])); err == nil {
+			source.ChunkIndex = chunkIndex
+		}
+		if page, err := strconv.Atoi(strings.TrimSpace(hit.Payload["page"])); err == nil {
+			source.Page = page
+		}
+		if objectKey := strings.TrimSpace(hit.Payload["object_key"]); objectKey != "" {
+			if url := c.presignObject(ctx, objectKey); url != "" {
+				source.ImageURLs = append(source.ImageURLs, url)
+			}
+		} else {
+			for _, imagePath := range strings.Split(source.ImagePath, ",") {
+				imagePath = strings.TrimSpace(imagePath)
+				if imagePath == "" {
+					continue
+				}
+				if url := c.presignImage(ctx, source.DocID, imagePath); url != "" {
+					source.ImageURLs = append(source.ImageURLs, url)
+				}
+			}
+		}
+		sources = append(sources, source)
+	}
+	return sources
+}
+
+// presignImage mirrors the object key layout used when ingest uploads
+// artifacts to MinIO: <doc_id>/<file name>.
+func (c *ChatbotHandler) presignImage(ctx context.Context, docID string, imagePath string) string {
+	if c == nil || c.MinioServiceClient == nil || docID == "" {
+		return ""
+	}
+	return c.presignObject(ctx, path.Join(docID, path.Base(strings.ReplaceAll(imagePath, "\\", "/"))))
+}
+
+func (c *ChatbotHandler) presignObject(ctx context.Context, objectKey string) string {
+	if c == nil || c.MinioServiceClient == nil || objectKey == "" {
+		return ""
+	}
+	resp, err := c.MinioServiceClient.PresignUploadURL(ctx, &pb.PresignUploadURLRequest{ObjectKey: objectKey})
+	if err != nil {
+		if c.appLogger != nil {
+			c.appLogger.Error("internal.application.use_cases.orchestrator.chat.presignObject failed", err, "object_key", objectKey)
+		}
+		return ""
+	}
+	if resp == nil || !resp.Success {
+		return ""
+	}
+	return resp.Url
+}
@@ -90,3 +90,10 @@ func NewDeepLearningServiceClient(
 ) (pb.DeepLearningServiceClient, *grpc.ClientConn, error) {
 	return NewGRPCClient(ctx, host, port, pb.NewDeepLearningServiceClient)
 }
+
+func NewMinioServiceClient(
+	ctx context.Context,
+	host, port string,
+) (pb.MinioServiceClient, *grpc.ClientConn, error) {
+	return NewGRPCClient(ctx, host, port, pb.NewMinioServiceClient)
+}
@@ -7,6 +7,8 @@ import (
 	"rag_imagetotext_texttoimage/internal/application/ports/orchestrator"
 )
 
+var _ orchestrator.ManagedSessionStore = (*InMemorySessionStore)(nil)
+
 type InMemoryChatMessageManager struct {
 	mu      sync.RWMutex
 	Message []orchestrator.ChatMessage
@@ -63,15 +65,17 @@ func (s *InMemorySessionStore) CreateSession(sessionID string) error {
 	defer s.mu.Unlock()
 
 	now := time.Now()
+	expiresAt := now.Add(s.sessionTTL)
 	s.Sessions[sessionID] = inMemorySessionEntry{
 		Data: orchestrator.SessionData{
 			SessionID:   sessionID,
 			ChatHistory: NewInMemoryChatMessageManager(),
 			Metadata: orchestrator.Metadata{
 				CreatedAt: now,
+				ExpiresAt: expiresAt,
 			},
 		},
-		ExpiresAt: now.Add(s.sessionTTL),
+		ExpiresAt: expiresAt,
 	}
 	return nil
 }
@@ -94,7 +98,11 @@ func (s *InMemorySessionStore) GetSession(sessionID string) (orchestrator.Sessio
 		return orchestrator.SessionData{}, nil
 	}
 
-	entry.ExpiresAt = time.Now().Add(s.sessionTTL)
+	// Sliding expiry never shortens a TTL set by ExtendSession.
+	if next := time.Now().Add(s.sessionTTL); next.After(entry.ExpiresAt) {
+		entry.ExpiresAt = next
+	}
+	entry.Data.Metadata.ExpiresAt = entry.ExpiresAt
 	s.Sessions[sessionID] = entry
 	s.mu.Unlock()
 
@@ -137,6 +145,71 @@ func (s *InMemorySessionStore) SessionExists(sessionID string) (bool, error) {
 	return exists, nil
 }
 
+func (s *InMemorySessionStore) ListSessions() ([]orchestrator.SessionSummary, error) {
+	s.mu.RLock()
+	entries := make([]inMemorySessionEntry, 0, len(s.Sessions))
+	now := time.Now()
+	for _, entry := range s.Sessions {
+		if now.After(entry.ExpiresAt) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	s.mu.RUnlock()
+
+	summaries := make([]orchestrator.SessionSummary, 0, len(entries))
+	for _, entry := range entries {
+		messageCount := 0
+		if entry.Data.ChatHistory != nil {
+			history, err := entry.Data.ChatHistory.GetChatHistory()
+			if err != nil {
+				return nil, err
+			}
+			messageCount = len(history)
+		}
+		summaries = append(summaries, orchestrator.SessionSummary{
+			SessionID:    entry.Data.SessionID,
+			Metadata:     entry.Data.Metadata,
+			MessageCount: messageCount,
+		})
+	}
+	return summaries, nil
+}
+
+func (s *InMemorySessionStore) ExtendSession(sessionID string, ttl time.Duration) (time.Time, error) {
+	if ttl <= 0 {
+		ttl = s.sessionTTL
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, exists := s.Sessions[sessionID]
+	if !exists || time.Now().After(entry.ExpiresAt) {
+		return time.Time{}, orchestrator.ErrSessionNotFound
+	}
+	entry.ExpiresAt = time.Now().Add(ttl)
+	entry.Data.Metadata.ExpiresAt = entry.ExpiresAt
+	s.Sessions[sessionID] = entry
+	return entry.ExpiresAt, nil
+}
+
+func (s *InMemorySessionStore) UpdateMetadata(sessionID string, update func(metadata *orchestrator.Metadata)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, exists := s.Sessions[sessionID]
+	if !exists {
+		return orchestrator.ErrSessionNotFound
+	}
+	metadata := entry.Data.Metadata
+	update(&metadata)
+	metadata.CreatedAt = entry.Data.Metadata.CreatedAt
+	metadata.ExpiresAt = entry.Data.Metadata.ExpiresAt
+	entry.Data.Metadata = metadata
+	s.Sessions[sessionID] = entry
+	return nil
+}
+
 func (s *InMemorySessionStore) Close() {
 	if s == nil {
 		return
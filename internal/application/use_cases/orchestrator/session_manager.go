@@ -0,0 +1,128 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	dtoOrchestrator "rag_imagetotext_texttoimage/internal/application/dtos/orchestrator"
+	"rag_imagetotext_texttoimage/internal/application/ports/orchestrator"
+)
+
+// SessionManager backs the session admin endpoints. Reading a session's
+// history goes through GetSession, so it refreshes the TTL like a chat turn.
+type SessionManager struct {
+	store orchestrator.ManagedSessionStore
+}
+
+func NewSessionManager(store orchestrator.ManagedSessionStore) *SessionManager {
+	return &SessionManager{store: store}
+}
+
+func (m *SessionManager) ListSessions() (*dtoOrchestrator.ListSessionsResponse, error) {
+	if m == nil || m.store == nil {
+		return nil, errors.New("session store is not configured")
+	}
+	summaries, err := m.store.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Metadata.CreatedAt.After(summaries[j].Metadata.CreatedAt)
+	})
+
+	sessions := make([]dtoOrchestrator.SessionInfo, 0, len(summaries))
+	for _, summary := range summaries {
+		sessions = append(sessions, toSessionInfo(summary.SessionID, summary.Metadata, summary.MessageCount))
+	}
+	return &dtoOrchestrator.ListSessionsResponse{Sessions: sessions, Total: len(sessions)}, nil
+}
+
+func (m *SessionManager) GetSessionHistory(sessionID string) (*dtoOrchestrator.SessionHistoryResponse, error) {
+	sessionData, err := m.getSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	history, err := sessionData.ChatHistory.GetChatHistory()
+	if err != nil {
+		return nil, err
+	}
+	messages := make([]dtoOrchestrator.SessionMessage, 0, len(history))
+	for _, message := range history {
+		messages = append(messages, dtoOrchestrator.SessionMessage{Role: message.Role, Content: message.Content})
+	}
+	return &dtoOrchestrator.SessionHistoryResponse{
+		SessionInfo: toSessionInfo(sessionData.SessionID, sessionData.Metadata, len(history)),
+		Messages:    messages,
+	}, nil
+}
+
+// ClearSessionHistory empties the history and then resets the summary. The
+// generation bump comes last so a summarizer that read the old messages
+// always sees it changed when it saves.
+func (m *SessionManager) ClearSessionHistory(sessionID string) error {
+	sessionData, err := m.getSession(sessionID)
+	if err != nil {
+		return err
+	}
+	if err := sessionData.ChatHistory.ClearChatHistory(); err != nil {
+		return err
+	}
+	return m.store.UpdateMetadata(sessionData.SessionID, func(metadata *orchestrator.Metadata) {
+		metadata.Summary = ""
+		metadata.SummarizedMessages = 0
+		metadata.HistoryGeneration++
+	})
+}
+
+func (m *SessionManager) DeleteSession(sessionID string) error {
+	if m == nil || m.store == nil {
+		return errors.New("session store is not configured")
+	}
+	sessionID = strings.TrimSpace(sessionID)
+	exists, err := m.store.SessionExists(sessionID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s", orchestrator.ErrSessionNotFound, sessionID)
+	}
+	return m.store.DeleteSession(sessionID)
+}
+
+// ExtendSession sets the expiry to now+ttl; a zero ttl uses the store's
+// configured session TTL.
+func (m *SessionManager) ExtendSession(sessionID string, ttl time.Duration) (time.Time, error) {
+	if m == nil || m.store == nil {
+		return time.Time{}, errors.New("session store is not configured")
+	}
+	return m.store.ExtendSession(strings.TrimSpace(sessionID), ttl)
+}
+
+func (m *SessionManager) getSession(sessionID string) (orchestrator.SessionData, error) {
+	if m == nil || m.store == nil {
+		return orchestrator.SessionData{}, errors.New("session store is not configured")
+	}
+	sessionID = strings.TrimSpace(sessionID)
+	sessionData, err := m.store.GetSession(sessionID)
+	if err != nil {
+		return orchestrator.SessionData{}, err
+	}
+	if sessionData.SessionID == "" || sessionData.ChatHistory == nil {
+		return orchestrator.SessionData{}, fmt.Errorf("%w: %s", orchestrator.ErrSessionNotFound, sessionID)
+	}
+	return sessionData, nil
+}
+
+func toSessionInfo(sessionID string, metadata orchestrator.Metadata, messageCount int) dtoOrchestrator.SessionInfo {
+	return dtoOrchestrator.SessionInfo{
+		SessionID:    sessionID,
+		CreatedAt:    metadata.CreatedAt,
+		ExpiresAt:    metadata.ExpiresAt,
+		MessageCount: messageCount,
+		Collection:   metadata.Collection,
+		Summary:      metadata.Summary,
+	}
+}
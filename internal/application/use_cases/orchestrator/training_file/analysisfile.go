@@ -4,11 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os"
-	"os/exec"
-	"path/filepath"
-	"runtime"
-	"strconv"
 	"strings"
 
 	"rag_imagetotext_texttoimage/internal/application/dtos"
@@ -47,71 +42,43 @@ func (T *trainingFileUseCase) AnalysisFile(ctx context.Context, req *dtos.Analys
 		return dtos.AnalysisFileResult{Success: status}, err
 	}
 
-	scriptPath, err := resolveTrainingScriptPath()
-	if err != nil {
-		T.logger.Error("internal.application.use_cases.orchestrator.training_file.AnalysisFile resolve script path failed", err)
+	if T.documentParser == nil {
+		err := errors.New("document parser is not configured")
+		T.logger.Error("internal.application.use_cases.orchestrator.training_file.AnalysisFile document parser missing", err)
 		return dtos.AnalysisFileResult{Success: false}, err
 	}
 
-	cmd := exec.CommandContext(
-		ctx,
-		"bash",
-		scriptPath,
-		"-pathfilesrc", req.FilePath,
-		"-destdir", req.DistDir,
-		"-dev", strconv.FormatBool(req.Dev),
-	)
-	cmd.Env = os.Environ()
-
-	output, err := cmd.CombinedOutput()
-	outputText := strings.TrimSpace(string(output))
-	if outputText != "" {
-		T.logger.Info(
-			"internal.application.use_cases.orchestrator.training_file.AnalysisFile script output",
-			"output", outputText,
-			"script_path", scriptPath,
-		)
-	}
-
+	parsed, err := T.documentParser.Parse(ctx, dtos.ParseDocumentRequest{
+		FilePath:  req.FilePath,
+		OutputDir: req.DistDir,
+		Dev:       req.Dev,
+	})
 	if err != nil {
-		wrappedErr := fmt.Errorf("analysis file script execution failed: %w", err)
+		wrappedErr := fmt.Errorf("analysis file parse failed: %w", err)
 		T.logger.Error(
-			"internal.application.use_cases.orchestrator.training_file.AnalysisFile script execution failed",
+			"internal.application.use_cases.orchestrator.training_file.AnalysisFile parse failed",
 			wrappedErr,
-			"script_path", scriptPath,
+			"parser", parsed.Parser,
 			"file_path", req.FilePath,
 			"dest_dir", req.DistDir,
 			"dev", req.Dev,
 		)
-		return dtos.AnalysisFileResult{Success: false}, wrappedErr
+		return dtos.AnalysisFileResult{Success: false, Parser: parsed.Parser}, wrappedErr
 	}
 
 	T.logger.Info(
-		"internal.application.use_cases.orchestrator.training_file.AnalysisFile script execution succeeded",
-		"script_path", scriptPath,
+		"internal.application.use_cases.orchestrator.training_file.AnalysisFile parse succeeded",
+		"parser", parsed.Parser,
 		"file_path", req.FilePath,
 		"dest_dir", req.DistDir,
+		"markdown_path", parsed.MarkdownPath,
+		"image_count", len(parsed.ImagePaths),
 		"dev", req.Dev,
 	)
 
-	return dtos.AnalysisFileResult{Success: true}, nil
-}
-
-func resolveTrainingScriptPath() (string, error) {
-	_, currentFile, _, ok := runtime.Caller(0)
-	if !ok {
-		return "", errors.New("cannot resolve caller path for analysisfile")
-	}
-
-	scriptPath := filepath.Join(filepath.Dir(currentFile), "marker_single_file.sh")
-	absPath, err := filepath.Abs(scriptPath)
-	if err != nil {
-		return "", fmt.Errorf("resolve absolute script path failed: %w", err)
-	}
-
-	if _, err := os.Stat(absPath); err != nil {
-		return "", fmt.Errorf("script not found at %s: %w", absPath, err)
-	}
-
-	return absPath, nil
+	return dtos.AnalysisFileResult{
+		Success:      true,
+		Parser:       parsed.Parser,
+		MarkdownPath: parsed.MarkdownPath,
+	}, nil
 }
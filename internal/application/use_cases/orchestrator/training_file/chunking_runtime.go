@@ -12,6 +12,7 @@ func (uc *trainingFileUseCase) resolveChunkingConfig() chunkingRuntimeConfig {
 		MaxChunkTokens:        uc.Config.FileTraining.MaxChunkTokens,
 		SemanticSimThreshold:  uc.Config.FileTraining.SemanticSimilarityThreshold,
 		ChunkOverlapSentences: uc.Config.FileTraining.ChunkOverlapSentences,
+		MaxTableRows:          uc.Config.FileTraining.MaxTableRows,
 	}
 
 	if cfg.MinChunkChars <= 0 {
@@ -32,6 +33,9 @@ func (uc *trainingFileUseCase) resolveChunkingConfig() chunkingRuntimeConfig {
 	if cfg.ChunkOverlapSentences == 0 {
 		cfg.ChunkOverlapSentences = defaultChunkOverlap
 	}
+	if cfg.MaxTableRows <= 0 {
+		cfg.MaxTableRows = defaultMaxTableRows
+	}
 	return cfg
 }
 
@@ -0,0 +1,410 @@
+package trainingfile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"rag_imagetotext_texttoimage/internal/application/dtos"
+	"rag_imagetotext_texttoimage/internal/application/ports"
+	"rag_imagetotext_texttoimage/internal/util"
+)
+
+const (
+	defaultIngestJobWorkers   = 2
+	defaultIngestJobQueueSize = 32
+	defaultIngestJobRetention = 24 * time.Hour
+)
+
+// progressIngester is implemented by trainingFileUseCase; other
+// TrainingFileUseCase implementations run without per-step progress.
+type progressIngester interface {
+	processAndIngest(ctx context.Context, req *dtos.ProcessAndIngestRequest, progress ingestProgress) (dtos.ProcessAndIngestResult, error)
+}
+
+type ingestJob struct {
+	mu     sync.Mutex
+	state  dtos.IngestJob
+	req    dtos.ProcessAndIngestRequest
+	cancel context.CancelFunc
+}
+
+type ingestJobManager struct {
+	logger    util.Logger
+	useCase   ports.TrainingFileUseCase
+	retention time.Duration
+
+	ctx    context.Context
+	stop   context.CancelFunc
+	queue  chan *ingestJob
+	wg     sync.WaitGroup
+	closed sync.Once
+
+	mu       sync.Mutex
+	jobs     map[string]*ingestJob
+	isClosed bool
+}
+
+func NewIngestJobManager(logger util.Logger, useCase ports.TrainingFileUseCase, settings util.IngestJobSettings) ports.IngestJobManager {
+	workers := settings.Workers
+	if workers <= 0 {
+		workers = defaultIngestJobWorkers
+	}
+	queueSize := settings.QueueSize
+	if queueSize <= 0 {
+		queueSize = defaultIngestJobQueueSize
+	}
+	retention := time.Duration(settings.RetentionSeconds) * time.Second
+	if retention <= 0 {
+		retention = defaultIngestJobRetention
+	}
+
+	ctx, stop := context.WithCancel(context.Background())
+	m := &ingestJobManager{
+		logger:    logger,
+		useCase:   useCase,
+		retention: retention,
+		ctx:       ctx,
+		stop:      stop,
+		queue:     make(chan *ingestJob, queueSize),
+		jobs:      map[string]*ingestJob{},
+	}
+	for i := 0; i < workers; i++ {
+		m.wg.Add(1)
+		go m.worker()
+	}
+	return m
+}
+
+// Submit queues a copy of req. A missing uuid is generated here so the job
+// reports the collection it writes to, and only one active job per uuid is
+// accepted because jobs share data/<stage>/<uuid> and the Kafka group ids.
+func (m *ingestJobManager) Submit(req *dtos.ProcessAndIngestRequest) (dtos.IngestJob, error) {
+	if req == nil {
+		return dtos.IngestJob{}, errors.New("request is nil")
+	}
+	if strings.TrimSpace(req.URLDownload) == "" {
+		return dtos.IngestJob{}, errors.New("url_download is required")
+	}
+	jobReq := *req
+	jobReq.UUID = strings.TrimSpace(jobReq.UUID)
+	if jobReq.UUID == "" {
+		jobReq.UUID = uuid.NewString()
+	}
+
+	job := &ingestJob{
+		req: jobReq,
+		state: dtos.IngestJob{
+			JobID:     uuid.NewString(),
+			Status:    dtos.IngestJobQueued,
+			UUID:      jobReq.UUID,
+			CreatedAt: time.Now().UTC(),
+			Steps:     make([]dtos.IngestJobStep, 0, len(ingestSteps)),
+		},
+	}
+	for _, step := range ingestSteps {
+		job.state.Steps = append(job.state.Steps, dtos.IngestJobStep{Name: step, Status: dtos.IngestStepPending})
+	}
+
+	m.mu.Lock()
+	if m.isClosed {
+		m.mu.Unlock()
+		return dtos.IngestJob{}, ports.ErrIngestJobsClosed
+	}
+	m.pruneLocked()
+	for _, existing := range m.jobs {
+		if existing.snapshot().UUID == jobReq.UUID && !existing.finished() {
+			m.mu.Unlock()
+			return dtos.IngestJob{}, fmt.Errorf("%w: %s", ports.ErrIngestJobConflict, jobReq.UUID)
+		}
+	}
+	select {
+	case m.queue <- job:
+		m.jobs[job.state.JobID] = job
+	default:
+		m.mu.Unlock()
+		return dtos.IngestJob{}, ports.ErrIngestQueueFull
+	}
+	m.mu.Unlock()
+
+	m.logger.Info(
+		"internal.application.use_cases.orchestrator.training_file.IngestJobManager job queued",
+		"job_id", job.state.JobID,
+		"uuid", jobReq.UUID,
+	)
+	return job.snapshot(), nil
+}
+
+func (m *ingestJobManager) Get(jobID string) (dtos.IngestJob, error) {
+	job, err := m.lookup(jobID)
+	if err != nil {
+		return dtos.IngestJob{}, err
+	}
+	return job.snapshot(), nil
+}
+
+// Cancel stops a job: a queued job is marked canceled and skipped by the
+// workers, a running job has its pipeline context canceled and is marked
+// canceled once the current step returns.
+func (m *ingestJobManager) Cancel(jobID string) (dtos.IngestJob, error) {
+	job, err := m.lookup(jobID)
+	if err != nil {
+		return dtos.IngestJob{}, err
+	}
+
+	job.mu.Lock()
+	switch job.state.Status {
+	case dtos.IngestJobQueued:
+		job.cancelQueuedLocked()
+	case dtos.IngestJobRunning:
+		if job.cancel != nil {
+			job.cancel()
+		}
+	default:
+		job.mu.Unlock()
+		return job.snapshot(), fmt.Errorf("%w: %s", ports.ErrIngestJobFinished, job.state.Status)
+	}
+	job.mu.Unlock()
+
+	m.logger.Info(
+		"internal.application.use_cases.orchestrator.training_file.IngestJobManager cancel requested",
+		"job_id", jobID,
+	)
+	return job.snapshot(), nil
+}
+
+// Close cancels running jobs and waits for the workers to exit. Jobs still
+// in the queue are marked canceled so they do not report queued forever, and
+// later Submit calls fail with ports.ErrIngestJobsClosed.
+func (m *ingestJobManager) Close() {
+	m.closed.Do(func() {
+		m.mu.Lock()
+		m.isClosed = true
+		m.mu.Unlock()
+		m.stop()
+		m.wg.Wait()
+		for {
+			select {
+			case job := <-m.queue:
+				job.cancelQueued()
+			default:
+				return
+			}
+		}
+	})
+}
+
+func (m *ingestJobManager) lookup(jobID string) (*ingestJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pruneLocked()
+	job, ok := m.jobs[strings.TrimSpace(jobID)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ports.ErrIngestJobNotFound, jobID)
+	}
+	return job, nil
+}
+
+func (m *ingestJobManager) pruneLocked() {
+	cutoff := time.Now().Add(-m.retention)
+	for id, job := range m.jobs {
+		state := job.snapshot()
+		if state.FinishedAt != nil && state.FinishedAt.Before(cutoff) {
+			delete(m.jobs, id)
+		}
+	}
+}
+
+func (m *ingestJobManager) worker() {
+	defer m.wg.Done()
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case job := <-m.queue:
+			// select picks at random when both are ready; a job taken
+			// after Close must not start.
+			if m.ctx.Err() != nil {
+				job.cancelQueued()
+				return
+			}
+			m.run(job)
+		}
+	}
+}
+
+func (m *ingestJobManager) run(job *ingestJob) {
+	ctx, cancel := context.WithCancel(m.ctx)
+	defer cancel()
+
+	job.mu.Lock()
+	if job.state.Status != dtos.IngestJobQueued {
+		job.mu.Unlock()
+		return
+	}
+	now := time.Now().UTC()
+	job.state.Status = dtos.IngestJobRunning
+	job.state.StartedAt = &now
+	job.cancel = cancel
+	req := job.req
+	job.mu.Unlock()
+
+	m.logger.Info(
+		"internal.application.use_cases.orchestrator.training_file.IngestJobManager job started",
+		"job_id", job.state.JobID,
+		"uuid", req.UUID,
+	)
+
+	result, err := m.ingest(ctx, &req, job)
+	job.finish(result, err, ctx.Err() != nil)
+	state := job.snapshot()
+	if err != nil {
+		m.logger.Error(
+			"internal.application.use_cases.orchestrator.training_file.IngestJobManager job stopped",
+			err,
+			"job_id", state.JobID,
+			"uuid", state.UUID,
+			"status", state.Status,
+		)
+		return
+	}
+	m.logger.Info(
+		"internal.application.use_cases.orchestrator.training_file.IngestJobManager job succeeded",
+		"job_id", state.JobID,
+		"uuid", state.UUID,
+		"inserted_points", result.InsertedPoints,
+		"latency_ms", result.LatencyMs,
+	)
+}
+
+// ingest runs the pipeline for one job. A panic in any step fails the job
+// instead of the worker, which would take the orchestrator down with it.
+func (m *ingestJobManager) ingest(ctx context.Context, req *dtos.ProcessAndIngestRequest, job *ingestJob) (result dtos.ProcessAndIngestResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = dtos.ProcessAndIngestResult{}, fmt.Errorf("ingest job panicked: %v", r)
+		}
+	}()
+	if ingester, ok := m.useCase.(progressIngester); ok {
+		return ingester.processAndIngest(ctx, req, job)
+	}
+	return m.useCase.ProcessAndIngest(ctx, req)
+}
+
+func (j *ingestJob) StepStarted(step string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if s := j.stepLocked(step); s != nil {
+		now := time.Now().UTC()
+		s.Status = dtos.IngestStepRunning
+		s.StartedAt = &now
+	}
+}
+
+func (j *ingestJob) StepCompleted(step string, counts map[string]int) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if s := j.stepLocked(step); s != nil {
+		now := time.Now().UTC()
+		s.Status = dtos.IngestStepCompleted
+		s.FinishedAt = &now
+		if s.StartedAt != nil {
+			s.LatencyMs = now.Sub(*s.StartedAt).Milliseconds()
+		}
+		s.Counts = counts
+	}
+}
+
+func (j *ingestJob) StepSkipped(step string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if s := j.stepLocked(step); s != nil {
+		s.Status = dtos.IngestStepSkipped
+	}
+}
+
+func (j *ingestJob) stepLocked(step string) *dtos.IngestJobStep {
+	for i := range j.state.Steps {
+		if j.state.Steps[i].Name == step {
+			return &j.state.Steps[i]
+		}
+	}
+	return nil
+}
+
+// finish records the outcome and closes the step that was running when the
+// pipeline returned. canceled is set when the job context was canceled by
+// Cancel or Close; the pipeline's own timeout still counts as a failure.
+func (j *ingestJob) finish(result dtos.ProcessAndIngestResult, err error, canceled bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	now := time.Now().UTC()
+	j.state.FinishedAt = &now
+	j.state.Result = &result
+	j.cancel = nil
+
+	if err == nil {
+		j.state.Status = dtos.IngestJobSucceeded
+		return
+	}
+
+	stepStatus := dtos.IngestStepFailed
+	j.state.Status = dtos.IngestJobFailed
+	if canceled {
+		stepStatus = dtos.IngestStepCanceled
+		j.state.Status = dtos.IngestJobCanceled
+	}
+	j.state.Error = err.Error()
+	for i := range j.state.Steps {
+		step := &j.state.Steps[i]
+		if step.Status != dtos.IngestStepRunning {
+			continue
+		}
+		step.Status = stepStatus
+		step.FinishedAt = &now
+		if step.StartedAt != nil {
+			step.LatencyMs = now.Sub(*step.StartedAt).Milliseconds()
+		}
+		step.Error = err.Error()
+	}
+}
+
+// cancelQueued marks a job that never started as canceled.
+func (j *ingestJob) cancelQueued() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.state.Status == dtos.IngestJobQueued {
+		j.cancelQueuedLocked()
+	}
+}
+
+func (j *ingestJob) cancelQueuedLocked() {
+	now := time.Now().UTC()
+	j.state.Status = dtos.IngestJobCanceled
+	j.state.FinishedAt = &now
+}
+
+func (j *ingestJob) finished() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.state.FinishedAt != nil
+}
+
+func (j *ingestJob) snapshot() dtos.IngestJob {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	out := j.state
+	out.Steps = make([]dtos.IngestJobStep, len(j.state.Steps))
+	copy(out.Steps, j.state.Steps)
+	if j.state.Result != nil {
+		result := *j.state.Result
+		out.Result = &result
+	}
+	return out
+}
@@ -0,0 +1,59 @@
+package trainingfile
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"rag_imagetotext_texttoimage/internal/application/dtos"
+	"rag_imagetotext_texttoimage/internal/application/ports"
+	"rag_imagetotext_texttoimage/internal/util"
+)
+
+// blockingIngest runs until its context is canceled and reports every start.
+type blockingIngest struct {
+	ports.TrainingFileUseCase
+	started chan struct{}
+}
+
+func (b *blockingIngest) ProcessAndIngest(ctx context.Context, _ *dtos.ProcessAndIngestRequest) (dtos.ProcessAndIngestResult, error) {
+	b.started <- struct{}{}
+	<-ctx.Done()
+	return dtos.ProcessAndIngestResult{}, ctx.Err()
+}
+
+func TestIngestJobManagerClose(t *testing.T) {
+	useCase := &blockingIngest{started: make(chan struct{}, 2)}
+	manager := NewIngestJobManager(nopLogger{}, useCase, util.IngestJobSettings{Workers: 1, QueueSize: 4})
+
+	running, err := manager.Submit(&dtos.ProcessAndIngestRequest{URLDownload: "http://example.com/a.pdf", UUID: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-useCase.started
+	queued, err := manager.Submit(&dtos.ProcessAndIngestRequest{URLDownload: "http://example.com/b.pdf", UUID: "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manager.Close()
+
+	for _, id := range []string{running.JobID, queued.JobID} {
+		job, err := manager.Get(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.Status != dtos.IngestJobCanceled || job.FinishedAt == nil {
+			t.Fatalf("job %s: status = %s, finished_at = %v", id, job.Status, job.FinishedAt)
+		}
+	}
+
+	if _, err := manager.Submit(&dtos.ProcessAndIngestRequest{URLDownload: "http://example.com/c.pdf"}); !errors.Is(err, ports.ErrIngestJobsClosed) {
+		t.Fatalf("Submit after Close: err = %v", err)
+	}
+	select {
+	case <-useCase.started:
+		t.Fatal("a job started after Close")
+	default:
+	}
+}
@@ -13,9 +13,14 @@ import (
 	"github.com/google/uuid"
 
 	"rag_imagetotext_texttoimage/internal/application/dtos"
+	domain "rag_imagetotext_texttoimage/internal/domain/entity_objects"
 )
 
 func (uc *trainingFileUseCase) ProcessAndIngest(ctx context.Context, req *dtos.ProcessAndIngestRequest) (dtos.ProcessAndIngestResult, error) {
+	return uc.processAndIngest(ctx, req, noopIngestProgress{})
+}
+
+func (uc *trainingFileUseCase) processAndIngest(ctx context.Context, req *dtos.ProcessAndIngestRequest, progress ingestProgress) (dtos.ProcessAndIngestResult, error) {
 	startedAt := time.Now()
 	result := dtos.ProcessAndIngestResult{
 		Success:        false,
@@ -73,6 +78,11 @@ func (uc *trainingFileUseCase) ProcessAndIngest(ctx context.Context, req *dtos.P
 	uploadDir := filepath.Join("data", "upload", trainingUUID)
 	result.ProcessDir = processDir
 
+	manifest, previous, err := uc.prepareIngestManifest(req, trainingUUID, urlDownload, processDir, downloadDir, uploadDir)
+	if err != nil {
+		return result, err
+	}
+
 	if err := os.MkdirAll(downloadDir, 0755); err != nil {
 		return result, fmt.Errorf("create download dir: %w", err)
 	}
@@ -83,203 +93,357 @@ func (uc *trainingFileUseCase) ProcessAndIngest(ctx context.Context, req *dtos.P
 		return result, fmt.Errorf("create upload dir: %w", err)
 	}
 
-	stepStartedAt := time.Now()
-	downloadRes, err := uc.Download(pipelineCtx, &dtos.DownFileTrainingRequest{
-		UrlDownFile: urlDownload,
-		Uuid:        trainingUUID,
-		PathSave:    downloadDir,
-	})
-	if err != nil {
-		return result, fmt.Errorf("step download failed: %w", err)
+	checkpoint := func(step string) {
+		manifest.markCompleted(step)
+		if err := saveIngestManifest(manifest); err != nil {
+			uc.logger.Error("internal.application.use_cases.orchestrator.training_file.ProcessAndIngest save manifest failed", err, "uuid", trainingUUID, "step", step)
+		}
 	}
-	result.DownloadPath = downloadRes.FilePath
-	uc.logger.Info("internal.application.use_cases.orchestrator.training_file.ProcessAndIngest step completed", "step", "download", "uuid", trainingUUID, "latency_ms", time.Since(stepStartedAt).Milliseconds())
-
-	stepStartedAt = time.Now()
-	_, err = uc.AnalysisFile(pipelineCtx, &dtos.AnalysisFileRequest{
-		FilePath: downloadRes.FilePath,
-		DistDir:  processDir,
-		Uuid:     trainingUUID,
-		Dev:      markerDevMode,
-	})
-	if err != nil {
-		return result, fmt.Errorf("step marker analysis failed: %w", err)
+	resumed := func(step string) bool {
+		if !manifest.completed(step) {
+			return false
+		}
+		result.ResumedSteps = append(result.ResumedSteps, step)
+		progress.StepSkipped(step)
+		uc.logger.Info("internal.application.use_cases.orchestrator.training_file.ProcessAndIngest step resumed from manifest", "step", step, "uuid", trainingUUID)
+		return true
 	}
-	uc.logger.Info("internal.application.use_cases.orchestrator.training_file.ProcessAndIngest step completed", "step", "analysis", "uuid", trainingUUID, "latency_ms", time.Since(stepStartedAt).Milliseconds())
 
-	markdownPath, _, artifactPaths, err := discoverProcessedArtifacts(processDir)
-	if err != nil {
-		return result, fmt.Errorf("discover processed artifacts: %w", err)
+	var stepStartedAt time.Time
+	if !resumed(ingestStepDownload) {
+		progress.StepStarted(ingestStepDownload)
+		stepStartedAt = time.Now()
+		downloadRes, err := uc.Download(pipelineCtx, &dtos.DownFileTrainingRequest{
+			UrlDownFile: urlDownload,
+			Uuid:        trainingUUID,
+			PathSave:    downloadDir,
+		})
+		if err != nil {
+			return result, fmt.Errorf("step download failed: %w", err)
+		}
+		docHash, err := fileContentHash(downloadRes.FilePath)
+		if err != nil {
+			return result, fmt.Errorf("hash downloaded file: %w", err)
+		}
+		manifest.DownloadPath = downloadRes.FilePath
+		manifest.DocHash = docHash
+		uc.logger.Info("internal.application.use_cases.orchestrator.training_file.ProcessAndIngest step completed", "step", ingestStepDownload, "uuid", trainingUUID, "doc_hash", docHash, "latency_ms", time.Since(stepStartedAt).Milliseconds())
+		progress.StepCompleted(ingestStepDownload, nil)
+		checkpoint(ingestStepDownload)
 	}
-	result.MarkdownPath = markdownPath
+	result.DownloadPath = manifest.DownloadPath
 
-	stepStartedAt = time.Now()
-	uploaded, err := uc.uploadArtifactsToMinio(pipelineCtx, uploadDir, append(artifactPaths, downloadRes.FilePath))
-	if err != nil {
-		return result, fmt.Errorf("step minio upload failed: %w", err)
+	if uc.documentUnchanged(pipelineCtx, collectionName, previous, manifest.DocHash) {
+		previous.DownloadPath = manifest.DownloadPath
+		if err := saveIngestManifest(previous); err != nil {
+			uc.logger.Error("internal.application.use_cases.orchestrator.training_file.ProcessAndIngest save manifest failed", err, "uuid", trainingUUID)
+		}
+		for _, step := range ingestSteps[1:] {
+			progress.StepSkipped(step)
+		}
+		uc.logger.Info("internal.application.use_cases.orchestrator.training_file.ProcessAndIngest document unchanged, skipping ingest", "uuid", trainingUUID, "doc_hash", manifest.DocHash)
+		result.MarkdownPath = previous.MarkdownPath
+		result.UploadedFiles = previous.UploadedFiles
+		result.Unchanged = true
+		result.Verified = true
+		result.Success = true
+		result.LatencyMs = time.Since(startedAt).Milliseconds()
+		return result, nil
 	}
-	result.UploadedFiles = uploaded
-	uc.logger.Info("internal.application.use_cases.orchestrator.training_file.ProcessAndIngest step completed", "step", "upload_minio", "uuid", trainingUUID, "uploaded_files", uploaded, "latency_ms", time.Since(stepStartedAt).Milliseconds())
 
-	stepStartedAt = time.Now()
-	mdContent, err := uc.ReadMarkdownFile(pipelineCtx, markdownPath)
-	if err != nil {
-		return result, fmt.Errorf("step read markdown failed: %w", err)
-	}
-	chunkingCfg := uc.resolveChunkingConfig()
-	chunks, parseStats := parseLineBasedChunks(mdContent, chunkingCfg)
-	if len(chunks) == 0 {
-		return result, errors.New("no chunk extracted from markdown")
+	if !resumed(ingestStepAnalysis) {
+		progress.StepStarted(ingestStepAnalysis)
+		stepStartedAt = time.Now()
+		_, err = uc.AnalysisFile(pipelineCtx, &dtos.AnalysisFileRequest{
+			FilePath: manifest.DownloadPath,
+			DistDir:  processDir,
+			Uuid:     trainingUUID,
+			Dev:      markerDevMode,
+		})
+		if err != nil {
+			return result, fmt.Errorf("step marker analysis failed: %w", err)
+		}
+		uc.logger.Info("internal.application.use_cases.orchestrator.training_file.ProcessAndIngest step completed", "step", ingestStepAnalysis, "uuid", trainingUUID, "latency_ms", time.Since(stepStartedAt).Milliseconds())
+		progress.StepCompleted(ingestStepAnalysis, nil)
+		checkpoint(ingestStepAnalysis)
 	}
-	shortChunkCount := countShortChunks(chunks, chunkingCfg)
-	shortChunkPct := percentInt(shortChunkCount, len(chunks))
-	headerDropPct := percentInt(parseStats.DroppedHeader, parseStats.RawSentenceCount)
-	numericDropPct := percentInt(parseStats.DroppedNumeric, parseStats.RawSentenceCount)
-	noiseDropPct := percentInt(parseStats.DroppedNoise, parseStats.RawSentenceCount)
-	uc.logger.Info(
-		"internal.application.use_cases.orchestrator.training_file.ProcessAndIngest step completed",
-		"step", "parse_markdown",
-		"uuid", trainingUUID,
-		"raw_chunk_count", parseStats.RawSentenceCount,
-		"chunk_count", len(chunks),
-		"dropped_header", parseStats.DroppedHeader,
-		"dropped_header_pct", fmt.Sprintf("%.2f", headerDropPct),
-		"dropped_numeric", parseStats.DroppedNumeric,
-		"dropped_numeric_pct", fmt.Sprintf("%.2f", numericDropPct),
-		"dropped_noise", parseStats.DroppedNoise,
-		"dropped_noise_pct", fmt.Sprintf("%.2f", noiseDropPct),
-		"merged_short_sentences", parseStats.MergedShort,
-		"short_chunk_pct", fmt.Sprintf("%.2f", shortChunkPct),
-		"latency_ms", time.Since(stepStartedAt).Milliseconds(),
-	)
 
-	stepStartedAt = time.Now()
-	texts := make([]string, 0, len(chunks))
-	for _, chunk := range chunks {
-		texts = append(texts, chunk.Text)
-	}
-	textVectors, err := uc.embedTextAsyncByKafka(pipelineCtx, trainingUUID, texts, effectiveBatchSize)
-	if err != nil {
-		return result, fmt.Errorf("step embed text failed: %w", err)
-	}
-	filteredChunks, filteredTextVectors, skippedZeroNorm, skippedDimMismatch, err := filterValidChunksAndEmbeddings(chunks, textVectors)
-	if err != nil {
-		return result, fmt.Errorf("validate embeddings failed: %w", err)
+	if !resumed(ingestStepUploadMinio) {
+		progress.StepStarted(ingestStepUploadMinio)
+		markdownPath, _, artifactPaths, err := discoverProcessedArtifacts(processDir)
+		if err != nil {
+			return result, fmt.Errorf("discover processed artifacts: %w", err)
+		}
+		manifest.MarkdownPath = markdownPath
+		manifest.ArtifactPaths = artifactPaths
+		result.MarkdownPath = markdownPath
+
+		stepStartedAt = time.Now()
+		uploaded, err := uc.uploadArtifactsToMinio(pipelineCtx, uploadDir, append(artifactPaths, manifest.DownloadPath))
+		if err != nil {
+			return result, fmt.Errorf("step minio upload failed: %w", err)
+		}
+		manifest.UploadedFiles = uploaded
+		uc.logger.Info("internal.application.use_cases.orchestrator.training_file.ProcessAndIngest step completed", "step", ingestStepUploadMinio, "uuid", trainingUUID, "uploaded_files", uploaded, "latency_ms", time.Since(stepStartedAt).Milliseconds())
+		progress.StepCompleted(ingestStepUploadMinio, map[string]int{"artifact_files": len(artifactPaths), "uploaded_files": uploaded})
+		checkpoint(ingestStepUploadMinio)
 	}
-	if skippedZeroNorm > 0 || skippedDimMismatch > 0 {
+	markdownPath := manifest.MarkdownPath
+	result.MarkdownPath = markdownPath
+	result.UploadedFiles = manifest.UploadedFiles
+
+	chunkingCfg := uc.resolveChunkingConfig()
+	if !resumed(ingestStepParseMarkdown) {
+		progress.StepStarted(ingestStepParseMarkdown)
+		stepStartedAt = time.Now()
+		mdContent, err := uc.ReadMarkdownFile(pipelineCtx, markdownPath)
+		if err != nil {
+			return result, fmt.Errorf("step read markdown failed: %w", err)
+		}
+		chunks, parseStats := parseLineBasedChunks(mdContent, chunkingCfg)
+		if len(chunks) == 0 {
+			return result, errors.New("no chunk extracted from markdown")
+		}
+		shortChunkCount := countShortChunks(chunks, chunkingCfg)
+		shortChunkPct := percentInt(shortChunkCount, len(chunks))
+		headerDropPct := percentInt(parseStats.DroppedHeader, parseStats.RawSentenceCount)
+		numericDropPct := percentInt(parseStats.DroppedNumeric, parseStats.RawSentenceCount)
+		noiseDropPct := percentInt(parseStats.DroppedNoise, parseStats.RawSentenceCount)
 		uc.logger.Info(
-			"internal.application.use_cases.orchestrator.training_file.ProcessAndIngest invalid embeddings were filtered",
+			"internal.application.use_cases.orchestrator.training_file.ProcessAndIngest step completed",
+			"step", ingestStepParseMarkdown,
 			"uuid", trainingUUID,
-			"skipped_zero_norm", skippedZeroNorm,
-			"skipped_dimension_mismatch", skippedDimMismatch,
-			"remaining_chunks", len(filteredChunks),
+			"raw_chunk_count", parseStats.RawSentenceCount,
+			"chunk_count", len(chunks),
+			"dropped_header", parseStats.DroppedHeader,
+			"dropped_header_pct", fmt.Sprintf("%.2f", headerDropPct),
+			"dropped_numeric", parseStats.DroppedNumeric,
+			"dropped_numeric_pct", fmt.Sprintf("%.2f", numericDropPct),
+			"dropped_noise", parseStats.DroppedNoise,
+			"dropped_noise_pct", fmt.Sprintf("%.2f", noiseDropPct),
+			"merged_short_sentences", parseStats.MergedShort,
+			"table_chunk_count", parseStats.TableCount,
+			"short_chunk_pct", fmt.Sprintf("%.2f", shortChunkPct),
+			"latency_ms", time.Since(stepStartedAt).Milliseconds(),
 		)
+		progress.StepCompleted(ingestStepParseMarkdown, map[string]int{
+			"raw_chunk_count": parseStats.RawSentenceCount,
+			"chunk_count":     len(chunks),
+			"dropped_header":  parseStats.DroppedHeader,
+			"dropped_numeric": parseStats.DroppedNumeric,
+			"dropped_noise":   parseStats.DroppedNoise,
+			"table_count":     parseStats.TableCount,
+		})
+		manifest.ParsedChunks = chunks
+		checkpoint(ingestStepParseMarkdown)
 	}
+	chunks := manifest.ParsedChunks
 
-	if err := uc.DoSemanticChunking(pipelineCtx, filteredTextVectors, chunkingCfg.SemanticSimThreshold); err != nil {
-		return result, fmt.Errorf("step semantic chunking failed: %w", err)
-	}
-	mergedChunks, mergedVectors, err := mergeChunksBySemantic(filteredChunks, filteredTextVectors, chunkingCfg)
-	if err != nil {
-		return result, fmt.Errorf("merge semantic chunks failed: %w", err)
-	}
-	finalShortChunkCount := countShortChunks(mergedChunks, chunkingCfg)
-	finalShortChunkPct := percentInt(finalShortChunkCount, len(mergedChunks))
-	p50Tokens, p95Tokens, maxTokens := chunkTokenDistribution(mergedChunks)
-	headSamples, tailSamples := sampleChunkTexts(mergedChunks, 3, 200)
-	uc.logger.Info(
-		"internal.application.use_cases.orchestrator.training_file.ProcessAndIngest step completed",
-		"step", "embedding_semantic",
-		"uuid", trainingUUID,
-		"original_chunks", len(chunks),
-		"final_chunk_count", len(mergedChunks),
-		"short_chunk_pct", fmt.Sprintf("%.2f", finalShortChunkPct),
-		"token_p50", p50Tokens,
-		"token_p95", p95Tokens,
-		"token_max", maxTokens,
-		"head_samples", strings.Join(headSamples, " || "),
-		"tail_samples", strings.Join(tailSamples, " || "),
-		"latency_ms", time.Since(stepStartedAt).Milliseconds(),
-	)
-
-	stepStartedAt = time.Now()
-	imageVectorByChunk := map[int][]float32{}
-	for i, chunk := range mergedChunks {
-		if len(chunk.ImagePaths) == 0 {
-			continue
+	if !resumed(ingestStepEmbedding) {
+		progress.StepStarted(ingestStepEmbedding)
+		stepStartedAt = time.Now()
+		texts := make([]string, 0, len(chunks))
+		for _, chunk := range chunks {
+			texts = append(texts, chunk.Text)
+		}
+		textVectors, err := uc.embedTextAsyncByKafka(pipelineCtx, trainingUUID, texts, effectiveBatchSize)
+		if err != nil {
+			return result, fmt.Errorf("step embed text failed: %w", err)
 		}
-		absPath := resolveImagePath(markdownPath, chunk.ImagePaths[0])
-		if absPath == "" {
-			continue
+		filteredChunks, filteredTextVectors, skippedZeroNorm, skippedDimMismatch, err := filterValidChunksAndEmbeddings(chunks, textVectors)
+		if err != nil {
+			return result, fmt.Errorf("validate embeddings failed: %w", err)
 		}
-		vec, imgErr := uc.embedSingleImageAsyncByKafka(pipelineCtx, trainingUUID, absPath)
-		if imgErr != nil {
-			uc.logger.Error("internal.application.use_cases.orchestrator.training_file.ProcessAndIngest image embedding failed", imgErr, "chunk_index", i, "image_path", absPath)
-			continue
+		if skippedZeroNorm > 0 || skippedDimMismatch > 0 {
+			uc.logger.Info(
+				"internal.application.use_cases.orchestrator.training_file.ProcessAndIngest invalid embeddings were filtered",
+				"uuid", trainingUUID,
+				"skipped_zero_norm", skippedZeroNorm,
+				"skipped_dimension_mismatch", skippedDimMismatch,
+				"remaining_chunks", len(filteredChunks),
+			)
+		}
+
+		if err := uc.DoSemanticChunking(pipelineCtx, filteredTextVectors, chunkingCfg.SemanticSimThreshold); err != nil {
+			return result, fmt.Errorf("step semantic chunking failed: %w", err)
 		}
-		imageVectorByChunk[i] = vec
+		mergedChunks, mergedVectors, err := mergeChunksBySemantic(filteredChunks, filteredTextVectors, chunkingCfg)
+		if err != nil {
+			return result, fmt.Errorf("merge semantic chunks failed: %w", err)
+		}
+		finalShortChunkCount := countShortChunks(mergedChunks, chunkingCfg)
+		finalShortChunkPct := percentInt(finalShortChunkCount, len(mergedChunks))
+		p50Tokens, p95Tokens, maxTokens := chunkTokenDistribution(mergedChunks)
+		headSamples, tailSamples := sampleChunkTexts(mergedChunks, 3, 200)
+		uc.logger.Info(
+			"internal.application.use_cases.orchestrator.training_file.ProcessAndIngest step completed",
+			"step", ingestStepEmbedding,
+			"uuid", trainingUUID,
+			"original_chunks", len(chunks),
+			"final_chunk_count", len(mergedChunks),
+			"short_chunk_pct", fmt.Sprintf("%.2f", finalShortChunkPct),
+			"token_p50", p50Tokens,
+			"token_p95", p95Tokens,
+			"token_max", maxTokens,
+			"head_samples", strings.Join(headSamples, " || "),
+			"tail_samples", strings.Join(tailSamples, " || "),
+			"latency_ms", time.Since(stepStartedAt).Milliseconds(),
+		)
+		progress.StepCompleted(ingestStepEmbedding, map[string]int{
+			"embedded_chunks":            len(textVectors),
+			"skipped_zero_norm":          skippedZeroNorm,
+			"skipped_dimension_mismatch": skippedDimMismatch,
+			"final_chunk_count":          len(mergedChunks),
+		})
+		manifest.Chunks = mergedChunks
+		manifest.TextVectors = mergedVectors
+		checkpoint(ingestStepEmbedding)
 	}
-	uc.logger.Info("internal.application.use_cases.orchestrator.training_file.ProcessAndIngest step completed", "step", "embedding_image_optional", "uuid", trainingUUID, "image_vector_count", len(imageVectorByChunk), "latency_ms", time.Since(stepStartedAt).Milliseconds())
+	mergedChunks := manifest.Chunks
+	mergedVectors := manifest.TextVectors
 
-	stepStartedAt = time.Now()
-	points := make([]dtos.UploadVectorDBPoint, 0, len(mergedChunks))
-	for i := range mergedChunks {
-		payload := map[string]string{
-			"doc_id":      trainingUUID,
-			"unit_type":   "semantic_chunk",
-			"text":        mergedChunks[i].Text,
-			"chunk_index": strconv.Itoa(i),
-			"lang":        strings.TrimSpace(req.Lang),
-			"source_path": markdownPath,
-			"page":        "0",
-			"token_count": strconv.Itoa(len(strings.Fields(mergedChunks[i].Text))),
+	if !resumed(ingestStepEmbeddingImage) {
+		progress.StepStarted(ingestStepEmbeddingImage)
+		stepStartedAt = time.Now()
+		figures, imageFailures, err := uc.embedFigures(pipelineCtx, trainingUUID, markdownPath, collectFigures(mergedChunks))
+		if err != nil {
+			return result, fmt.Errorf("step embed images failed: %w", err)
 		}
-		if len(mergedChunks[i].ImagePaths) > 0 {
-			payload["image_path"] = strings.Join(mergedChunks[i].ImagePaths, ",")
+		uc.logger.Info("internal.application.use_cases.orchestrator.training_file.ProcessAndIngest step completed", "step", ingestStepEmbeddingImage, "uuid", trainingUUID, "image_vector_count", len(figures), "image_failed_count", len(imageFailures), "latency_ms", time.Since(stepStartedAt).Milliseconds())
+		progress.StepCompleted(ingestStepEmbeddingImage, map[string]int{"image_vector_count": len(figures), "image_failed_count": len(imageFailures)})
+		manifest.Figures = figures
+		manifest.FailedImages = imageFailures
+		checkpoint(ingestStepEmbeddingImage)
+	}
+	result.FailedImages = manifest.FailedImages
+
+	if !resumed(ingestStepFigureCaption) {
+		progress.StepStarted(ingestStepFigureCaption)
+		stepStartedAt = time.Now()
+		if uc.Config.FileTraining.FigureCaption.Enabled && len(manifest.Figures) > 0 {
+			captioned, captionCounts, err := uc.captionFigures(pipelineCtx, trainingUUID, markdownPath, req.Lang, manifest.Figures)
+			if err != nil {
+				return result, fmt.Errorf("step figure caption failed: %w", err)
+			}
+			if err := uc.embedFigureCaptions(pipelineCtx, trainingUUID, captioned, effectiveBatchSize); err != nil {
+				return result, fmt.Errorf("step embed figure captions failed: %w", err)
+			}
+			manifest.Figures = captioned
+			uc.logger.Info("internal.application.use_cases.orchestrator.training_file.ProcessAndIngest step completed", "step", ingestStepFigureCaption, "uuid", trainingUUID, "generated", captionCounts.Generated, "cached", captionCounts.Cached, "skipped_budget", captionCounts.Skipped, "failed", captionCounts.Failed, "latency_ms", time.Since(stepStartedAt).Milliseconds())
+			progress.StepCompleted(ingestStepFigureCaption, map[string]int{
+				"captions_generated": captionCounts.Generated,
+				"captions_cached":    captionCounts.Cached,
+				"skipped_budget":     captionCounts.Skipped,
+				"caption_failed":     captionCounts.Failed,
+			})
+		} else {
+			progress.StepCompleted(ingestStepFigureCaption, nil)
 		}
+		checkpoint(ingestStepFigureCaption)
+	}
+	figures := manifest.Figures
 
-		vectors := []dtos.UploadVectorDBVector{
-			{Name: "text_dense", Vector: mergedVectors[i]},
+	if !resumed(ingestStepUploadVectorDB) {
+		progress.StepStarted(ingestStepUploadVectorDB)
+		stepStartedAt = time.Now()
+		ingestRunID := uuid.NewString()
+		points := make([]dtos.UploadVectorDBPoint, 0, len(mergedChunks)+len(figures))
+		chunkPayloads := make([]map[string]string, len(mergedChunks))
+		chunkPointIDs := make([]string, len(mergedChunks))
+		for i := range mergedChunks {
+			payload := map[string]string{
+				"doc_id":        trainingUUID,
+				"doc_hash":      manifest.DocHash,
+				"ingest_run_id": ingestRunID,
+				"unit_type":     unitTypeSemanticChunk,
+				"modality":      modalityText,
+				"text":          mergedChunks[i].Text,
+				"chunk_index":   strconv.Itoa(i),
+				"lang":          strings.TrimSpace(req.Lang),
+				"source_path":   markdownPath,
+				"page":          strconv.Itoa(mergedChunks[i].Page),
+				"token_count":   strconv.Itoa(len(strings.Fields(mergedChunks[i].Text))),
+			}
+			if mergedChunks[i].isTable() {
+				payload["unit_type"] = unitTypeTable
+				payload["has_table"] = "true"
+				payload["table_csv"] = mergedChunks[i].TableCSV
+			}
+			if mergedChunks[i].SectionTitle != "" {
+				payload["section_title"] = mergedChunks[i].SectionTitle
+			}
+			if len(mergedChunks[i].HeadingPath) > 0 {
+				payload["heading_path"] = strings.Join(mergedChunks[i].HeadingPath, domain.HeadingPathSeparator)
+			}
+			if len(mergedChunks[i].ImagePaths) > 0 {
+				payload["image_path"] = strings.Join(mergedChunks[i].ImagePaths, ",")
+				payload["has_figure"] = "true"
+			}
+			payload["chunk_hash"], chunkPointIDs[i] = payloadPointID(payload)
+			chunkPayloads[i] = payload
+
+			points = append(points, dtos.UploadVectorDBPoint{
+				Vectors: []dtos.UploadVectorDBVector{
+					{Name: "text_dense", Vector: mergedVectors[i]},
+				},
+				Payload: payload,
+			})
 		}
-		if imageVector, ok := imageVectorByChunk[i]; ok && len(imageVector) > 0 {
-			vectors = append(vectors, dtos.UploadVectorDBVector{
-				Name:   "image_dense",
-				Vector: imageVector,
+		for _, figure := range figures {
+			if figure.ChunkIndex < 0 || figure.ChunkIndex >= len(mergedChunks) || len(figure.Vector) == 0 {
+				continue
+			}
+			vectors := []dtos.UploadVectorDBVector{
+				{Name: "image_dense", Vector: figure.Vector},
+			}
+			if len(figure.CaptionVector) > 0 {
+				vectors = append(vectors, dtos.UploadVectorDBVector{Name: "text_dense", Vector: figure.CaptionVector})
+			}
+			points = append(points, dtos.UploadVectorDBPoint{
+				Vectors: vectors,
+				Payload: figurePayload(chunkPayloads[figure.ChunkIndex], chunkPointIDs[figure.ChunkIndex], figure),
 			})
 		}
 
-		points = append(points, dtos.UploadVectorDBPoint{
-			Vectors: vectors,
-			Payload: payload,
+		uploadRes, err := uc.UploadVectorDB(pipelineCtx, &dtos.UploadVectorDBRequest{
+			CollectionName: collectionName,
+			Points:         points,
+			BatchSize:      effectiveBatchSize,
 		})
+		if err != nil {
+			return result, fmt.Errorf("step upload vectordb failed: %w", err)
+		}
+		if err := uc.deleteStaleDocPoints(pipelineCtx, collectionName, trainingUUID, ingestRunID); err != nil {
+			return result, fmt.Errorf("step delete stale points failed: %w", err)
+		}
+		manifest.InsertedPoints = uploadRes.InsertedPoints
+		manifest.SkippedPoints = uploadRes.SkippedPoints
+		uc.logger.Info("internal.application.use_cases.orchestrator.training_file.ProcessAndIngest step completed", "step", ingestStepUploadVectorDB, "uuid", trainingUUID, "inserted_points", uploadRes.InsertedPoints, "latency_ms", time.Since(stepStartedAt).Milliseconds())
+		progress.StepCompleted(ingestStepUploadVectorDB, map[string]int{"inserted_points": uploadRes.InsertedPoints, "skipped_points": uploadRes.SkippedPoints})
+		checkpoint(ingestStepUploadVectorDB)
 	}
+	result.InsertedPoints = manifest.InsertedPoints
+	result.SkippedPoints = manifest.SkippedPoints
 
-	uploadRes, err := uc.UploadVectorDB(pipelineCtx, &dtos.UploadVectorDBRequest{
-		CollectionName: collectionName,
-		Points:         points,
-		BatchSize:      effectiveBatchSize,
-	})
-	if err != nil {
-		return result, fmt.Errorf("step upload vectordb failed: %w", err)
-	}
-	result.InsertedPoints = uploadRes.InsertedPoints
-	result.SkippedPoints = uploadRes.SkippedPoints
-	uc.logger.Info("internal.application.use_cases.orchestrator.training_file.ProcessAndIngest step completed", "step", "upload_vectordb", "uuid", trainingUUID, "inserted_points", uploadRes.InsertedPoints, "latency_ms", time.Since(stepStartedAt).Milliseconds())
-
-	stepStartedAt = time.Now()
-	probeText := ""
-	if len(mergedChunks) > 0 {
-		probeText = strings.TrimSpace(mergedChunks[0].Text)
-	}
-	verified, err := uc.verifyVectorDBByDocID(pipelineCtx, collectionName, trainingUUID, probeText)
-	if err != nil {
-		return result, fmt.Errorf("step verify vectordb failed: %w", err)
-	}
-	result.Verified = verified
-	if !verified {
-		return result, errors.New("vectordb verification failed: no data found for doc_id")
+	if !resumed(ingestStepVerifyVectorDB) {
+		progress.StepStarted(ingestStepVerifyVectorDB)
+		stepStartedAt = time.Now()
+		probeText := ""
+		if len(mergedChunks) > 0 {
+			probeText = strings.TrimSpace(mergedChunks[0].Text)
+		}
+		verified, err := uc.verifyVectorDBByDocID(pipelineCtx, collectionName, trainingUUID, manifest.DocHash, probeText)
+		if err != nil {
+			return result, fmt.Errorf("step verify vectordb failed: %w", err)
+		}
+		if !verified {
+			return result, errors.New("vectordb verification failed: no data found for doc_id")
+		}
+		uc.logger.Info("internal.application.use_cases.orchestrator.training_file.ProcessAndIngest step completed", "step", ingestStepVerifyVectorDB, "uuid", trainingUUID, "verified", verified, "latency_ms", time.Since(stepStartedAt).Milliseconds())
+		progress.StepCompleted(ingestStepVerifyVectorDB, nil)
+		checkpoint(ingestStepVerifyVectorDB)
 	}
-	uc.logger.Info("internal.application.use_cases.orchestrator.training_file.ProcessAndIngest step completed", "step", "verify_vectordb", "uuid", trainingUUID, "verified", verified, "latency_ms", time.Since(stepStartedAt).Milliseconds())
+	result.Verified = true
 
 	result.Success = true
 	result.LatencyMs = time.Since(startedAt).Milliseconds()
@@ -0,0 +1,223 @@
+package trainingfile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	domain "rag_imagetotext_texttoimage/internal/domain/entity_objects"
+	pb "rag_imagetotext_texttoimage/proto"
+)
+
+const (
+	defaultCaptionBudget   = 30
+	defaultCaptionTimeout  = 60 * time.Second
+	defaultCaptionCacheDir = "data/caption_cache"
+	captionMaxChars        = 1200
+)
+
+const defaultCaptionPrompt = "Describe this figure for a search index in 2-4 sentences. " +
+	"Say what kind of figure it is (chart, diagram, photo, screenshot, table image), " +
+	"its title, and the entities, axes, labels and key numbers it shows. " +
+	"Do not add information that is not visible in the figure. Answer in plain text without markdown."
+
+type captionStats struct {
+	Generated int
+	Cached    int
+	Skipped   int
+	Failed    int
+}
+
+// captionFigures fills Caption for the figures: cached captions first, then
+// LLM calls until the per-document budget is used up. A failed call leaves
+// the figure without caption and does not stop the ingest.
+func (uc *trainingFileUseCase) captionFigures(ctx context.Context, trainingUUID string, markdownPath string, lang string, figures []figureUnit) ([]figureUnit, captionStats, error) {
+	settings := uc.Config.FileTraining.FigureCaption
+	stats := captionStats{}
+	model := strings.TrimSpace(settings.Model)
+	if model == "" {
+		model = strings.TrimSpace(uc.Config.LLMService.Model)
+	}
+	prompt := strings.TrimSpace(settings.Prompt)
+	if prompt == "" {
+		prompt = defaultCaptionPrompt
+	}
+	if lang = strings.TrimSpace(lang); lang != "" {
+		prompt += " Write the description in the language with code " + lang + "."
+	}
+	timeout := defaultCaptionTimeout
+	if settings.TimeoutSeconds > 0 {
+		timeout = time.Duration(settings.TimeoutSeconds) * time.Second
+	}
+	cacheDir := strings.TrimSpace(settings.CacheDir)
+	if cacheDir == "" {
+		cacheDir = defaultCaptionCacheDir
+	}
+	budget := settings.MaxPerDocument
+	if budget <= 0 {
+		budget = defaultCaptionBudget
+	}
+	calls := 0
+
+	var llmClient pb.LlmServiceClient
+	var conn *grpc.ClientConn
+	defer func() {
+		if conn != nil {
+			conn.Close()
+		}
+	}()
+
+	out := make([]figureUnit, len(figures))
+	copy(out, figures)
+	for i := range out {
+		if err := ctx.Err(); err != nil {
+			return nil, stats, err
+		}
+		absPath := resolveImagePath(markdownPath, out[i].ImagePath)
+		if absPath == "" {
+			stats.Failed++
+			continue
+		}
+		imageBytes, err := os.ReadFile(absPath)
+		if err != nil {
+			stats.Failed++
+			continue
+		}
+		cacheKey := domain.ContentHash(model, prompt, string(imageBytes))
+		if caption, ok := readCaptionCache(cacheDir, cacheKey); ok {
+			out[i].Caption = caption
+			stats.Cached++
+			continue
+		}
+		if calls >= budget {
+			stats.Skipped++
+			continue
+		}
+
+		if llmClient == nil {
+			conn, llmClient, err = uc.newLLMServiceClient(ctx)
+			if err != nil {
+				return nil, stats, err
+			}
+		}
+		calls++
+		callCtx, cancel := context.WithTimeout(ctx, timeout)
+		resp, err := llmClient.GenerateTextToImage(callCtx, &pb.TextToImageRequest{
+			Model:       model,
+			Temperature: uc.Config.LLMService.Temp,
+			ImagePath:   absPath,
+			Prompt:      prompt,
+		})
+		cancel()
+		caption := normalizeCaption(resp.GetText())
+		if err == nil && caption == "" {
+			err = errors.New("empty caption")
+		}
+		if err != nil {
+			stats.Failed++
+			uc.logger.Error(
+				"internal.application.use_cases.orchestrator.training_file.ProcessAndIngest figure caption failed",
+				err,
+				"uuid", trainingUUID,
+				"image_path", out[i].ImagePath,
+			)
+			continue
+		}
+		out[i].Caption = caption
+		stats.Generated++
+		if err := writeCaptionCache(cacheDir, cacheKey, caption); err != nil {
+			uc.logger.Error("internal.application.use_cases.orchestrator.training_file.ProcessAndIngest write caption cache failed", err, "cache_dir", cacheDir)
+		}
+	}
+	return out, stats, nil
+}
+
+// embedFigureCaptions sets CaptionVector for the captioned figures. Vectors
+// that fail validation are dropped; the caption stays in the payload for BM25.
+func (uc *trainingFileUseCase) embedFigureCaptions(ctx context.Context, trainingUUID string, figures []figureUnit, batchSize int) error {
+	indexes := make([]int, 0, len(figures))
+	captions := make([]string, 0, len(figures))
+	for i, figure := range figures {
+		if figure.Caption != "" {
+			indexes = append(indexes, i)
+			captions = append(captions, figure.Caption)
+		}
+	}
+	if len(captions) == 0 {
+		return nil
+	}
+	vectors, err := uc.embedTextAsyncByKafka(ctx, trainingUUID, captions, batchSize)
+	if err != nil {
+		return err
+	}
+	for i, index := range indexes {
+		if isZeroNormVector(vectors[i]) {
+			continue
+		}
+		figures[index].CaptionVector = vectors[i]
+	}
+	return nil
+}
+
+func normalizeCaption(text string) string {
+	caption := strings.Join(strings.Fields(text), " ")
+	if runes := []rune(caption); len(runes) > captionMaxChars {
+		caption = string(runes[:captionMaxChars])
+	}
+	return caption
+}
+
+func readCaptionCache(cacheDir string, key string) (string, bool) {
+	data, err := os.ReadFile(filepath.Join(cacheDir, key+".txt"))
+	if err != nil {
+		return "", false
+	}
+	caption := strings.TrimSpace(string(data))
+	return caption, caption != ""
+}
+
+func writeCaptionCache(cacheDir string, key string, caption string) error {
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return err
+	}
+	tmpPath := filepath.Join(cacheDir, key+".txt.tmp")
+	if err := os.WriteFile(tmpPath, []byte(caption), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, filepath.Join(cacheDir, key+".txt"))
+}
+
+func (uc *trainingFileUseCase) newLLMServiceClient(ctx context.Context) (*grpc.ClientConn, pb.LlmServiceClient, error) {
+	host := strings.TrimSpace(os.Getenv("LLM_SERVICE_HOST"))
+	if host == "" {
+		host = "localhost"
+	}
+	port := strings.TrimSpace(uc.Config.LLMService.Port)
+	if port == "" {
+		return nil, nil, fmt.Errorf("llm grpc port is empty")
+	}
+
+	addr := net.JoinHostPort(host, port)
+	dialCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	conn, err := grpc.DialContext(
+		dialCtx,
+		addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithBlock(),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot connect to llm service at %s; ensure cmd/llm_service/main.go is running: %w", addr, err)
+	}
+
+	return conn, pb.NewLlmServiceClient(conn), nil
+}
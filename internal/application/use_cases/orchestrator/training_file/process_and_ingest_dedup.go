@@ -0,0 +1,92 @@
+package trainingfile
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	pb "rag_imagetotext_texttoimage/proto"
+)
+
+func fileContentHash(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// documentUnchanged reports whether the previous finished run ingested the
+// same document content and its points are still in the collection.
+func (uc *trainingFileUseCase) documentUnchanged(ctx context.Context, collectionName string, previous *ingestManifest, docHash string) bool {
+	if previous == nil || docHash == "" || previous.DocHash != docHash {
+		return false
+	}
+	probeText := ""
+	if len(previous.Chunks) > 0 {
+		probeText = strings.TrimSpace(previous.Chunks[0].Text)
+	}
+	stored, err := uc.verifyVectorDBByDocID(ctx, collectionName, previous.UUID, docHash, probeText)
+	if err != nil {
+		uc.logger.Error("internal.application.use_cases.orchestrator.training_file.ProcessAndIngest unchanged document check failed, re-ingesting", err, "uuid", previous.UUID)
+		return false
+	}
+	return stored
+}
+
+// deleteStaleDocPoints removes the points of docID not written by the ingest
+// run ingestRunID. Chunks that are still produced were just upserted under the
+// same deterministic id and carry the run id, so only chunks that disappeared
+// match, including after a re-ingest of the same file with a new chunker.
+func (uc *trainingFileUseCase) deleteStaleDocPoints(ctx context.Context, collectionName, docID, ingestRunID string) error {
+	if ingestRunID == "" {
+		return errors.New("ingest_run_id is required to delete stale points")
+	}
+
+	conn, ragClient, err := uc.newRAGServiceClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	resp, err := ragClient.DeletePointFilter(ctx, &pb.DeletePointFilterRequest{
+		CollectionName: collectionName,
+		Filter: &pb.Filter{
+			Must: []*pb.FieldCondition{
+				{
+					Key:      "doc_id",
+					Operator: "eq",
+					ScalarValue: &pb.FieldCondition_StringValue{
+						StringValue: docID,
+					},
+				},
+			},
+			MustNot: []*pb.FieldCondition{
+				{
+					Key:      "ingest_run_id",
+					Operator: "eq",
+					ScalarValue: &pb.FieldCondition_StringValue{
+						StringValue: ingestRunID,
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if resp == nil || !resp.Status {
+		return errors.New("delete stale points returned status=false")
+	}
+	return nil
+}
@@ -1,30 +1,24 @@
 package trainingfile
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"image"
-	_ "image/gif"
-	_ "image/jpeg"
-	_ "image/png"
-	"os"
 	"strings"
 	"time"
 
 	"rag_imagetotext_texttoimage/internal/application/ports"
 )
 
-const embeddingImageTargetSize = 224
+const embeddingTextReplyTimeout = 90 * time.Second
 
 func (uc *trainingFileUseCase) embedTextAsyncByKafka(ctx context.Context, uuid string, texts []string, reqBatchSize int) ([][]float32, error) {
 	if len(texts) == 0 {
 		return nil, errors.New("texts is empty")
 	}
-	if uc.KafkaPublisher == nil || uc.KafkaConsumer == nil {
-		return nil, errors.New("kafka publisher/consumer is not configured")
+	if uc.KafkaReplies == nil {
+		return nil, errors.New("kafka request reply is not configured")
 	}
 
 	topicReq := strings.TrimSpace(uc.Config.EmbeddingService.Topics.BatchTextRequest)
@@ -37,7 +31,6 @@ func (uc *trainingFileUseCase) embedTextAsyncByKafka(ctx context.Context, uuid s
 	totalBatches := (len(texts) + batchSize - 1) / batchSize
 	startedAt := time.Now()
 
-	groupID := "training-file-embed-text-" + uuid
 	allEmbeddings := make([][]float32, 0, len(texts))
 
 	for start, batchIndex := 0, 0; start < len(texts); start, batchIndex = start+batchSize, batchIndex+1 {
@@ -48,42 +41,28 @@ func (uc *trainingFileUseCase) embedTextAsyncByKafka(ctx context.Context, uuid s
 		batchTexts := texts[start:end]
 		correlationID := fmt.Sprintf("embed-text-%s-%d-%d", uuid, batchIndex, time.Now().UnixNano())
 
-		payload, err := json.Marshal(map[string]any{
-			"correlation_id": correlationID,
-			"texts":          batchTexts,
-		})
+		res, err := uc.requestEmbedding(ctx, topicReq, topicRes, correlationID, map[string]any{
+			"texts": batchTexts,
+		}, embeddingTextReplyTimeout)
 		if err != nil {
-			return nil, fmt.Errorf("marshal embedding request for batch %d: %w", batchIndex, err)
-		}
-
-		if err := uc.KafkaPublisher.Publish(ctx, ports.PublishMessageInput{
-			Topic: topicReq,
-			Message: ports.KafkaMessage{
-				Key:   []byte(correlationID),
-				Value: payload,
-				Headers: map[string]string{
-					"correlation_id": correlationID,
-					"source":         "training_file_orchestrator",
-				},
-			},
-		}); err != nil {
-			return nil, fmt.Errorf("publish embedding text request for batch %d: %w", batchIndex, err)
-		}
-
-		res, err := uc.pollEmbeddingResult(ctx, topicRes, groupID, correlationID)
-		if err != nil {
-			return nil, fmt.Errorf("poll embedding text result for batch %d: %w", batchIndex, err)
+			return nil, fmt.Errorf("embedding text request for batch %d: %w", batchIndex, err)
 		}
 		if strings.EqualFold(strings.TrimSpace(res.Status), "failed") {
 			return nil, fmt.Errorf("embedding text failed for batch %d: %s", batchIndex, res.Message)
 		}
-		if len(res.Embeddings) != len(batchTexts) {
-			return nil, fmt.Errorf("embedding result size mismatch for batch %d: expected=%d got=%d", batchIndex, len(batchTexts), len(res.Embeddings))
+		embeddings, itemErrors, err := res.itemResults(len(batchTexts))
+		if err != nil {
+			return nil, fmt.Errorf("batch %d: %w", batchIndex, err)
+		}
+		for i, itemErr := range itemErrors {
+			if itemErr != "" {
+				return nil, fmt.Errorf("embedding text failed for batch %d item %d: %s", batchIndex, i, itemErr)
+			}
 		}
 
-		allEmbeddings = append(allEmbeddings, res.Embeddings...)
+		allEmbeddings = append(allEmbeddings, embeddings...)
 		zeroNormInBatch := 0
-		for _, emb := range res.Embeddings {
+		for _, emb := range embeddings {
 			if isZeroNormVector(emb) {
 				zeroNormInBatch++
 			}
@@ -118,40 +97,21 @@ func (uc *trainingFileUseCase) embedTextAsyncByKafka(ctx context.Context, uuid s
 	return allEmbeddings, nil
 }
 
-func (uc *trainingFileUseCase) embedSingleImageAsyncByKafka(ctx context.Context, uuid, imagePath string) ([]float32, error) {
-	if uc.KafkaPublisher == nil || uc.KafkaConsumer == nil {
-		return nil, errors.New("kafka publisher/consumer is not configured")
-	}
-
-	fileBytes, err := os.ReadFile(imagePath)
-	if err != nil {
-		return nil, err
-	}
-	img, _, err := image.Decode(bytes.NewReader(fileBytes))
-	if err != nil {
-		return nil, err
-	}
-	rgbBytes, width, height := imageToRGBBytesWithSize(img, embeddingImageTargetSize, embeddingImageTargetSize)
-
-	topicReq := strings.TrimSpace(uc.Config.EmbeddingService.Topics.BatchImageRequest)
-	topicRes := strings.TrimSpace(uc.Config.EmbeddingService.Topics.BatchImageResult)
-	if topicReq == "" || topicRes == "" {
-		return nil, errors.New("embedding image request/result topic is empty")
-	}
-
-	correlationID := fmt.Sprintf("embed-image-%s-%d", uuid, time.Now().UnixNano())
-	payload, err := json.Marshal(map[string]any{
-		"correlation_id": correlationID,
-		"images":         [][]byte{rgbBytes},
-		"width":          width,
-		"height":         height,
-		"channels":       3,
-	})
+// requestEmbedding publishes one embedding request and waits for its reply
+// on the shared reply consumer.
+func (uc *trainingFileUseCase) requestEmbedding(
+	ctx context.Context,
+	topicReq, topicRes, correlationID string,
+	body map[string]any,
+	timeout time.Duration,
+) (embeddingBatchResult, error) {
+	body["correlation_id"] = correlationID
+	payload, err := json.Marshal(body)
 	if err != nil {
-		return nil, fmt.Errorf("marshal embedding image request: %w", err)
+		return embeddingBatchResult{}, fmt.Errorf("marshal embedding request: %w", err)
 	}
 
-	if err := uc.KafkaPublisher.Publish(ctx, ports.PublishMessageInput{
+	msg, err := uc.KafkaReplies.Request(ctx, ports.PublishMessageInput{
 		Topic: topicReq,
 		Message: ports.KafkaMessage{
 			Key:   []byte(correlationID),
@@ -161,74 +121,43 @@ func (uc *trainingFileUseCase) embedSingleImageAsyncByKafka(ctx context.Context,
 				"source":         "training_file_orchestrator",
 			},
 		},
-	}); err != nil {
-		return nil, fmt.Errorf("publish embedding image request: %w", err)
-	}
-
-	res, err := uc.pollEmbeddingResult(ctx, topicRes, "training-file-embed-image-"+uuid, correlationID)
+	}, topicRes, correlationID, timeout)
 	if err != nil {
-		return nil, err
-	}
-	if strings.EqualFold(strings.TrimSpace(res.Status), "failed") {
-		return nil, fmt.Errorf("embedding image failed: %s", res.Message)
+		return embeddingBatchResult{}, err
 	}
-	if len(res.Embeddings) == 0 {
-		return nil, errors.New("embedding image result is empty")
+
+	var res embeddingBatchResult
+	if err := json.Unmarshal(msg.Message.Value, &res); err != nil {
+		return embeddingBatchResult{}, fmt.Errorf("decode embedding result for correlation_id=%s: %w", correlationID, err)
 	}
-	return res.Embeddings[0], nil
+	res.CorrelationID = correlationID
+	return res, nil
 }
 
-func (uc *trainingFileUseCase) pollEmbeddingResult(ctx context.Context, topic, groupID, correlationID string) (embeddingBatchResult, error) {
-	result := embeddingBatchResult{}
-	timeoutCtx, cancel := context.WithTimeout(ctx, 90*time.Second)
-	defer cancel()
-
-	foundCh := make(chan embeddingBatchResult, 1)
-	errCh := make(chan error, 1)
-	doneCh := make(chan struct{})
-
-	go func() {
-		defer close(doneCh)
-		consumeErr := uc.KafkaConsumer.Consume(timeoutCtx, topic, groupID, func(hctx context.Context, msg ports.ConsumeMessage) error {
-			var payload embeddingBatchResult
-			if err := json.Unmarshal(msg.Message.Value, &payload); err != nil {
-				return nil
-			}
-
-			cid := strings.TrimSpace(payload.CorrelationID)
-			if cid == "" {
-				cid = strings.TrimSpace(string(msg.Message.Key))
-			}
-			if cid == "" {
-				cid = strings.TrimSpace(msg.Message.Headers["correlation_id"])
-			}
-			if cid != correlationID {
-				return nil
-			}
+// itemResults returns the vector or the error of every item of a batch of
+// count items, in request order.
+func (r embeddingBatchResult) itemResults(count int) ([][]float32, []string, error) {
+	vectors := make([][]float32, count)
+	itemErrors := make([]string, count)
+	if r.SchemaVersion < 2 && len(r.Items) == 0 {
+		if len(r.Embeddings) != count {
+			return nil, nil, fmt.Errorf("embedding result size mismatch: expected=%d got=%d", count, len(r.Embeddings))
+		}
+		copy(vectors, r.Embeddings)
+		return vectors, itemErrors, nil
+	}
 
-			payload.CorrelationID = cid
-			select {
-			case foundCh <- payload:
-			default:
-			}
-			cancel()
-			return nil
-		})
-		if consumeErr != nil && !errors.Is(consumeErr, context.Canceled) && !errors.Is(consumeErr, context.DeadlineExceeded) {
-			errCh <- consumeErr
+	if len(r.Items) != count {
+		return nil, nil, fmt.Errorf("embedding result size mismatch: expected=%d got=%d", count, len(r.Items))
+	}
+	seen := make([]bool, count)
+	for _, item := range r.Items {
+		if item.Index < 0 || item.Index >= count || seen[item.Index] {
+			return nil, nil, fmt.Errorf("embedding result has invalid item index %d", item.Index)
 		}
-	}()
-
-	select {
-	case res := <-foundCh:
-		result = res
-		<-doneCh
-		return result, nil
-	case err := <-errCh:
-		<-doneCh
-		return result, err
-	case <-timeoutCtx.Done():
-		<-doneCh
-		return result, fmt.Errorf("poll embedding result timeout for correlation_id=%s", correlationID)
+		seen[item.Index] = true
+		vectors[item.Index] = item.Embedding
+		itemErrors[item.Index] = item.Error
 	}
+	return vectors, itemErrors, nil
 }
@@ -0,0 +1,251 @@
+package trainingfile
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"rag_imagetotext_texttoimage/internal/application/dtos"
+	"rag_imagetotext_texttoimage/internal/application/ports"
+)
+
+const (
+	embeddingImageTargetSize     = 224
+	embeddingImageChannels       = 3
+	defaultImageBatchSize        = 16
+	defaultImageBatchConcurrency = 2
+	embeddingImageBatchTimeout   = 90 * time.Second
+
+	// maxImageRequestBytes bounds the JSON value of one batch_image_request
+	// message. kafka-go rejects messages above the writer's BatchBytes
+	// (1 MiB by default) and the broker's message.max.bytes defaults to about
+	// the same, so a batch is cut before its base64 pixels reach this size;
+	// the rest is headroom for the envelope and headers. At 224x224x3 that is
+	// 4 images per message.
+	maxImageRequestBytes = 900 << 10
+)
+
+type imageEmbeddingBatch struct {
+	index int
+	items []int
+}
+
+// embedImagesAsyncByKafka embeds the images in batches on the
+// batch_image_request topic. Every image is resized to the 224x224 model
+// input first, so a batch shares one width and height. At most concurrency
+// batches are in flight; each reply comes back through the shared reply
+// consumer and is matched to the images by position. The
+// returned vectors line up with imagePaths, nil where the image failed; each
+// failure carries its own reason.
+func (uc *trainingFileUseCase) embedImagesAsyncByKafka(
+	ctx context.Context,
+	uuid string,
+	imagePaths []string,
+) ([][]float32, []dtos.ImageEmbeddingFailure, error) {
+	if uc.KafkaReplies == nil {
+		return nil, nil, errors.New("kafka request reply is not configured")
+	}
+	topicReq := strings.TrimSpace(uc.Config.EmbeddingService.Topics.BatchImageRequest)
+	topicRes := strings.TrimSpace(uc.Config.EmbeddingService.Topics.BatchImageResult)
+	if topicReq == "" || topicRes == "" {
+		return nil, nil, errors.New("embedding image request/result topic is empty")
+	}
+
+	vectors := make([][]float32, len(imagePaths))
+	failures := make([]dtos.ImageEmbeddingFailure, 0)
+	fail := func(index int, reason string) {
+		failures = append(failures, dtos.ImageEmbeddingFailure{ImagePath: imagePaths[index], Reason: reason})
+	}
+
+	pixels := make([][]byte, len(imagePaths))
+	loaded := make([]int, 0, len(imagePaths))
+	for i, imagePath := range imagePaths {
+		rgb, err := loadEmbeddingImage(imagePath)
+		if err != nil {
+			fail(i, err.Error())
+			continue
+		}
+		pixels[i] = rgb
+		loaded = append(loaded, i)
+	}
+	if len(loaded) == 0 {
+		return vectors, failures, nil
+	}
+
+	batchSize, concurrency := uc.resolveImageBatching()
+	batches := splitImageBatches(loaded, pixels, batchSize, maxImageRequestBytes)
+
+	var (
+		mu        sync.Mutex
+		completed int
+		fatalErr  error
+		wg        sync.WaitGroup
+	)
+	workCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	failBatch := func(batch *imageEmbeddingBatch, correlationID string, reason string) {
+		for _, item := range batch.items {
+			fail(item, reason)
+		}
+		uc.logger.Error(
+			"internal.application.use_cases.orchestrator.training_file.embedImagesAsyncByKafka batch failed",
+			errors.New(reason),
+			"uuid", uuid,
+			"batch_index", batch.index,
+			"batch_size", len(batch.items),
+			"correlation_id", correlationID,
+		)
+	}
+	runBatch := func(batch *imageEmbeddingBatch) {
+		correlationID := fmt.Sprintf("embed-image-%s-%d-%d", uuid, batch.index, time.Now().UnixNano())
+		images := make([][]byte, 0, len(batch.items))
+		for _, item := range batch.items {
+			images = append(images, pixels[item])
+		}
+		batchStartedAt := time.Now()
+		res, err := uc.requestEmbedding(workCtx, topicReq, topicRes, correlationID, map[string]any{
+			"images":   images,
+			"width":    embeddingImageTargetSize,
+			"height":   embeddingImageTargetSize,
+			"channels": embeddingImageChannels,
+		}, embeddingImageBatchTimeout)
+
+		mu.Lock()
+		defer mu.Unlock()
+		completed++
+		switch {
+		case errors.Is(err, ports.ErrReplyTimeout):
+			failBatch(batch, correlationID, err.Error())
+			return
+		case err != nil:
+			if fatalErr == nil {
+				fatalErr = fmt.Errorf("embedding image request for batch %d: %w", batch.index, err)
+			}
+			cancel()
+			return
+		case strings.EqualFold(strings.TrimSpace(res.Status), "failed"):
+			failBatch(batch, correlationID, "embedding image failed: "+res.Message)
+			return
+		}
+		embeddings, itemErrors, err := res.itemResults(len(batch.items))
+		if err != nil {
+			failBatch(batch, correlationID, "embedding image "+err.Error())
+			return
+		}
+		for i, item := range batch.items {
+			switch {
+			case itemErrors[i] != "":
+				fail(item, "embedding image failed: "+itemErrors[i])
+			case len(embeddings[i]) == 0 || isZeroNormVector(embeddings[i]):
+				fail(item, "embedding image result is empty")
+			default:
+				vectors[item] = embeddings[i]
+			}
+		}
+		uc.logger.Info(
+			"internal.application.use_cases.orchestrator.training_file.embedImagesAsyncByKafka batch completed",
+			"uuid", uuid,
+			"batch_index", batch.index,
+			"batch_total", len(batches),
+			"batch_size", len(batch.items),
+			"progress_bar", formatEmbeddingProgressBar(completed, len(batches), 28),
+			"latency_ms", time.Since(batchStartedAt).Milliseconds(),
+		)
+	}
+
+	startedAt := time.Now()
+	queue := make(chan *imageEmbeddingBatch)
+	for w := 0; w < min(concurrency, len(batches)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range queue {
+				runBatch(batch)
+			}
+		}()
+	}
+feed:
+	for _, batch := range batches {
+		select {
+		case queue <- batch:
+		case <-workCtx.Done():
+			break feed
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if fatalErr != nil {
+		return nil, nil, fatalErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	uc.logger.Info(
+		"internal.application.use_cases.orchestrator.training_file.embedImagesAsyncByKafka completed",
+		"uuid", uuid,
+		"images", len(imagePaths),
+		"batches", len(batches),
+		"failed", len(failures),
+		"latency_ms", time.Since(startedAt).Milliseconds(),
+	)
+	return vectors, failures, nil
+}
+
+// splitImageBatches cuts the loaded images into batches of at most maxItems
+// images whose base64-encoded pixels stay within maxBytes. A batch always
+// holds at least one image.
+func splitImageBatches(loaded []int, pixels [][]byte, maxItems int, maxBytes int) []*imageEmbeddingBatch {
+	batches := make([]*imageEmbeddingBatch, 0)
+	start, size := 0, 0
+	for i, item := range loaded {
+		encoded := base64.StdEncoding.EncodedLen(len(pixels[item])) + 3
+		if i > start && (i-start >= maxItems || size+encoded > maxBytes) {
+			batches = append(batches, &imageEmbeddingBatch{index: len(batches), items: loaded[start:i]})
+			start, size = i, 0
+		}
+		size += encoded
+	}
+	if start < len(loaded) {
+		batches = append(batches, &imageEmbeddingBatch{index: len(batches), items: loaded[start:]})
+	}
+	return batches
+}
+
+func (uc *trainingFileUseCase) resolveImageBatching() (int, int) {
+	batchSize := uc.Config.FileTraining.ImageBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultImageBatchSize
+	}
+	concurrency := uc.Config.FileTraining.ImageBatchConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultImageBatchConcurrency
+	}
+	return batchSize, concurrency
+}
+
+// loadEmbeddingImage decodes an image and returns its RGB bytes at the model
+// input size.
+func loadEmbeddingImage(imagePath string) ([]byte, error) {
+	fileBytes, err := os.ReadFile(imagePath)
+	if err != nil {
+		return nil, fmt.Errorf("read image: %w", err)
+	}
+	img, _, err := image.Decode(bytes.NewReader(fileBytes))
+	if err != nil {
+		return nil, fmt.Errorf("decode image: %w", err)
+	}
+	rgb, _, _ := imageToRGBBytesWithSize(img, embeddingImageTargetSize, embeddingImageTargetSize)
+	return rgb, nil
+}
@@ -0,0 +1,134 @@
+package trainingfile
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"rag_imagetotext_texttoimage/internal/application/dtos"
+	"rag_imagetotext_texttoimage/internal/application/ports"
+	"rag_imagetotext_texttoimage/internal/util"
+)
+
+// kafkaWriterBatchBytes is kafka-go's default Writer.BatchBytes; a bigger
+// message fails with MessageTooLargeError.
+const kafkaWriterBatchBytes = 1 << 20
+
+type nopLogger struct{}
+
+func (nopLogger) Info(string, ...any)         {}
+func (nopLogger) Error(string, error, ...any) {}
+func (nopLogger) Debug(string, ...any)        {}
+func (nopLogger) Close() error                { return nil }
+
+// fakeImageReplies answers batch image requests like the dlmodel service and
+// rejects messages the Kafka writer would refuse.
+type fakeImageReplies struct {
+	mu       sync.Mutex
+	requests int
+	maxBytes int
+}
+
+func (f *fakeImageReplies) Request(ctx context.Context, input ports.PublishMessageInput, replyTopic string, correlationID string, timeout time.Duration) (ports.ConsumeMessage, error) {
+	size := len(input.Message.Key) + len(input.Message.Value)
+	for k, v := range input.Message.Headers {
+		size += len(k) + len(v)
+	}
+	f.mu.Lock()
+	f.requests++
+	f.maxBytes = max(f.maxBytes, size)
+	f.mu.Unlock()
+	if size > kafkaWriterBatchBytes {
+		return ports.ConsumeMessage{}, fmt.Errorf("message too large: %d bytes", size)
+	}
+
+	var request dtos.EmbedBatchImageRequest
+	if err := json.Unmarshal(input.Message.Value, &request); err != nil {
+		return ports.ConsumeMessage{}, err
+	}
+	result := dtos.EmbedBatchResult{
+		SchemaVersion: dtos.EmbeddingResultSchemaVersion,
+		CorrelationID: correlationID,
+		Status:        dtos.EmbeddingStatusSuccess,
+		Count:         len(request.Images),
+	}
+	for i := range request.Images {
+		result.Items = append(result.Items, dtos.EmbedBatchItemResult{Index: i, Embedding: []float32{1, float32(i)}})
+	}
+	value, err := json.Marshal(result)
+	if err != nil {
+		return ports.ConsumeMessage{}, err
+	}
+	return ports.ConsumeMessage{Topic: replyTopic, Message: ports.KafkaMessage{Value: value}}, nil
+}
+
+func (f *fakeImageReplies) Close() error { return nil }
+
+func TestEmbedImagesAsyncByKafkaDefaultConfigTwentyFigures(t *testing.T) {
+	dir := t.TempDir()
+	paths := make([]string, 20)
+	for i := range paths {
+		img := image.NewRGBA(image.Rect(0, 0, 300, 200))
+		for y := 0; y < 200; y++ {
+			for x := 0; x < 300; x++ {
+				img.Set(x, y, color.RGBA{uint8(x + i), uint8(y * i), uint8(x ^ y), 255})
+			}
+		}
+		paths[i] = filepath.Join(dir, fmt.Sprintf("figure_%d.png", i))
+		f, err := os.Create(paths[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := png.Encode(f, img); err != nil {
+			t.Fatal(err)
+		}
+		_ = f.Close()
+	}
+
+	cfg := util.Config{}
+	cfg.EmbeddingService.Topics.BatchImageRequest = "embedding.embed.batch_image.request"
+	cfg.EmbeddingService.Topics.BatchImageResult = "embedding.embed.batch_image.result"
+	cfg.FileTraining.ImageBatchSize = 16
+	cfg.FileTraining.ImageBatchConcurrency = 2
+	replies := &fakeImageReplies{}
+	uc := NewTrainingFileUseCase(nopLogger{}, nil, replies, cfg, nil).(*trainingFileUseCase)
+
+	vectors, failures, err := uc.embedImagesAsyncByKafka(context.Background(), "doc", paths)
+	if err != nil {
+		t.Fatalf("embed images: %v", err)
+	}
+	if len(failures) != 0 {
+		t.Fatalf("unexpected failures: %+v", failures)
+	}
+	for i, vector := range vectors {
+		if len(vector) == 0 {
+			t.Fatalf("image %d has no vector", i)
+		}
+	}
+	if replies.maxBytes > kafkaWriterBatchBytes {
+		t.Fatalf("largest request is %d bytes, above %d", replies.maxBytes, kafkaWriterBatchBytes)
+	}
+	if replies.requests < 5 {
+		t.Fatalf("expected the 20 images to be split by size, got %d requests", replies.requests)
+	}
+}
+
+func TestSplitImageBatchesKeepsOversizedImageAlone(t *testing.T) {
+	pixels := [][]byte{make([]byte, 10), make([]byte, 2000), make([]byte, 10), make([]byte, 10)}
+	batches := splitImageBatches([]int{0, 1, 2, 3}, pixels, 16, 100)
+	got := [][]int{}
+	for _, batch := range batches {
+		got = append(got, batch.items)
+	}
+	if fmt.Sprint(got) != "[[0] [1] [2 3]]" {
+		t.Fatalf("batches = %v", got)
+	}
+}
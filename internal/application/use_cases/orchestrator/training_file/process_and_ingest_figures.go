@@ -0,0 +1,145 @@
+package trainingfile
+
+import (
+	"context"
+	"errors"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"rag_imagetotext_texttoimage/internal/application/dtos"
+	domain "rag_imagetotext_texttoimage/internal/domain/entity_objects"
+)
+
+const (
+	modalityText  = "text"
+	modalityImage = "image"
+)
+
+// figureUnit is one image of a merged chunk. Every figure is ingested as its
+// own image point whose parent_id is the point id of the chunk.
+// Caption and CaptionVector are set by the figure_caption step.
+type figureUnit struct {
+	ChunkIndex    int       `json:"chunk_index"`
+	ImagePath     string    `json:"image_path"`
+	Vector        []float32 `json:"vector,omitempty"`
+	Caption       string    `json:"caption,omitempty"`
+	CaptionVector []float32 `json:"caption_vector,omitempty"`
+}
+
+// collectFigures lists the images of the chunks in document order; an image
+// referenced by several chunks belongs to the first one.
+func collectFigures(chunks []pipelineChunk) []figureUnit {
+	figures := make([]figureUnit, 0)
+	seen := map[string]struct{}{}
+	for i, chunk := range chunks {
+		for _, imagePath := range chunk.ImagePaths {
+			imagePath = strings.TrimSpace(imagePath)
+			if imagePath == "" {
+				continue
+			}
+			if _, ok := seen[imagePath]; ok {
+				continue
+			}
+			seen[imagePath] = struct{}{}
+			figures = append(figures, figureUnit{ChunkIndex: i, ImagePath: imagePath})
+		}
+	}
+	return figures
+}
+
+// embedFigures returns the figures that got an image vector and the figures
+// that did not, with the reason for each.
+func (uc *trainingFileUseCase) embedFigures(ctx context.Context, trainingUUID string, markdownPath string, figures []figureUnit) ([]figureUnit, []dtos.ImageEmbeddingFailure, error) {
+	failures := make([]dtos.ImageEmbeddingFailure, 0)
+	pending := make([]figureUnit, 0, len(figures))
+	absPaths := make([]string, 0, len(figures))
+	for _, figure := range figures {
+		absPath := resolveImagePath(markdownPath, figure.ImagePath)
+		if absPath == "" {
+			failures = append(failures, dtos.ImageEmbeddingFailure{ImagePath: figure.ImagePath, Reason: "image file not found"})
+			continue
+		}
+		pending = append(pending, figure)
+		absPaths = append(absPaths, absPath)
+	}
+	if len(pending) == 0 {
+		return nil, failures, nil
+	}
+
+	vectors, embedFailures, err := uc.embedImagesAsyncByKafka(ctx, trainingUUID, absPaths)
+	if err != nil {
+		return nil, nil, err
+	}
+	failures = append(failures, embedFailures...)
+
+	embedded := make([]figureUnit, 0, len(pending))
+	for i, figure := range pending {
+		if len(vectors[i]) == 0 {
+			continue
+		}
+		figure.Vector = vectors[i]
+		embedded = append(embedded, figure)
+	}
+	for _, failure := range failures {
+		uc.logger.Error(
+			"internal.application.use_cases.orchestrator.training_file.ProcessAndIngest image embedding failed",
+			errors.New(failure.Reason),
+			"uuid", trainingUUID,
+			"image_path", failure.ImagePath,
+		)
+	}
+	return embedded, failures, nil
+}
+
+// figurePayload copies the position and text of the parent chunk, so a
+// figure hit carries the text it illustrates. The rag service indexes only
+// the caption (ocr_text) of a figure for BM25, not the copied fields.
+func figurePayload(parent map[string]string, parentID string, figure figureUnit) map[string]string {
+	payload := map[string]string{
+		"doc_id":        parent["doc_id"],
+		"doc_hash":      parent["doc_hash"],
+		"ingest_run_id": parent["ingest_run_id"],
+		"unit_type":     unitTypeFigure,
+		"modality":      modalityImage,
+		"parent_id":     parentID,
+		"text":          parent["text"],
+		"chunk_index":   parent["chunk_index"],
+		"page":          parent["page"],
+		"lang":          parent["lang"],
+		"source_path":   parent["source_path"],
+		"image_path":    figure.ImagePath,
+		"object_key":    figureObjectKey(parent["doc_id"], figure.ImagePath),
+		"has_figure":    "true",
+	}
+	if figure.Caption != "" {
+		payload["ocr_text"] = figure.Caption
+	}
+	for _, key := range []string{"section_title", "heading_path"} {
+		if v := parent[key]; v != "" {
+			payload[key] = v
+		}
+	}
+	payload["chunk_hash"], _ = payloadPointID(payload)
+	return payload
+}
+
+// figureObjectKey is the key the artifact upload gives the image in MinIO:
+// <doc_id>/<file name>.
+func figureObjectKey(docID string, imagePath string) string {
+	return path.Join(docID, path.Base(filepath.ToSlash(imagePath)))
+}
+
+// payloadPointID returns the chunk hash and the point id the rag service
+// derives for a payload, so a figure can reference its parent before the
+// parent is inserted. chunk_index is not hashed on purpose: chunks of a
+// document with the same text (repeated headers, boilerplate) share one point.
+func payloadPointID(payload map[string]string) (string, string) {
+	chunkHash := domain.ChunkContentHash(domain.PointPayload{
+		UnitType:  payload["unit_type"],
+		Text:      payload["text"],
+		OCRText:   payload["ocr_text"],
+		ImagePath: payload["image_path"],
+	})
+	return chunkHash, domain.DeterministicPointID(payload["doc_id"], chunkHash)
+}
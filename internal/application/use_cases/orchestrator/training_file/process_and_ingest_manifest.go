@@ -0,0 +1,209 @@
+package trainingfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"rag_imagetotext_texttoimage/internal/application/dtos"
+)
+
+const ingestManifestVersion = 7
+
+// ingestManifest is the checkpoint of one ProcessAndIngest run, stored at
+// data/manifest/<uuid>.json and rewritten after every completed step.
+// Chunks and TextVectors are the merged chunks after embedding_semantic;
+// ParsedChunks are the chunks before embedding, kept so a run that stopped
+// during embedding does not have to parse the markdown again.
+type ingestManifest struct {
+	Version        int                          `json:"version"`
+	UUID           string                       `json:"uuid"`
+	URLDownload    string                       `json:"url_download"`
+	CompletedSteps []string                     `json:"completed_steps"`
+	DownloadPath   string                       `json:"download_path,omitempty"`
+	DocHash        string                       `json:"doc_hash,omitempty"`
+	MarkdownPath   string                       `json:"markdown_path,omitempty"`
+	ArtifactPaths  []string                     `json:"artifact_paths,omitempty"`
+	UploadedFiles  int                          `json:"uploaded_files"`
+	ParsedChunks   []pipelineChunk              `json:"parsed_chunks,omitempty"`
+	Chunks         []pipelineChunk              `json:"chunks,omitempty"`
+	TextVectors    [][]float32                  `json:"text_vectors,omitempty"`
+	Figures        []figureUnit                 `json:"figures,omitempty"`
+	FailedImages   []dtos.ImageEmbeddingFailure `json:"failed_images,omitempty"`
+	InsertedPoints int                          `json:"inserted_points"`
+	SkippedPoints  int                          `json:"skipped_points"`
+	UpdatedAt      time.Time                    `json:"updated_at"`
+}
+
+func newIngestManifest(trainingUUID, urlDownload string) *ingestManifest {
+	return &ingestManifest{
+		Version:        ingestManifestVersion,
+		UUID:           trainingUUID,
+		URLDownload:    urlDownload,
+		CompletedSteps: []string{},
+	}
+}
+
+// prepareIngestManifest picks the checkpoint a run starts from. force wipes
+// the manifest and working directories of the uuid; resume reuses the
+// manifest when it was written for the same url_download; a plain run drops
+// any old manifest so a later resume cannot mix two runs. For a plain run the
+// dropped manifest is also returned as previous when that run finished, so
+// the pipeline can skip a document whose content has not changed.
+func (uc *trainingFileUseCase) prepareIngestManifest(
+	req *dtos.ProcessAndIngestRequest,
+	trainingUUID string,
+	urlDownload string,
+	processDir string,
+	workDirs ...string,
+) (manifest *ingestManifest, previous *ingestManifest, err error) {
+	fresh := newIngestManifest(trainingUUID, urlDownload)
+	if req.Force {
+		if err := removeIngestState(trainingUUID, workDirs...); err != nil {
+			return nil, nil, fmt.Errorf("reset ingest state: %w", err)
+		}
+		uc.logger.Info("internal.application.use_cases.orchestrator.training_file.ProcessAndIngest forced rebuild, previous state removed", "uuid", trainingUUID)
+		return fresh, nil, nil
+	}
+
+	loaded, loadErr := loadIngestManifest(trainingUUID)
+	if loadErr != nil {
+		uc.logger.Error("internal.application.use_cases.orchestrator.training_file.ProcessAndIngest load manifest failed, starting over", loadErr, "uuid", trainingUUID)
+		loaded = nil
+	}
+	if loaded != nil && (loaded.Version != ingestManifestVersion || loaded.URLDownload != urlDownload) {
+		loaded = nil
+	}
+
+	if !req.Resume {
+		if err := removeIngestState(trainingUUID); err != nil {
+			return nil, nil, fmt.Errorf("reset ingest manifest: %w", err)
+		}
+		if loaded != nil && loaded.completed(ingestStepVerifyVectorDB) && loaded.DocHash != "" {
+			previous = loaded
+		}
+		return fresh, previous, nil
+	}
+
+	if loaded == nil {
+		uc.logger.Info("internal.application.use_cases.orchestrator.training_file.ProcessAndIngest no matching manifest, starting over", "uuid", trainingUUID)
+		return fresh, nil, nil
+	}
+	loaded.trimToResumable(processDir)
+	uc.logger.Info(
+		"internal.application.use_cases.orchestrator.training_file.ProcessAndIngest resuming from manifest",
+		"uuid", trainingUUID,
+		"completed_steps", strings.Join(loaded.CompletedSteps, ","),
+	)
+	return loaded, nil, nil
+}
+
+func ingestManifestPath(trainingUUID string) string {
+	return filepath.Join("data", "manifest", trainingUUID+".json")
+}
+
+// loadIngestManifest returns nil without error when no manifest exists.
+func loadIngestManifest(trainingUUID string) (*ingestManifest, error) {
+	raw, err := os.ReadFile(ingestManifestPath(trainingUUID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var manifest ingestManifest
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return nil, fmt.Errorf("decode ingest manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// saveIngestManifest writes through a temp file and a rename so a crash
+// mid-write leaves the previous checkpoint intact.
+func saveIngestManifest(manifest *ingestManifest) error {
+	manifest.UpdatedAt = time.Now().UTC()
+	raw, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	path := ingestManifestPath(manifest.UUID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, raw, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// removeIngestState deletes the manifest and the download, processed and
+// upload directories of a uuid so a forced run starts from nothing.
+func removeIngestState(trainingUUID string, dirs ...string) error {
+	if err := os.Remove(ingestManifestPath(trainingUUID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	for _, dir := range dirs {
+		if err := os.RemoveAll(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *ingestManifest) completed(step string) bool {
+	for _, done := range m.CompletedSteps {
+		if done == step {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *ingestManifest) markCompleted(step string) {
+	if !m.completed(step) {
+		m.CompletedSteps = append(m.CompletedSteps, step)
+	}
+}
+
+// trimToResumable keeps the longest run of completed steps, in pipeline
+// order, whose outputs are still usable; everything after it is redone.
+func (m *ingestManifest) trimToResumable(processDir string) {
+	kept := make([]string, 0, len(m.CompletedSteps))
+	for _, step := range ingestSteps {
+		if !m.completed(step) || !m.stepOutputUsable(step, processDir) {
+			break
+		}
+		kept = append(kept, step)
+	}
+	m.CompletedSteps = kept
+}
+
+func (m *ingestManifest) stepOutputUsable(step string, processDir string) bool {
+	switch step {
+	case ingestStepDownload:
+		return fileExists(m.DownloadPath) && m.DocHash != ""
+	case ingestStepAnalysis:
+		_, _, _, err := discoverProcessedArtifacts(processDir)
+		return err == nil
+	case ingestStepUploadMinio:
+		return fileExists(m.MarkdownPath)
+	case ingestStepParseMarkdown:
+		return len(m.ParsedChunks) > 0
+	case ingestStepEmbedding:
+		return len(m.Chunks) > 0 && len(m.Chunks) == len(m.TextVectors)
+	}
+	return true
+}
+
+func fileExists(path string) bool {
+	if path == "" {
+		return false
+	}
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -2,26 +2,81 @@ package trainingfile
 
 import (
 	"errors"
+	"slices"
+	"strconv"
 	"strings"
 	"unicode"
 )
 
+// parseLineBasedChunks splits the markdown into sentence chunks. Marker page
+// separators and headings are not chunked themselves; they set the position
+// recorded on the chunks that follow them. Pipe tables are kept whole, with
+// their caption, as table chunks.
 func parseLineBasedChunks(content string, cfg chunkingRuntimeConfig) ([]pipelineChunk, chunkingStats) {
 	lines := strings.Split(content, "\n")
 	tokens := make([]lineToken, 0, len(lines))
 	stats := chunkingStats{}
+	position := chunkPosition{}
+	headings := make([]string, 6)
 
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
 		if trimmed == "" {
 			continue
 		}
 
+		tableStart, caption := i, ""
+		if isTableCaption(trimmed) {
+			if next := nextNonEmptyLine(lines, i); next >= 0 && isTableLine(strings.TrimSpace(lines[next])) {
+				tableStart, caption = next, normalizeWhitespace(trimmed)
+			}
+		}
+		if isTableLine(strings.TrimSpace(lines[tableStart])) {
+			if table, end, ok := parseMarkdownTable(lines, tableStart); ok {
+				table.Caption = caption
+				// A caption below the table is only taken when no table
+				// follows it; otherwise it leads the next table.
+				if next := nextNonEmptyLine(lines, end); table.Caption == "" && next >= 0 && isTableCaption(strings.TrimSpace(lines[next])) {
+					if after := nextNonEmptyLine(lines, next); after < 0 || !isTableLine(strings.TrimSpace(lines[after])) {
+						table.Caption = normalizeWhitespace(lines[next])
+						end = next
+					}
+				}
+				tokens = append(tokens, lineToken{Type: lineTokenTable, Table: &table, Position: position})
+				stats.TableCount++
+				i = end
+				continue
+			}
+		}
+
+		if m := markerPagePattern.FindStringSubmatch(trimmed); m != nil {
+			if pageID, err := strconv.Atoi(m[1]); err == nil {
+				position.Page = pageID + 1
+			}
+			continue
+		}
+		if m := markdownHeadingPattern.FindStringSubmatch(trimmed); m != nil {
+			stats.RawSentenceCount++
+			stats.DroppedHeader++
+			title := cleanHeadingText(m[2])
+			if title == "" {
+				continue
+			}
+			level := len(m[1])
+			headings[level-1] = title
+			for i := level; i < len(headings); i++ {
+				headings[i] = ""
+			}
+			position.SectionTitle = title
+			position.HeadingPath = headingPath(headings)
+			continue
+		}
+
 		imageMatches := inlineImagePattern.FindAllStringSubmatch(trimmed, -1)
 		if len(imageMatches) > 0 {
 			for _, m := range imageMatches {
 				if len(m) == 2 {
-					tokens = append(tokens, lineToken{Type: lineTokenImage, Value: strings.TrimSpace(m[1])})
+					tokens = append(tokens, lineToken{Type: lineTokenImage, Value: strings.TrimSpace(m[1]), Position: position})
 				}
 			}
 			trimmed = inlineImagePattern.ReplaceAllString(trimmed, "")
@@ -42,17 +97,22 @@ func parseLineBasedChunks(content string, cfg chunkingRuntimeConfig) ([]pipeline
 		stats.MergedShort += lineStats.MergedShort
 
 		for _, sentence := range stableSentences {
-			tokens = append(tokens, lineToken{Type: lineTokenText, Value: sentence})
+			tokens = append(tokens, lineToken{Type: lineTokenText, Value: sentence, Position: position})
 		}
 	}
 
 	chunks := make([]pipelineChunk, 0, len(tokens))
 	for i := 0; i < len(tokens); i++ {
 		token := tokens[i]
+		if token.Type == lineTokenTable {
+			chunks = append(chunks, tableChunks(*token.Table, token.Position, cfg.MaxTableRows)...)
+			continue
+		}
 		if token.Type == lineTokenText {
 			chunk := pipelineChunk{
-				Text:       token.Value,
-				ImagePaths: []string{},
+				Text:          token.Value,
+				ImagePaths:    []string{},
+				chunkPosition: token.Position,
 			}
 			for i+1 < len(tokens) && tokens[i+1].Type == lineTokenImage {
 				chunk.ImagePaths = append(chunk.ImagePaths, tokens[i+1].Value)
@@ -67,8 +127,9 @@ func parseLineBasedChunks(content string, cfg chunkingRuntimeConfig) ([]pipeline
 			continue
 		}
 		chunks = append(chunks, pipelineChunk{
-			Text:       "",
-			ImagePaths: []string{token.Value},
+			Text:          "",
+			ImagePaths:    []string{token.Value},
+			chunkPosition: token.Position,
 		})
 	}
 
@@ -82,6 +143,35 @@ func parseLineBasedChunks(content string, cfg chunkingRuntimeConfig) ([]pipeline
 	return out, stats
 }
 
+func nextNonEmptyLine(lines []string, after int) int {
+	for i := after + 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) != "" {
+			return i
+		}
+	}
+	return -1
+}
+
+// headingPath returns the non-empty headings from the outermost level down;
+// a skipped level (## straight under #) leaves no gap in the path.
+func headingPath(headings []string) []string {
+	path := make([]string, 0, len(headings))
+	for _, heading := range headings {
+		if heading != "" {
+			path = append(path, heading)
+		}
+	}
+	return path
+}
+
+// cleanHeadingText drops the span anchors and emphasis marker puts in
+// headings.
+func cleanHeadingText(text string) string {
+	text = htmlTagPattern.ReplaceAllString(text, "")
+	text = strings.NewReplacer("**", "", "__", "", "`", "").Replace(text)
+	return normalizeWhitespace(text)
+}
+
 func splitSentences(input string) []string {
 	text := normalizeWhitespace(input)
 	if text == "" {
@@ -182,7 +272,11 @@ func mergeChunksBySemantic(chunks []pipelineChunk, embeddings [][]float32, cfg c
 		candidateText := normalizeWhitespace(currentChunk.Text + " " + chunks[i].Text)
 		candidateTokens := countTextTokens(candidateText)
 		canMergeByLength := cfg.MaxChunkTokens <= 0 || candidateTokens <= cfg.MaxChunkTokens
-		if sim >= threshold && canMergeByLength {
+		// A merged chunk keeps the position of its first part, so merging stops
+		// at a heading to keep section_title true for the whole chunk.
+		sameSection := slices.Equal(currentChunk.HeadingPath, chunks[i].HeadingPath)
+		textOnly := !currentChunk.isTable() && !chunks[i].isTable()
+		if sim >= threshold && canMergeByLength && sameSection && textOnly {
 			currentChunk.Text = candidateText
 			currentChunk.ImagePaths = append(currentChunk.ImagePaths, chunks[i].ImagePaths...)
 			currentVectors = append(currentVectors, embeddings[i])
@@ -211,18 +305,24 @@ func repairShortChunks(chunks []pipelineChunk, vectors [][]float32, cfg chunking
 		return chunks, vectors
 	}
 
+	// Tables are never merged: a short table stays as it is and a short text
+	// chunk next to a table is merged into its other neighbour.
 	for i := 0; i < len(chunks); i++ {
-		if !isShortChunk(chunks[i], cfg) || len(chunks) <= 1 {
+		if chunks[i].isTable() || !isShortChunk(chunks[i], cfg) || len(chunks) <= 1 {
 			continue
 		}
 
-		if i == 0 {
-			chunks[1].Text = normalizeWhitespace(chunks[0].Text + " " + chunks[1].Text)
-			chunks[1].ImagePaths = append(chunks[0].ImagePaths, chunks[1].ImagePaths...)
-			vectors[1] = averageVectors([][]float32{vectors[0], vectors[1]})
-			chunks = chunks[1:]
-			vectors = vectors[1:]
-			i = -1
+		if i == 0 || chunks[i-1].isTable() {
+			if i+1 >= len(chunks) || chunks[i+1].isTable() {
+				continue
+			}
+			chunks[i+1].Text = normalizeWhitespace(chunks[i].Text + " " + chunks[i+1].Text)
+			chunks[i+1].ImagePaths = append(chunks[i].ImagePaths, chunks[i+1].ImagePaths...)
+			chunks[i+1].chunkPosition = chunks[i].chunkPosition
+			vectors[i+1] = averageVectors([][]float32{vectors[i], vectors[i+1]})
+			chunks = append(chunks[:i], chunks[i+1:]...)
+			vectors = append(vectors[:i], vectors[i+1:]...)
+			i--
 			continue
 		}
 
@@ -248,7 +348,7 @@ func splitOverlongChunks(chunks []pipelineChunk, vectors [][]float32, cfg chunki
 	for i := 0; i < len(chunks); i++ {
 		chunk := chunks[i]
 		vec := vectors[i]
-		if countTextTokens(chunk.Text) <= cfg.MaxChunkTokens {
+		if chunk.isTable() || countTextTokens(chunk.Text) <= cfg.MaxChunkTokens {
 			finalChunks = append(finalChunks, chunk)
 			finalVectors = append(finalVectors, vec)
 			continue
@@ -265,7 +365,7 @@ func splitOverlongChunks(chunks []pipelineChunk, vectors [][]float32, cfg chunki
 		for _, sentence := range sentences {
 			candidate := normalizeWhitespace(strings.TrimSpace(buffer + " " + sentence))
 			if buffer != "" && countTextTokens(candidate) > cfg.MaxChunkTokens {
-				finalChunks = append(finalChunks, pipelineChunk{Text: buffer, ImagePaths: []string{}})
+				finalChunks = append(finalChunks, pipelineChunk{Text: buffer, ImagePaths: []string{}, chunkPosition: chunk.chunkPosition})
 				finalVectors = append(finalVectors, vec)
 				buffer = sentence
 				continue
@@ -273,7 +373,7 @@ func splitOverlongChunks(chunks []pipelineChunk, vectors [][]float32, cfg chunki
 			buffer = candidate
 		}
 		if buffer != "" {
-			finalChunks = append(finalChunks, pipelineChunk{Text: buffer, ImagePaths: []string{}})
+			finalChunks = append(finalChunks, pipelineChunk{Text: buffer, ImagePaths: []string{}, chunkPosition: chunk.chunkPosition})
 			finalVectors = append(finalVectors, vec)
 		}
 
@@ -294,6 +394,9 @@ func applySentenceOverlap(chunks []pipelineChunk, overlapSentences int) []pipeli
 	copy(out, chunks)
 
 	for i := 1; i < len(out); i++ {
+		if out[i].isTable() || out[i-1].isTable() {
+			continue
+		}
 		tail := tailSentences(out[i-1].Text, overlapSentences)
 		if len(tail) == 0 {
 			continue
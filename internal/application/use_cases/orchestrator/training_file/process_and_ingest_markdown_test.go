@@ -0,0 +1,58 @@
+package trainingfile
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseLineBasedChunksTableCaptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     []string
+	}{
+		{
+			name:     "caption above",
+			markdown: "Table 1: Costs\n\n| a | b |\n| --- | --- |\n| 1 | 2 |",
+			want:     []string{"Table 1: Costs"},
+		},
+		{
+			name:     "caption below",
+			markdown: "| a | b |\n| --- | --- |\n| 1 | 2 |\n\nTable 1: Costs",
+			want:     []string{"Table 1: Costs"},
+		},
+		{
+			name:     "caption between tables leads the next one",
+			markdown: "| a | b |\n| --- | --- |\n| 1 | 2 |\n\nTable 2: Revenue\n\n| c | d |\n| --- | --- |\n| 3 | 4 |",
+			want:     []string{"", "Table 2: Revenue"},
+		},
+		{
+			name:     "caption below and caption above the next table",
+			markdown: "| a | b |\n| --- | --- |\n| 1 | 2 |\nTable 1: Costs\nSome text between the tables.\nBảng 2: Doanh thu\n| c | d |\n| --- | --- |\n| 3 | 4 |",
+			want:     []string{"Table 1: Costs", "Bảng 2: Doanh thu"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks, stats := parseLineBasedChunks(tt.markdown, chunkingRuntimeConfig{})
+			if stats.TableCount != len(tt.want) {
+				t.Fatalf("TableCount = %d, want %d", stats.TableCount, len(tt.want))
+			}
+			var captions []string
+			for _, chunk := range chunks {
+				if chunk.UnitType != unitTypeTable {
+					continue
+				}
+				caption := ""
+				if first, _, _ := strings.Cut(chunk.Text, "\n"); !isTableLine(first) {
+					caption = first
+				}
+				captions = append(captions, caption)
+			}
+			if !slices.Equal(captions, tt.want) {
+				t.Fatalf("captions = %q, want %q", captions, tt.want)
+			}
+		})
+	}
+}
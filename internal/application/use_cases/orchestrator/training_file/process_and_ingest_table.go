@@ -0,0 +1,187 @@
+package trainingfile
+
+import (
+	"bytes"
+	"encoding/csv"
+	"regexp"
+	"strings"
+)
+
+const (
+	unitTypeSemanticChunk = "semantic_chunk"
+	unitTypeTable         = "table"
+	unitTypeFigure        = "figure"
+)
+
+var tableSeparatorCellPattern = regexp.MustCompile(`^:?-{3,}:?$`)
+var tableCaptionPattern = regexp.MustCompile(`(?i)^(\*\*)?(table|tab\.|bảng)\s*[0-9ivx]`)
+
+type markdownTable struct {
+	Caption string
+	Header  []string
+	Rows    [][]string
+}
+
+func isTableLine(line string) bool {
+	return strings.HasPrefix(line, "|") && strings.Count(line, "|") >= 2
+}
+
+func isTableSeparatorLine(line string) bool {
+	cells := splitTableRow(line)
+	if len(cells) == 0 {
+		return false
+	}
+	for _, cell := range cells {
+		if !tableSeparatorCellPattern.MatchString(strings.ReplaceAll(cell, " ", "")) {
+			return false
+		}
+	}
+	return true
+}
+
+func isTableCaption(line string) bool {
+	return tableCaptionPattern.MatchString(line)
+}
+
+// splitTableRow splits a pipe table row into trimmed cells; an escaped \|
+// stays inside its cell and <br> tags become spaces.
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = strings.TrimSuffix(line, "|")
+	}
+
+	cells := []string{}
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' && i+1 < len(line) && line[i+1] == '|' {
+			cell.WriteByte('|')
+			i++
+			continue
+		}
+		if line[i] == '|' {
+			cells = append(cells, cleanTableCell(cell.String()))
+			cell.Reset()
+			continue
+		}
+		cell.WriteByte(line[i])
+	}
+	return append(cells, cleanTableCell(cell.String()))
+}
+
+func cleanTableCell(cell string) string {
+	cell = htmlTagPattern.ReplaceAllString(cell, " ")
+	cell = strings.NewReplacer("**", "", "__", "").Replace(cell)
+	return normalizeWhitespace(cell)
+}
+
+// parseMarkdownTable reads the pipe table starting at lines[start]. It returns
+// the table and the index of its last line, or ok=false when the block has no
+// header separator and should be read as plain text.
+func parseMarkdownTable(lines []string, start int) (table markdownTable, end int, ok bool) {
+	end = start
+	for end+1 < len(lines) && isTableLine(strings.TrimSpace(lines[end+1])) {
+		end++
+	}
+	if end-start < 1 || !isTableSeparatorLine(strings.TrimSpace(lines[start+1])) {
+		return markdownTable{}, start, false
+	}
+
+	table.Header = splitTableRow(lines[start])
+	for i := start + 2; i <= end; i++ {
+		row := normalizeTableRow(splitTableRow(lines[i]), len(table.Header))
+		if isEmptyTableRow(row) {
+			continue
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table, end, true
+}
+
+// normalizeTableRow pads or folds a row so it has exactly width cells; extra
+// cells are joined into the last column instead of being dropped.
+func normalizeTableRow(row []string, width int) []string {
+	if width <= 0 {
+		return row
+	}
+	if len(row) > width {
+		folded := append([]string{}, row[:width-1]...)
+		return append(folded, normalizeWhitespace(strings.Join(row[width-1:], " ")))
+	}
+	for len(row) < width {
+		row = append(row, "")
+	}
+	return row
+}
+
+func isEmptyTableRow(row []string) bool {
+	for _, cell := range row {
+		if cell != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// tableChunks renders a table as one chunk per maxRows rows. Every part
+// repeats the caption and the header so it can be retrieved on its own.
+func tableChunks(table markdownTable, position chunkPosition, maxRows int) []pipelineChunk {
+	if maxRows <= 0 || len(table.Rows) <= maxRows {
+		return []pipelineChunk{newTableChunk(table, table.Rows, position)}
+	}
+	out := make([]pipelineChunk, 0, len(table.Rows)/maxRows+1)
+	for start := 0; start < len(table.Rows); start += maxRows {
+		end := min(start+maxRows, len(table.Rows))
+		out = append(out, newTableChunk(table, table.Rows[start:end], position))
+	}
+	return out
+}
+
+func newTableChunk(table markdownTable, rows [][]string, position chunkPosition) pipelineChunk {
+	return pipelineChunk{
+		Text:          renderTableText(table.Caption, table.Header, rows),
+		ImagePaths:    []string{},
+		UnitType:      unitTypeTable,
+		TableCSV:      renderTableCSV(table.Header, rows),
+		chunkPosition: position,
+	}
+}
+
+// renderTableText is the text embedded and indexed for a table: the caption
+// followed by the rows as a normalized pipe table.
+func renderTableText(caption string, header []string, rows [][]string) string {
+	lines := make([]string, 0, len(rows)+3)
+	if caption != "" {
+		lines = append(lines, caption)
+	}
+	lines = append(lines, renderTableRow(header))
+	lines = append(lines, "|"+strings.Repeat(" --- |", len(header)))
+	for _, row := range rows {
+		lines = append(lines, renderTableRow(row))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func renderTableRow(cells []string) string {
+	escaped := make([]string, 0, len(cells))
+	for _, cell := range cells {
+		escaped = append(escaped, strings.ReplaceAll(cell, "|", `\|`))
+	}
+	return "| " + strings.Join(escaped, " | ") + " |"
+}
+
+func renderTableCSV(header []string, rows [][]string) string {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	_ = writer.Write(header)
+	for _, row := range rows {
+		_ = writer.Write(row)
+	}
+	writer.Flush()
+	return strings.TrimRight(buf.String(), "\n")
+}
+
+func (c pipelineChunk) isTable() bool {
+	return c.UnitType == unitTypeTable
+}
@@ -3,6 +3,8 @@ package trainingfile
 import (
 	"regexp"
 	"time"
+
+	"rag_imagetotext_texttoimage/internal/application/dtos"
 )
 
 const (
@@ -12,34 +14,102 @@ const (
 	defaultMinChunkTokens  = 8
 	defaultMaxChunkTokens  = 160
 	defaultChunkOverlap    = 1
+	defaultMaxTableRows    = 20
+)
+
+const (
+	ingestStepDownload       = "download"
+	ingestStepAnalysis       = "analysis"
+	ingestStepUploadMinio    = "upload_minio"
+	ingestStepParseMarkdown  = "parse_markdown"
+	ingestStepEmbedding      = "embedding_semantic"
+	ingestStepEmbeddingImage = "embedding_image_optional"
+	ingestStepFigureCaption  = "figure_caption"
+	ingestStepUploadVectorDB = "upload_vectordb"
+	ingestStepVerifyVectorDB = "verify_vectordb"
 )
 
+// ingestSteps lists the ProcessAndIngest steps in the order they run.
+var ingestSteps = []string{
+	ingestStepDownload,
+	ingestStepAnalysis,
+	ingestStepUploadMinio,
+	ingestStepParseMarkdown,
+	ingestStepEmbedding,
+	ingestStepEmbeddingImage,
+	ingestStepFigureCaption,
+	ingestStepUploadVectorDB,
+	ingestStepVerifyVectorDB,
+}
+
+// ingestProgress receives step transitions from the pipeline. A step that is
+// started but never completed is the one the pipeline stopped in; skipped
+// steps were restored from the manifest of an earlier run.
+type ingestProgress interface {
+	StepStarted(step string)
+	StepCompleted(step string, counts map[string]int)
+	StepSkipped(step string)
+}
+
+type noopIngestProgress struct{}
+
+func (noopIngestProgress) StepStarted(string)                   {}
+func (noopIngestProgress) StepCompleted(string, map[string]int) {}
+func (noopIngestProgress) StepSkipped(string)                   {}
+
 var inlineImagePattern = regexp.MustCompile(`!\[[^\]]*\]\(([^)]+)\)`)
 var sentenceSplitPattern = regexp.MustCompile(`(?m)([^.!?\n]+[.!?]?)(\s+|$)`)
 
+// markerPagePattern matches the separator marker writes before every page
+// with --paginate_output: "{<page_id>}" followed by a row of dashes.
+var markerPagePattern = regexp.MustCompile(`^\{(\d+)\}-{3,}$`)
+var markdownHeadingPattern = regexp.MustCompile(`^(#{1,6})\s+(.+?)(?:\s+#+)?\s*$`)
+var htmlTagPattern = regexp.MustCompile(`<[^>]+>`)
+
 type lineTokenType string
 
 const (
 	lineTokenText  lineTokenType = "text"
 	lineTokenImage lineTokenType = "image"
+	lineTokenTable lineTokenType = "table"
 )
 
 type lineToken struct {
-	Type  lineTokenType
-	Value string
+	Type     lineTokenType
+	Value    string
+	Table    *markdownTable
+	Position chunkPosition
+}
+
+// chunkPosition is where a chunk starts in the document: the 1-based page
+// (0 when the markdown has no page separators) and the enclosing headings,
+// outermost first.
+type chunkPosition struct {
+	Page         int      `json:"page,omitempty"`
+	SectionTitle string   `json:"section_title,omitempty"`
+	HeadingPath  []string `json:"heading_path,omitempty"`
 }
 
+// pipelineChunk is a semantic_chunk unless UnitType says otherwise; table
+// chunks also carry their rows as CSV.
 type pipelineChunk struct {
-	Text       string
-	ImagePaths []string
+	Text       string   `json:"text"`
+	ImagePaths []string `json:"image_paths,omitempty"`
+	UnitType   string   `json:"unit_type,omitempty"`
+	TableCSV   string   `json:"table_csv,omitempty"`
+	chunkPosition
 }
 
+// embeddingBatchResult reads both result schemas: version 1 carries the
+// vectors in Embeddings, version 2 one entry per item in Items.
 type embeddingBatchResult struct {
-	CorrelationID string      `json:"correlation_id"`
-	Status        string      `json:"status"`
-	Message       string      `json:"message"`
-	Dimension     int         `json:"dimension"`
-	Embeddings    [][]float32 `json:"embeddings"`
+	SchemaVersion int                         `json:"schema_version"`
+	CorrelationID string                      `json:"correlation_id"`
+	Status        string                      `json:"status"`
+	Message       string                      `json:"message"`
+	Dimension     int                         `json:"dimension"`
+	Embeddings    [][]float32                 `json:"embeddings"`
+	Items         []dtos.EmbedBatchItemResult `json:"items"`
 }
 
 type chunkingRuntimeConfig struct {
@@ -48,6 +118,7 @@ type chunkingRuntimeConfig struct {
 	MaxChunkTokens        int
 	SemanticSimThreshold  float32
 	ChunkOverlapSentences int
+	MaxTableRows          int
 }
 
 type chunkingStats struct {
@@ -57,4 +128,5 @@ type chunkingStats struct {
 	DroppedNumeric    int
 	DroppedNoise      int
 	MergedShort       int
+	TableCount        int
 }
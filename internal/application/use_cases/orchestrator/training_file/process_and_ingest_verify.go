@@ -7,7 +7,10 @@ import (
 	pb "rag_imagetotext_texttoimage/proto"
 )
 
-func (uc *trainingFileUseCase) verifyVectorDBByDocID(ctx context.Context, collectionName, docID, probeText string) (bool, error) {
+// verifyVectorDBByDocID reports whether the collection holds a point of docID;
+// a non-empty docHash also requires the point to come from that version of
+// the document.
+func (uc *trainingFileUseCase) verifyVectorDBByDocID(ctx context.Context, collectionName, docID, docHash, probeText string) (bool, error) {
 	conn, ragClient, err := uc.newRAGServiceClient(ctx)
 	if err != nil {
 		return false, err
@@ -20,23 +23,32 @@ func (uc *trainingFileUseCase) verifyVectorDBByDocID(ctx context.Context, collec
 		queryText = docID
 	}
 
+	must := []*pb.FieldCondition{
+		{
+			Key:      "doc_id",
+			Operator: "eq",
+			ScalarValue: &pb.FieldCondition_StringValue{
+				StringValue: docID,
+			},
+		},
+	}
+	if docHash != "" {
+		must = append(must, &pb.FieldCondition{
+			Key:      "doc_hash",
+			Operator: "eq",
+			ScalarValue: &pb.FieldCondition_StringValue{
+				StringValue: docHash,
+			},
+		})
+	}
+
 	resp, err := ragClient.SearchPoint(ctx, &pb.SearchPointRequest{
 		CollectionName: collectionName,
 		VectorName:     "bm25",
 		QueryText:      queryText,
 		Limit:          1,
 		WithPayload:    false,
-		Filter: &pb.Filter{
-			Must: []*pb.FieldCondition{
-				{
-					Key:      "doc_id",
-					Operator: "eq",
-					ScalarValue: &pb.FieldCondition_StringValue{
-						StringValue: docID,
-					},
-				},
-			},
-		},
+		Filter:         &pb.Filter{Must: must},
 	})
 	if err != nil {
 		return false, err
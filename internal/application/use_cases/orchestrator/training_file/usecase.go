@@ -8,16 +8,18 @@ import (
 type trainingFileUseCase struct {
 	logger         util.Logger
 	KafkaPublisher ports.KafkaPublisher
-	KafkaConsumer  ports.KafkaConsumer
+	KafkaReplies   ports.KafkaRequestReply
 	Config         util.Config
+	documentParser ports.DocumentParser
 	ragPointWriter ports.RagPointWriter
 }
 
 func NewTrainingFileUseCase(
 	logger util.Logger,
 	publisher ports.KafkaPublisher,
-	consumer ports.KafkaConsumer,
+	replies ports.KafkaRequestReply,
 	Config util.Config,
+	documentParser ports.DocumentParser,
 	ragPointWriter ...ports.RagPointWriter,
 ) ports.TrainingFileUseCase {
 	var writer ports.RagPointWriter
@@ -28,8 +30,9 @@ func NewTrainingFileUseCase(
 	return &trainingFileUseCase{
 		logger:         logger,
 		KafkaPublisher: publisher,
-		KafkaConsumer:  consumer,
+		KafkaReplies:   replies,
 		Config:         Config,
+		documentParser: documentParser,
 		ragPointWriter: writer,
 	}
 }
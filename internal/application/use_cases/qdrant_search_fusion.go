@@ -0,0 +1,171 @@
+package usecases
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"rag_imagetotext_texttoimage/internal/application/ports"
+)
+
+type FusionStrategy string
+
+const (
+	// FusionWeightedMinMax min-max normalizes each vector's scores and sums
+	// them with the vector weights.
+	FusionWeightedMinMax FusionStrategy = "weighted_minmax"
+	// FusionRRF ignores raw scores and sums weight/(k+rank) per vector.
+	FusionRRF FusionStrategy = "rrf"
+	// FusionDBSF normalizes each vector's scores against mean±3σ of that
+	// result list (distribution-based score fusion) before the weighted sum.
+	FusionDBSF FusionStrategy = "dbsf"
+
+	defaultRRFK = 60
+)
+
+type FusionOptions struct {
+	Strategy      FusionStrategy
+	VectorWeights map[string]float32
+	RRFK          float32
+}
+
+type FusionResult struct {
+	Results             []ports.SearchResult
+	Strategy            FusionStrategy
+	NormalizationMode   string
+	VectorNormalization map[string]string
+}
+
+func ParseFusionStrategy(raw string) (FusionStrategy, error) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "", string(FusionWeightedMinMax), "minmax":
+		return FusionWeightedMinMax, nil
+	case string(FusionRRF):
+		return FusionRRF, nil
+	case string(FusionDBSF):
+		return FusionDBSF, nil
+	default:
+		return "", fmt.Errorf("unsupported fusion strategy %q", raw)
+	}
+}
+
+func (f FusionStrategy) normalizationMode() string {
+	switch f {
+	case FusionRRF:
+		return "rank"
+	case FusionDBSF:
+		return "dbsf"
+	default:
+		return "minmax"
+	}
+}
+
+func (o FusionOptions) weightFor(vectorName string) float32 {
+	key := strings.ToLower(strings.TrimSpace(vectorName))
+	if w, ok := o.VectorWeights[key]; ok && w >= 0 {
+		return w
+	}
+	return defaultWeightForVector(vectorName)
+}
+
+func (o FusionOptions) rrfK() float32 {
+	if o.RRFK > 0 {
+		return o.RRFK
+	}
+	return defaultRRFK
+}
+
+// normalizeForFusion rewrites the scores in place for score-based strategies
+// and returns the per-vector mode. RRF only needs the ranking.
+func normalizeForFusion(strategy FusionStrategy, results []ports.SearchResult) string {
+	switch strategy {
+	case FusionRRF:
+		sort.SliceStable(results, func(i, j int) bool {
+			return results[i].Score > results[j].Score
+		})
+		if len(results) == 0 {
+			return "empty"
+		}
+		return "rank"
+	case FusionDBSF:
+		return normDistribution(results)
+	default:
+		return normFullScore(&results)
+	}
+}
+
+func normDistribution(results []ports.SearchResult) string {
+	if len(results) == 0 {
+		return "empty"
+	}
+	if len(results) < 2 {
+		return "raw_single"
+	}
+
+	var mean float64
+	for _, r := range results {
+		mean += float64(r.Score)
+	}
+	mean /= float64(len(results))
+	var variance float64
+	for _, r := range results {
+		d := float64(r.Score) - mean
+		variance += d * d
+	}
+	std := math.Sqrt(variance / float64(len(results)))
+	if std <= normEpsilon {
+		return "raw_flat"
+	}
+
+	lower := mean - 3*std
+	upper := mean + 3*std
+	for idx := range results {
+		v := (float64(results[idx].Score) - lower) / (upper - lower)
+		results[idx].Score = float32(math.Max(0, math.Min(1, v)))
+	}
+	return "dbsf"
+}
+
+func mergeRRF(group groupResult, k float32) []ports.SearchResult {
+	if len(group.items) == 0 {
+		return nil
+	}
+	if len(group.weights) != len(group.items) {
+		group.weights = make([]float32, len(group.items))
+		for i := range group.weights {
+			group.weights[i] = 1.0
+		}
+	}
+	normalizeWeights(group.weights)
+
+	merged := make(map[string]ports.SearchResult)
+	for idx, results := range group.items {
+		weight := group.weights[idx]
+		rank := 0
+		for _, item := range results {
+			if item.Point == nil {
+				continue
+			}
+			rank++
+			contribution := weight / (k + float32(rank))
+			existing, ok := merged[item.Point.ID]
+			if !ok {
+				item.Score = contribution
+				merged[item.Point.ID] = item
+				continue
+			}
+			existing.Score += contribution
+			merged[item.Point.ID] = existing
+		}
+	}
+
+	finalResult := make([]ports.SearchResult, 0, len(merged))
+	for _, item := range merged {
+		finalResult = append(finalResult, item)
+	}
+	sort.Slice(finalResult, func(i, j int) bool {
+		return finalResult[i].Score > finalResult[j].Score
+	})
+	return finalResult
+}
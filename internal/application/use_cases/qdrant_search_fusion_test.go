@@ -0,0 +1,139 @@
+package usecases
+
+import (
+	"math"
+	"testing"
+
+	"rag_imagetotext_texttoimage/internal/application/ports"
+	domain "rag_imagetotext_texttoimage/internal/domain/entity_objects"
+)
+
+func hits(ids ...string) []ports.SearchResult {
+	out := make([]ports.SearchResult, 0, len(ids))
+	for _, id := range ids {
+		if id == "" {
+			out = append(out, ports.SearchResult{})
+			continue
+		}
+		out = append(out, ports.SearchResult{Point: &domain.PointObject{ID: id}})
+	}
+	return out
+}
+
+func scored(scores ...float32) []ports.SearchResult {
+	out := make([]ports.SearchResult, 0, len(scores))
+	for _, score := range scores {
+		out = append(out, ports.SearchResult{Score: score})
+	}
+	return out
+}
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func TestMergeRRF(t *testing.T) {
+	tests := []struct {
+		name    string
+		items   [][]ports.SearchResult
+		weights []float32
+		k       float32
+		want    map[string]float64
+		order   []string
+	}{
+		{
+			name:  "single result",
+			items: [][]ports.SearchResult{hits("p1")},
+			k:     60,
+			want:  map[string]float64{"p1": 1.0 / 61},
+			order: []string{"p1"},
+		},
+		{
+			name:    "equal weights accumulate over vectors",
+			items:   [][]ports.SearchResult{hits("p1", "p2"), hits("p2", "p3")},
+			weights: []float32{1, 1},
+			k:       60,
+			want:    map[string]float64{"p1": 0.5 / 61, "p2": 0.5/62 + 0.5/61, "p3": 0.5 / 62},
+			order:   []string{"p2", "p1", "p3"},
+		},
+		{
+			name:    "weights are normalized",
+			items:   [][]ports.SearchResult{hits("p1", "p2"), hits("p2", "p3")},
+			weights: []float32{3, 1},
+			k:       10,
+			want:    map[string]float64{"p1": 0.75 / 11, "p2": 0.75/12 + 0.25/11, "p3": 0.25 / 12},
+			order:   []string{"p2", "p1", "p3"},
+		},
+		{
+			name:  "first everywhere scores 1/(k+1)",
+			items: [][]ports.SearchResult{hits("p1", "p2"), hits("p1", "p3"), hits("p1")},
+			k:     60,
+			want:  map[string]float64{"p1": 1.0 / 61, "p2": 1.0 / 3 / 62, "p3": 1.0 / 3 / 62},
+		},
+		{
+			name:  "points without payload take no rank",
+			items: [][]ports.SearchResult{hits("", "p1")},
+			k:     60,
+			want:  map[string]float64{"p1": 1.0 / 61},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := mergeRRF(groupResult{items: tt.items, weights: tt.weights}, tt.k)
+			if len(merged) != len(tt.want) {
+				t.Fatalf("got %d results, want %d", len(merged), len(tt.want))
+			}
+			for i, r := range merged {
+				if want := tt.want[r.Point.ID]; !approxEqual(float64(r.Score), want) {
+					t.Fatalf("%s score = %v, want %v", r.Point.ID, r.Score, want)
+				}
+				if tt.order != nil && r.Point.ID != tt.order[i] {
+					t.Fatalf("rank %d = %s, want %s", i, r.Point.ID, tt.order[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNormDistribution(t *testing.T) {
+	// Fifteen zeros and one outlier: the outlier sits sqrt(15) σ away from
+	// the mean, past the ±3σ window, and is clamped.
+	outlierHigh := append(scored(1), scored(make([]float32, 15)...)...)
+	outlierLow := append(scored(-1), scored(make([]float32, 15)...)...)
+	zeroHigh := float32((3 - 1/math.Sqrt(15)) / 6)
+	zeroLow := float32((3 + 1/math.Sqrt(15)) / 6)
+
+	tests := []struct {
+		name    string
+		results []ports.SearchResult
+		mode    string
+		want    []float32
+	}{
+		{name: "empty", results: nil, mode: "empty", want: nil},
+		{name: "single result keeps its raw score", results: scored(0.82), mode: "raw_single", want: []float32{0.82}},
+		{name: "flat scores stay raw", results: scored(0.7, 0.7, 0.7), mode: "raw_flat", want: []float32{0.7, 0.7, 0.7}},
+		{name: "mean maps to 0.5, ±3σ to 0 and 1", results: scored(1, 0), mode: "dbsf", want: []float32{2.0 / 3, 1.0 / 3}},
+		{name: "clamped above", results: outlierHigh, mode: "dbsf", want: append([]float32{1}, repeat(zeroHigh, 15)...)},
+		{name: "clamped below", results: outlierLow, mode: "dbsf", want: append([]float32{0}, repeat(zeroLow, 15)...)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if mode := normDistribution(tt.results); mode != tt.mode {
+				t.Fatalf("mode = %s, want %s", mode, tt.mode)
+			}
+			for i, r := range tt.results {
+				if !approxEqual(float64(r.Score), float64(tt.want[i])) {
+					t.Fatalf("score[%d] = %v, want %v", i, r.Score, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func repeat(v float32, n int) []float32 {
+	out := make([]float32, n)
+	for i := range out {
+		out[i] = v
+	}
+	return out
+}
@@ -30,11 +30,25 @@ type groupResult struct {
 	weights []float32
 }
 
+// Search fuses the queries with the default weighted min-max strategy.
 func (s *SearchWithVectorDB) Search(ctx context.Context, query ...ports.SearchQuery) ([]ports.SearchResult, error) {
+	fused, err := s.SearchWithFusion(ctx, FusionOptions{}, query...)
+	if err != nil {
+		return nil, err
+	}
+	return fused.Results, nil
+}
+
+func (s *SearchWithVectorDB) SearchWithFusion(ctx context.Context, opts FusionOptions, query ...ports.SearchQuery) (FusionResult, error) {
 	if len(query) < 1 {
 		err := errors.New("no query provided")
 		s.appLogger.Error("search failed", err)
-		return nil, err
+		return FusionResult{}, err
+	}
+	strategy, err := ParseFusionStrategy(string(opts.Strategy))
+	if err != nil {
+		s.appLogger.Error("search failed", err)
+		return FusionResult{}, err
 	}
 
 	queries := s.expandQueries(query)
@@ -42,19 +56,32 @@ func (s *SearchWithVectorDB) Search(ctx context.Context, query ...ports.SearchQu
 		items:   make([][]ports.SearchResult, 0, len(queries)),
 		weights: make([]float32, 0, len(queries)),
 	}
+	vectorNormalization := make(map[string]string, len(queries))
 
 	for _, q := range queries {
-		results, err := s.searchAndNorm(ctx, q)
+		results, err := s.searchOnlyQuery(ctx, q)
 		if err != nil {
-			return nil, err
+			return FusionResult{}, err
 		}
+		mode := normalizeForFusion(strategy, results)
+		vectorNormalization[q.VectorName] = mode
+		s.appLogger.Info(
+			"search normalization mode",
+			"vector_name", q.VectorName,
+			"fusion_strategy", string(strategy),
+			"result_count", len(results),
+			"normalization_mode", mode,
+			"top_score_after_norm", topScore(results),
+		)
 		group.items = append(group.items, results)
-		group.weights = append(group.weights, defaultWeightForVector(q.VectorName))
+		group.weights = append(group.weights, opts.weightFor(q.VectorName))
 	}
 
-	merged := mergeWeighted(group)
-	if len(merged) == 0 {
-		return merged, nil
+	var merged []ports.SearchResult
+	if strategy == FusionRRF {
+		merged = mergeRRF(group, opts.rrfK())
+	} else {
+		merged = mergeWeighted(group)
 	}
 
 	limit := query[0].Limit
@@ -62,7 +89,12 @@ func (s *SearchWithVectorDB) Search(ctx context.Context, query ...ports.SearchQu
 		merged = merged[:limit]
 	}
 
-	return merged, nil
+	return FusionResult{
+		Results:             merged,
+		Strategy:            strategy,
+		NormalizationMode:   strategy.normalizationMode(),
+		VectorNormalization: vectorNormalization,
+	}, nil
 }
 
 func (s *SearchWithVectorDB) expandQueries(query []ports.SearchQuery) []ports.SearchQuery {
@@ -169,22 +201,6 @@ func normalizeWeights(weights []float32) {
 	}
 }
 
-func (s *SearchWithVectorDB) searchAndNorm(ctx context.Context, query ports.SearchQuery) ([]ports.SearchResult, error) {
-	resultSearch, err := s.searchOnlyQuery(ctx, query)
-	if err != nil {
-		return nil, err
-	}
-	normalizationMode := normFullScore(&resultSearch)
-	s.appLogger.Info(
-		"search normalization mode",
-		"vector_name", query.VectorName,
-		"result_count", len(resultSearch),
-		"normalization_mode", normalizationMode,
-		"top_score_after_norm", topScore(resultSearch),
-	)
-	return resultSearch, nil
-}
-
 func normFullScore(resultSearch *[]ports.SearchResult) string {
 	if resultSearch == nil || len(*resultSearch) == 0 {
 		return "empty"
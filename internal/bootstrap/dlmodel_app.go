@@ -3,6 +3,7 @@ package bootstrap
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -14,10 +15,10 @@ import (
 
 	"google.golang.org/grpc"
 
+	grpcAdapter "rag_imagetotext_texttoimage/internal/adapter/grpc"
 	kafkaAdapter "rag_imagetotext_texttoimage/internal/adapter/kafka"
 	"rag_imagetotext_texttoimage/internal/application/dtos"
 	"rag_imagetotext_texttoimage/internal/application/ports"
-	"rag_imagetotext_texttoimage/internal/infra/cgo"
 	"rag_imagetotext_texttoimage/internal/infra/monitoring"
 	"rag_imagetotext_texttoimage/internal/util"
 )
@@ -25,8 +26,8 @@ import (
 type DLModelApp struct {
 	cfg          *util.Config
 	logger       util.Logger
-	jinaConfig   *dlmodelJinaRuntimeConfig
-	jina         *cgo.JinaAdapter
+	inference    *dlmodelInference
+	batcher      *grpcAdapter.EmbeddingBatcher
 	producer     *kafkaAdapter.ProducerAdapter
 	consumer     *kafkaAdapter.ConsumerAdapter
 	topics       util.EmbeddingTopics
@@ -47,8 +48,8 @@ func NewDLModelApp() (*DLModelApp, error) {
 
 	configKey := registry.Key("config")
 	loggerKey := registry.Key("logger")
-	jinaRuntimeKey := registry.Key("jina.runtime_config")
-	jinaAdapterKey := registry.Key("jina.adapter")
+	inferenceKey := registry.Key("inference")
+	batcherKey := registry.Key("grpc.batcher")
 	topicsKey := registry.Key("topics")
 	kafkaInfraKey := registry.Key("kafka.infra")
 	grpcServerKey := registry.Key("grpc.server")
@@ -58,8 +59,8 @@ func NewDLModelApp() (*DLModelApp, error) {
 	if err := registerDLModelBindings(container, dlmodelBindingKeys{
 		ConfigKey:      configKey,
 		LoggerKey:      loggerKey,
-		JinaRuntimeKey: jinaRuntimeKey,
-		JinaAdapterKey: jinaAdapterKey,
+		InferenceKey:   inferenceKey,
+		BatcherKey:     batcherKey,
 		TopicsKey:      topicsKey,
 		KafkaInfraKey:  kafkaInfraKey,
 		GRPCServerKey:  grpcServerKey,
@@ -77,37 +78,36 @@ func NewDLModelApp() (*DLModelApp, error) {
 	if err != nil {
 		return nil, err
 	}
-	jinaCfg, err := ResolveAs[*dlmodelJinaRuntimeConfig](container, jinaRuntimeKey)
+	inference, err := ResolveAs[*dlmodelInference](container, inferenceKey)
 	if err != nil {
 		logger.Close()
 		return nil, err
 	}
-	jina, err := ResolveAs[*cgo.JinaAdapter](container, jinaAdapterKey)
+	topics, err := ResolveAs[util.EmbeddingTopics](container, topicsKey)
 	if err != nil {
-		jinaCfg.cleanup()
+		inference.Close()
 		logger.Close()
 		return nil, err
 	}
-	topics, err := ResolveAs[util.EmbeddingTopics](container, topicsKey)
+	kafkaInfra, err := ResolveAs[dlmodelKafkaInfra](container, kafkaInfraKey)
 	if err != nil {
-		jina.Close()
-		jinaCfg.cleanup()
+		inference.Close()
 		logger.Close()
 		return nil, err
 	}
-	kafkaInfra, err := ResolveAs[dlmodelKafkaInfra](container, kafkaInfraKey)
+	grpcServer, err := ResolveAs[*grpc.Server](container, grpcServerKey)
 	if err != nil {
-		jina.Close()
-		jinaCfg.cleanup()
+		_ = kafkaInfra.producer.Close()
+		_ = kafkaInfra.consumer.Close()
+		inference.Close()
 		logger.Close()
 		return nil, err
 	}
-	grpcServer, err := ResolveAs[*grpc.Server](container, grpcServerKey)
+	batcher, err := ResolveAs[*grpcAdapter.EmbeddingBatcher](container, batcherKey)
 	if err != nil {
 		_ = kafkaInfra.producer.Close()
 		_ = kafkaInfra.consumer.Close()
-		jina.Close()
-		jinaCfg.cleanup()
+		inference.Close()
 		logger.Close()
 		return nil, err
 	}
@@ -115,8 +115,8 @@ func NewDLModelApp() (*DLModelApp, error) {
 	if err != nil {
 		_ = kafkaInfra.producer.Close()
 		_ = kafkaInfra.consumer.Close()
-		jina.Close()
-		jinaCfg.cleanup()
+		batcher.Close()
+		inference.Close()
 		logger.Close()
 		return nil, err
 	}
@@ -124,19 +124,19 @@ func NewDLModelApp() (*DLModelApp, error) {
 	if err != nil {
 		_ = kafkaInfra.producer.Close()
 		_ = kafkaInfra.consumer.Close()
-		jina.Close()
-		jinaCfg.cleanup()
+		batcher.Close()
+		inference.Close()
 		logger.Close()
 		return nil, err
 	}
 
-	logger.Info("dlmodel service bootstrap started", "grpc_port", cfg.EmbeddingService.Port, "log_path", ProjectPath("logs", "dlmodel_service.log"), "jina_config_path", cfg.EmbeddingService.JinaConfigPath)
+	logger.Info("dlmodel service bootstrap started", "grpc_port", cfg.EmbeddingService.Port, "log_path", ProjectPath("logs", "dlmodel_service.log"), "embedding_backend", inference.backend)
 
 	return &DLModelApp{
 		cfg:          cfg,
 		logger:       logger,
-		jinaConfig:   jinaCfg,
-		jina:         jina,
+		inference:    inference,
+		batcher:      batcher,
 		producer:     kafkaInfra.producer,
 		consumer:     kafkaInfra.consumer,
 		topics:       topics,
@@ -153,8 +153,9 @@ func (a *DLModelApp) Run() error {
 	}
 
 	rootCtx, cancel := context.WithCancel(context.Background())
-	batchTextErrCh := a.consumer.Start(rootCtx, a.topics.BatchTextRequest, a.topics.BatchTextGroup, a.handleBatchText)
-	batchImageErrCh := a.consumer.Start(rootCtx, a.topics.BatchImageRequest, a.topics.BatchImageGroup, a.handleBatchImage)
+	retry := kafkaAdapter.NewRetryMiddleware(a.producer, kafkaAdapter.RetryPolicyFromConfig(a.cfg.Kafka.Retry), a.metricsKafka, a.logger)
+	batchTextErrCh := a.consumer.StartWithRetry(rootCtx, a.topics.BatchTextRequest, a.topics.BatchTextGroup, "embed_batch_text", a.handleBatchText, retry)
+	batchImageErrCh := a.consumer.StartWithRetry(rootCtx, a.topics.BatchImageRequest, a.topics.BatchImageGroup, "embed_batch_image", a.handleBatchImage, retry)
 
 	grpcErrCh := make(chan error, 1)
 	go func() {
@@ -224,12 +225,8 @@ func (a *DLModelApp) Close() {
 	if a.consumer != nil {
 		_ = a.consumer.Close()
 	}
-	if a.jina != nil {
-		a.jina.Close()
-	}
-	if a.jinaConfig != nil && a.jinaConfig.cleanup != nil {
-		a.jinaConfig.cleanup()
-	}
+	a.batcher.Close()
+	a.inference.Close()
 	if a.logger != nil {
 		a.logger.Close()
 	}
@@ -263,40 +260,39 @@ func (a *DLModelApp) handleBatchText(ctx context.Context, msg ports.ConsumeMessa
 	if err := json.Unmarshal(msg.Message.Value, &request); err != nil {
 		telemetry.observe("invalid_payload")
 		a.logger.Error("internal.bootstrap.DLModelApp.handleBatchText invalid batch text payload", err, "topic", msg.Topic, "offset", msg.Offset)
-		return nil
+		if publishErr := a.publishEmbedResult(ctx, a.topics.BatchTextResult, a.topics.BatchTextRequest, msg, failedEmbedBatchResult(msg, "invalid batch text payload: "+err.Error())); publishErr != nil {
+			a.logger.Error("publish batch text result failed", publishErr, "result_topic", a.topics.BatchTextResult)
+		}
+		return ports.Permanent(fmt.Errorf("decode batch text payload: %w", err))
 	}
 
-	embeddings, runErr := a.jina.EmbedBatchText(request.Texts)
-	status := "success"
-	message := "batch text embedding completed"
-	dimension := 0
-	if len(embeddings) > 0 {
-		dimension = len(embeddings[0])
+	items, mode, batchErr := embedItems(len(request.Texts), func() ([][]float32, error) {
+		return a.inference.infer.EmbedBatchText(request.Texts)
+	}, func(index int) ([]float32, error) {
+		return a.inference.infer.EmbedText(request.Texts[index])
+	})
+	if batchErr != nil {
+		a.logger.Error("batch text embedding failed", batchErr, "topic", msg.Topic, "offset", msg.Offset, "mode", mode)
 	}
-	if runErr != nil {
-		status = "failed"
-		message = runErr.Error()
+	result := newEmbedBatchResult(msg, request.CorrelationID, items, mode)
+	if result.Status == dtos.EmbeddingStatusFailed {
 		telemetry.observe("failed")
-		a.logger.Error("batch text embedding failed", runErr, "topic", msg.Topic, "offset", msg.Offset)
-	}
-	if a.topics.BatchTextResult != "" {
-		publishErr := a.producer.PublishJSON(ctx, a.topics.BatchTextResult, msg.Message.Key, map[string]any{
-			"status":     status,
-			"message":    message,
-			"count":      len(request.Texts),
-			"dimension":  dimension,
-			"embeddings": embeddings,
-			"at":         time.Now().UTC().Format(time.RFC3339),
-		}, map[string]string{"source_topic": a.topics.BatchTextRequest})
-		if publishErr != nil {
-			telemetry.retry("publish_error")
-			a.logger.Error("publish batch text result failed", publishErr, "result_topic", a.topics.BatchTextResult)
+		if !kafkaAdapter.FinalAttempt(ctx) {
+			return fmt.Errorf("embed batch text: %s", result.Message)
 		}
 	}
-	if status == "success" {
-		telemetry.observe("success")
+	if publishErr := a.publishEmbedResult(ctx, a.topics.BatchTextResult, a.topics.BatchTextRequest, msg, result); publishErr != nil {
+		telemetry.retry("publish_error")
+		a.logger.Error("publish batch text result failed", publishErr, "result_topic", a.topics.BatchTextResult)
+		return fmt.Errorf("publish batch text result: %w", publishErr)
+	}
+	if result.Status != dtos.EmbeddingStatusFailed {
+		telemetry.observe(result.Status)
+	}
+	a.logger.Info("batch text pipeline completed", "topic", msg.Topic, "correlation_id", result.CorrelationID, "status", result.Status, "mode", mode, "count", result.Count, "failed", result.Failed, "dimension", result.Dimension, "latency_ms", time.Since(telemetry.startedAt).Milliseconds())
+	if result.Status == dtos.EmbeddingStatusFailed {
+		return errors.New(result.Message)
 	}
-	a.logger.Info("batch text pipeline completed", "topic", msg.Topic, "status", status, "count", len(request.Texts), "dimension", dimension, "latency_ms", time.Since(telemetry.startedAt).Milliseconds())
 	return nil
 }
 
@@ -311,38 +307,38 @@ func (a *DLModelApp) handleBatchImage(ctx context.Context, msg ports.ConsumeMess
 	if err := json.Unmarshal(msg.Message.Value, &request); err != nil {
 		telemetry.observe("invalid_payload")
 		a.logger.Error("invalid batch image payload", err, "topic", msg.Topic, "offset", msg.Offset)
-		return nil
+		if publishErr := a.publishEmbedResult(ctx, a.topics.BatchImageResult, a.topics.BatchImageRequest, msg, failedEmbedBatchResult(msg, "invalid batch image payload: "+err.Error())); publishErr != nil {
+			a.logger.Error("publish batch image result failed", publishErr, "result_topic", a.topics.BatchImageResult)
+		}
+		return ports.Permanent(fmt.Errorf("decode batch image payload: %w", err))
 	}
-	embeddings, runErr := a.jina.EmbedBatchImage(request.Images, request.Width, request.Height, request.Channels)
-	status := "success"
-	message := "batch image embedding completed"
-	dimension := 0
-	if len(embeddings) > 0 {
-		dimension = len(embeddings[0])
+
+	items, mode, batchErr := embedItems(len(request.Images), func() ([][]float32, error) {
+		return a.inference.infer.EmbedBatchImage(request.Images, request.Width, request.Height, request.Channels)
+	}, func(index int) ([]float32, error) {
+		return a.inference.infer.EmbedImage(request.Images[index], request.Width, request.Height, request.Channels)
+	})
+	if batchErr != nil {
+		a.logger.Error("batch image embedding failed", batchErr, "topic", msg.Topic, "offset", msg.Offset, "mode", mode)
 	}
-	if runErr != nil {
-		status = "failed"
-		message = runErr.Error()
+	result := newEmbedBatchResult(msg, request.CorrelationID, items, mode)
+	if result.Status == dtos.EmbeddingStatusFailed {
 		telemetry.observe("failed")
-		a.logger.Error("batch image embedding failed", runErr, "topic", msg.Topic, "offset", msg.Offset)
-	}
-	if a.topics.BatchImageResult != "" {
-		publishErr := a.producer.PublishJSON(ctx, a.topics.BatchImageResult, msg.Message.Key, map[string]any{
-			"status":     status,
-			"message":    message,
-			"count":      len(request.Images),
-			"dimension":  dimension,
-			"embeddings": embeddings,
-			"at":         time.Now().UTC().Format(time.RFC3339),
-		}, map[string]string{"source_topic": a.topics.BatchImageRequest})
-		if publishErr != nil {
-			telemetry.retry("publish_error")
-			a.logger.Error("publish batch image result failed", publishErr, "result_topic", a.topics.BatchImageResult)
+		if !kafkaAdapter.FinalAttempt(ctx) {
+			return fmt.Errorf("embed batch image: %s", result.Message)
 		}
 	}
-	if status == "success" {
-		telemetry.observe("success")
+	if publishErr := a.publishEmbedResult(ctx, a.topics.BatchImageResult, a.topics.BatchImageRequest, msg, result); publishErr != nil {
+		telemetry.retry("publish_error")
+		a.logger.Error("publish batch image result failed", publishErr, "result_topic", a.topics.BatchImageResult)
+		return fmt.Errorf("publish batch image result: %w", publishErr)
+	}
+	if result.Status != dtos.EmbeddingStatusFailed {
+		telemetry.observe(result.Status)
+	}
+	a.logger.Info("batch image pipeline completed", "topic", msg.Topic, "correlation_id", result.CorrelationID, "status", result.Status, "mode", mode, "count", result.Count, "failed", result.Failed, "dimension", result.Dimension, "latency_ms", time.Since(telemetry.startedAt).Milliseconds())
+	if result.Status == dtos.EmbeddingStatusFailed {
+		return errors.New(result.Message)
 	}
-	a.logger.Info("batch image pipeline completed", "topic", msg.Topic, "status", status, "count", len(request.Images), "dimension", dimension, "latency_ms", time.Since(telemetry.startedAt).Milliseconds())
 	return nil
 }
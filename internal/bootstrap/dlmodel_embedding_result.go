@@ -0,0 +1,142 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"rag_imagetotext_texttoimage/internal/application/dtos"
+	"rag_imagetotext_texttoimage/internal/application/ports"
+)
+
+// embedItems runs the batch call and, when it fails or returns the wrong
+// number of vectors, embeds every item on its own so one corrupt text or
+// image fails only itself. The batch error is returned for logging.
+func embedItems(count int, batch func() ([][]float32, error), single func(index int) ([]float32, error)) ([]dtos.EmbedBatchItemResult, string, error) {
+	items := make([]dtos.EmbedBatchItemResult, count)
+	for i := range items {
+		items[i].Index = i
+	}
+	if count == 0 {
+		return items, dtos.EmbeddingModeBatch, nil
+	}
+
+	embeddings, batchErr := batch()
+	if batchErr == nil && len(embeddings) != count {
+		batchErr = fmt.Errorf("batch returned %d embeddings for %d items", len(embeddings), count)
+	}
+	if batchErr == nil {
+		for i, embedding := range embeddings {
+			items[i].Embedding = embedding
+		}
+		return items, dtos.EmbeddingModeBatch, nil
+	}
+	if count == 1 {
+		items[0].Error = batchErr.Error()
+		return items, dtos.EmbeddingModeBatch, batchErr
+	}
+
+	for i := range items {
+		embedding, err := single(i)
+		switch {
+		case err != nil:
+			items[i].Error = err.Error()
+		case len(embedding) == 0:
+			items[i].Error = "empty embedding"
+		default:
+			items[i].Embedding = embedding
+		}
+	}
+	return items, dtos.EmbeddingModePerItem, batchErr
+}
+
+// newEmbedBatchResult builds the reply to msg. The correlation id comes from
+// the request body, then its headers, then its key.
+func newEmbedBatchResult(msg ports.ConsumeMessage, bodyCorrelationID string, items []dtos.EmbedBatchItemResult, mode string) dtos.EmbedBatchResult {
+	result := dtos.EmbedBatchResult{
+		SchemaVersion:  dtos.EmbeddingResultSchemaVersion,
+		CorrelationID:  requestCorrelationID(msg, bodyCorrelationID),
+		RequestedBy:    strings.TrimSpace(msg.Message.Headers["source"]),
+		RequestHeaders: requestHeaders(msg.Message.Headers),
+		Mode:           mode,
+		Count:          len(items),
+		Items:          items,
+		At:             time.Now().UTC().Format(time.RFC3339),
+	}
+	if result.Items == nil {
+		result.Items = []dtos.EmbedBatchItemResult{}
+	}
+	for _, item := range items {
+		if item.Error != "" {
+			result.Failed++
+			continue
+		}
+		if result.Dimension == 0 {
+			result.Dimension = len(item.Embedding)
+		}
+	}
+
+	switch {
+	case len(items) == 0:
+		result.Status = dtos.EmbeddingStatusFailed
+		result.Message = "no items to embed"
+	case result.Failed == len(items):
+		result.Status = dtos.EmbeddingStatusFailed
+		result.Message = "all items failed: " + items[0].Error
+	case result.Failed > 0:
+		result.Status = dtos.EmbeddingStatusPartial
+		result.Message = fmt.Sprintf("%d of %d items failed", result.Failed, len(items))
+	default:
+		result.Status = dtos.EmbeddingStatusSuccess
+		result.Message = "embedding completed"
+	}
+	return result
+}
+
+func failedEmbedBatchResult(msg ports.ConsumeMessage, message string) dtos.EmbedBatchResult {
+	result := newEmbedBatchResult(msg, "", nil, "")
+	result.Message = message
+	return result
+}
+
+func requestCorrelationID(msg ports.ConsumeMessage, bodyCorrelationID string) string {
+	if cid := strings.TrimSpace(bodyCorrelationID); cid != "" {
+		return cid
+	}
+	if cid := strings.TrimSpace(msg.Message.Headers["correlation_id"]); cid != "" {
+		return cid
+	}
+	return strings.TrimSpace(string(msg.Message.Key))
+}
+
+// requestHeaders echoes the caller's headers; the x- headers added by the
+// retry middleware are left out.
+func requestHeaders(headers map[string]string) map[string]string {
+	out := make(map[string]string, len(headers))
+	for k, v := range headers {
+		if strings.HasPrefix(k, "x-") {
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// publishEmbedResult sends result to resultTopic keyed by the request key, so
+// callers that match on the key keep working.
+func (a *DLModelApp) publishEmbedResult(ctx context.Context, resultTopic string, sourceTopic string, msg ports.ConsumeMessage, result dtos.EmbedBatchResult) error {
+	if resultTopic == "" {
+		return nil
+	}
+	key := msg.Message.Key
+	if len(key) == 0 {
+		key = []byte(result.CorrelationID)
+	}
+	return a.producer.PublishJSON(ctx, resultTopic, key, result, map[string]string{
+		"source_topic":   sourceTopic,
+		"correlation_id": result.CorrelationID,
+		"schema_version": strconv.Itoa(result.SchemaVersion),
+	})
+}
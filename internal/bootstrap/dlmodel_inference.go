@@ -0,0 +1,99 @@
+package bootstrap
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"rag_imagetotext_texttoimage/internal/application/ports"
+	"rag_imagetotext_texttoimage/internal/infra/embedding"
+	"rag_imagetotext_texttoimage/internal/infra/monitoring"
+	"rag_imagetotext_texttoimage/internal/util"
+)
+
+// dlmodelInference is the embedding backend chosen by
+// embedding_service.backend. reranker is nil when the backend has none.
+type dlmodelInference struct {
+	backend  string
+	infer    ports.Inference
+	reranker ports.Reranker
+	cleanup  func()
+}
+
+func (d *dlmodelInference) Close() {
+	if d == nil {
+		return
+	}
+	if d.infer != nil {
+		d.infer.Close()
+	}
+	if d.cleanup != nil {
+		d.cleanup()
+	}
+}
+
+// newDLModelInference creates the configured backend and, when the cache is
+// enabled, puts the embedding cache in front of it so the gRPC and the Kafka
+// paths share it.
+func newDLModelInference(cfg util.EmbeddingSettings, logger util.Logger) (*dlmodelInference, error) {
+	inference, err := newDLModelBackend(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.Cache.Enabled {
+		return inference, nil
+	}
+	modelVersion := strings.TrimSpace(cfg.Cache.ModelVersion)
+	if modelVersion == "" {
+		modelVersion = defaultEmbeddingModelVersion(cfg, inference.backend)
+	}
+	cached, err := embedding.NewCachedInference(inference.infer, embedding.EmbeddingCacheOptions{
+		ModelVersion: modelVersion,
+		MaxEntries:   cfg.Cache.MaxEntries,
+		DiskPath:     strings.TrimSpace(cfg.Cache.DiskPath),
+	}, monitoring.NewEmbeddingCacheMetrics(), logger)
+	if err != nil {
+		inference.Close()
+		return nil, fmt.Errorf("create embedding cache: %w", err)
+	}
+	inference.infer = cached
+	return inference, nil
+}
+
+// defaultEmbeddingModelVersion names the model from the backend settings. It
+// does not see the model files, so set cache.model_version when they change
+// in place.
+func defaultEmbeddingModelVersion(cfg util.EmbeddingSettings, backend string) string {
+	switch backend {
+	case "openai", "tei":
+		return backend + ":" + strings.TrimSpace(cfg.HTTP.BaseURL) + "/" + strings.TrimSpace(cfg.HTTP.Model)
+	case "hashing":
+		return backend + ":" + strconv.Itoa(embedding.NewHashingEmbedder(cfg.Hashing.Dimension).Dimension())
+	default:
+		return backend + ":" + strings.TrimSpace(cfg.JinaConfigPath)
+	}
+}
+
+func newDLModelBackend(cfg util.EmbeddingSettings, logger util.Logger) (*dlmodelInference, error) {
+	backend := strings.ToLower(strings.TrimSpace(cfg.Backend))
+	switch backend {
+	case "", "jina":
+		return newJinaInference(cfg, logger)
+	case "openai":
+		embedder, err := embedding.NewOpenAIEmbedder(cfg.HTTP, logger)
+		if err != nil {
+			return nil, fmt.Errorf("create openai embedding backend: %w", err)
+		}
+		return &dlmodelInference{backend: backend, infer: embedder}, nil
+	case "tei":
+		embedder, err := embedding.NewTEIEmbedder(cfg.HTTP, logger)
+		if err != nil {
+			return nil, fmt.Errorf("create tei embedding backend: %w", err)
+		}
+		return &dlmodelInference{backend: backend, infer: embedder, reranker: embedder}, nil
+	case "hashing":
+		return &dlmodelInference{backend: backend, infer: embedding.NewHashingEmbedder(cfg.Hashing.Dimension)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported embedding backend %q", cfg.Backend)
+	}
+}
@@ -0,0 +1,35 @@
+//go:build cgo && !nojina
+
+package bootstrap
+
+import (
+	"strings"
+
+	"rag_imagetotext_texttoimage/internal/infra/cgo"
+	"rag_imagetotext_texttoimage/internal/util"
+)
+
+func newJinaInference(cfg util.EmbeddingSettings, logger util.Logger) (*dlmodelInference, error) {
+	resolvedJinaConfigPath, err := resolveJinaConfigPath(strings.TrimSpace(cfg.JinaConfigPath))
+	if err != nil {
+		logger.Error("failed to resolve jina config path", err, "config_path", cfg.JinaConfigPath)
+		return nil, err
+	}
+	runtimeConfigPath, cleanup, err := prepareJinaRuntime(resolvedJinaConfigPath)
+	if err != nil {
+		logger.Error("failed to prepare jina runtime", err, "resolved_config_path", resolvedJinaConfigPath)
+		return nil, err
+	}
+	if err := validateJinaRuntimeConfig(runtimeConfigPath); err != nil {
+		cleanup()
+		logger.Error("invalid jina runtime config", err, "config_path", runtimeConfigPath)
+		return nil, err
+	}
+	jina, err := cgo.NewJinaAdapter(runtimeConfigPath, logger)
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+	logger.Info("jina adapter ready", "runtime_config_path", runtimeConfigPath)
+	return &dlmodelInference{backend: "jina", infer: jina, reranker: jina, cleanup: cleanup}, nil
+}
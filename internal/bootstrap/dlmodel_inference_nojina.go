@@ -0,0 +1,17 @@
+//go:build !cgo || nojina
+
+package bootstrap
+
+import (
+	"errors"
+
+	"rag_imagetotext_texttoimage/internal/util"
+)
+
+// Built with -tags nojina or CGO_ENABLED=0: the ONNX runtime and OpenCV are
+// not linked, so only the HTTP and hashing backends are available.
+func newJinaInference(cfg util.EmbeddingSettings, logger util.Logger) (*dlmodelInference, error) {
+	err := errors.New("jina embedding backend is not built in (nojina tag or cgo disabled); set embedding_service.backend to openai, tei or hashing")
+	logger.Error("embedding backend unavailable", err, "backend", "jina")
+	return nil, err
+}
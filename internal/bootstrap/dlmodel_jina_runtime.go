@@ -60,6 +60,12 @@ func prepareJinaRuntime(rawConfigPath string) (string, func(), error) {
 	if p, ok := getNestedString(cfg, "models", "jina_vision_encoder", "model_path"); ok {
 		_ = setNestedString(cfg, resolveMaybeRelative(runtimeRoot, p), "models", "jina_vision_encoder", "model_path")
 	}
+	if p, ok := getNestedString(cfg, "models", "cross_encoder_reranker", "model_path"); ok {
+		_ = setNestedString(cfg, resolveMaybeRelative(runtimeRoot, p), "models", "cross_encoder_reranker", "model_path")
+	}
+	if p, ok := getNestedString(cfg, "models", "cross_encoder_reranker", "vocab_path"); ok {
+		_ = setNestedString(cfg, resolveMaybeRelative(runtimeRoot, p), "models", "cross_encoder_reranker", "vocab_path")
+	}
 
 	normalized, err := yaml.Marshal(cfg)
 	if err != nil {
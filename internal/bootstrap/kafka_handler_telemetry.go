@@ -74,7 +74,8 @@ func (k *kafkaHandlerTelemetry) recover(handlerErr *error) {
 		k.observe("panic")
 	}
 	if handlerErr != nil {
-		*handlerErr = panicErr
+		// A message that panics the handler is poison: skip the retries.
+		*handlerErr = ports.Permanent(panicErr)
 	}
 	if k.logger != nil {
 		k.logger.Error(k.panicLogMessage, panicErr, "topic", k.msg.Topic, "offset", k.msg.Offset)
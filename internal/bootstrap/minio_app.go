@@ -156,7 +156,8 @@ func (a *MinioApp) Run() error {
 	rootCtx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	consumerErrCh := a.consumer.Start(rootCtx, a.topics.UploadRequest, a.topics.UploadGroup, func(ctx context.Context, msg ports.ConsumeMessage) (handlerErr error) {
+	retry := kafkaAdapter.NewRetryMiddleware(a.producer, kafkaAdapter.RetryPolicyFromConfig(a.cfg.Kafka.Retry), a.metrics, a.logger)
+	consumerErrCh := a.consumer.StartWithRetry(rootCtx, a.topics.UploadRequest, a.topics.UploadGroup, "minio_upload", func(ctx context.Context, msg ports.ConsumeMessage) (handlerErr error) {
 		handlerName := "minio_upload"
 		telemetry := newKafkaHandlerTelemetry(a.metrics, a.logger, msg, handlerName, "panic recovered in minio upload handler")
 		telemetry.start()
@@ -167,7 +168,7 @@ func (a *MinioApp) Run() error {
 		if err := json.Unmarshal(msg.Message.Value, &uploadReq); err != nil {
 			telemetry.observe("invalid_payload")
 			a.logger.Error("internal.bootstrap.MinioApp.Run invalid upload request payload", err, "topic", msg.Topic, "offset", msg.Offset)
-			return nil
+			return ports.Permanent(fmt.Errorf("decode upload request payload: %w", err))
 		}
 
 		if folderDownload := strings.TrimSpace(uploadReq.FolderDownload); folderDownload != "" && !filepath.IsAbs(folderDownload) {
@@ -182,6 +183,9 @@ func (a *MinioApp) Run() error {
 			message = uploadErr.Error()
 			telemetry.observe("failed")
 			a.logger.Error("internal.bootstrap.MinioApp.Run upload use case failed", uploadErr, "topic", msg.Topic, "offset", msg.Offset, "url_download", uploadReq.UrlDownload)
+			if !kafkaAdapter.FinalAttempt(ctx) {
+				return fmt.Errorf("upload to minio: %w", uploadErr)
+			}
 		}
 
 		if a.topics.UploadResult != "" {
@@ -195,14 +199,15 @@ func (a *MinioApp) Run() error {
 			if publishErr != nil {
 				telemetry.retry("publish_error")
 				a.logger.Error("internal.bootstrap.MinioApp.Run publish upload result failed", publishErr, "result_topic", a.topics.UploadResult)
+				return fmt.Errorf("publish upload result: %w", publishErr)
 			}
 		}
 		if status == "success" {
 			telemetry.observe("success")
 		}
 		a.logger.Info("internal.bootstrap.MinioApp.Run minio upload pipeline completed", "topic", msg.Topic, "status", status, "url_download", uploadReq.UrlDownload, "latency_ms", time.Since(telemetry.startedAt).Milliseconds())
-		return nil
-	})
+		return uploadErr
+	}, retry)
 
 	go func() {
 		a.logger.Info("internal.bootstrap.MinioApp.Run minio grpc server listening", "port", a.grpcPort)
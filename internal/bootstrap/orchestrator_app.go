@@ -13,7 +13,10 @@ import (
 
 	"google.golang.org/grpc"
 
-	orchestratorUC "rag_imagetotext_texttoimage/internal/application/use_cases/orchestrator"
+	kafkaAdapter "rag_imagetotext_texttoimage/internal/adapter/kafka"
+	"rag_imagetotext_texttoimage/internal/application/ports"
+	portsOrchestrator "rag_imagetotext_texttoimage/internal/application/ports/orchestrator"
+	chatUC "rag_imagetotext_texttoimage/internal/application/use_cases/orchestrator/chat"
 	infraKafka "rag_imagetotext_texttoimage/internal/infra/kafka"
 	"rag_imagetotext_texttoimage/internal/util"
 	pb "rag_imagetotext_texttoimage/proto"
@@ -24,21 +27,26 @@ type OrchestratorApp struct {
 	logger     util.Logger
 	httpPort   string
 	httpServer *http.Server
-	session    *orchestratorUC.InMemorySessionStore
+	session    portsOrchestrator.ManagedSessionStore
+	ingestJobs ports.IngestJobManager
 	publisher  *infraKafka.Publisher
 	consumer   *infraKafka.Consumer
+	replies    *kafkaAdapter.RequestReply
 	ragConn    *grpc.ClientConn
 	llmConn    *grpc.ClientConn
 	dlConn     *grpc.ClientConn
+	minioConn  *grpc.ClientConn
 }
 
 type orchestratorClients struct {
-	ragClient pb.RagServiceClient
-	ragConn   *grpc.ClientConn
-	llmClient pb.LlmServiceClient
-	llmConn   *grpc.ClientConn
-	dlClient  pb.DeepLearningServiceClient
-	dlConn    *grpc.ClientConn
+	ragClient   pb.RagServiceClient
+	ragConn     *grpc.ClientConn
+	llmClient   pb.LlmServiceClient
+	llmConn     *grpc.ClientConn
+	dlClient    pb.DeepLearningServiceClient
+	dlConn      *grpc.ClientConn
+	minioClient pb.MinioServiceClient
+	minioConn   *grpc.ClientConn
 }
 
 type orchestratorPrompts struct {
@@ -49,6 +57,7 @@ type orchestratorPrompts struct {
 type orchestratorKafkaInfra struct {
 	publisher *infraKafka.Publisher
 	consumer  *infraKafka.Consumer
+	replies   *kafkaAdapter.RequestReply
 }
 
 func NewOrchestratorApp() (*OrchestratorApp, error) {
@@ -63,6 +72,7 @@ func NewOrchestratorApp() (*OrchestratorApp, error) {
 	kafkaInfraKey := registry.Key("kafka.infra")
 	sessionTTLKey := registry.Key("session.ttl_seconds")
 	sessionStoreKey := registry.Key("session.store")
+	ingestJobsKey := registry.Key("ingest.jobs")
 	httpServerKey := registry.Key("http.server")
 
 	if err := registerOrchestratorBindings(container, orchestratorBindingKeys{
@@ -74,6 +84,7 @@ func NewOrchestratorApp() (*OrchestratorApp, error) {
 		KafkaInfraKey:   kafkaInfraKey,
 		SessionTTLKey:   sessionTTLKey,
 		SessionStoreKey: sessionStoreKey,
+		IngestJobsKey:   ingestJobsKey,
 		HTTPServerKey:   httpServerKey,
 	}); err != nil {
 		return nil, err
@@ -105,8 +116,21 @@ func NewOrchestratorApp() (*OrchestratorApp, error) {
 		logger.Close()
 		return nil, err
 	}
-	sessionStore, err := ResolveAs[*orchestratorUC.InMemorySessionStore](container, sessionStoreKey)
+	sessionStore, err := ResolveAs[portsOrchestrator.ManagedSessionStore](container, sessionStoreKey)
 	if err != nil {
+		_ = kafkaInfra.replies.Close()
+		_ = kafkaInfra.publisher.Close()
+		_ = kafkaInfra.consumer.Close()
+		_ = clients.ragConn.Close()
+		_ = clients.llmConn.Close()
+		_ = clients.dlConn.Close()
+		logger.Close()
+		return nil, err
+	}
+	ingestJobs, err := ResolveAs[ports.IngestJobManager](container, ingestJobsKey)
+	if err != nil {
+		sessionStore.Close()
+		_ = kafkaInfra.replies.Close()
 		_ = kafkaInfra.publisher.Close()
 		_ = kafkaInfra.consumer.Close()
 		_ = clients.ragConn.Close()
@@ -117,7 +141,9 @@ func NewOrchestratorApp() (*OrchestratorApp, error) {
 	}
 	httpServer, err := ResolveAs[*http.Server](container, httpServerKey)
 	if err != nil {
+		ingestJobs.Close()
 		sessionStore.Close()
+		_ = kafkaInfra.replies.Close()
 		_ = kafkaInfra.publisher.Close()
 		_ = kafkaInfra.consumer.Close()
 		_ = clients.ragConn.Close()
@@ -135,11 +161,14 @@ func NewOrchestratorApp() (*OrchestratorApp, error) {
 		httpPort:   httpPort,
 		httpServer: httpServer,
 		session:    sessionStore,
+		ingestJobs: ingestJobs,
 		publisher:  kafkaInfra.publisher,
 		consumer:   kafkaInfra.consumer,
+		replies:    kafkaInfra.replies,
 		ragConn:    clients.ragConn,
 		llmConn:    clients.llmConn,
 		dlConn:     clients.dlConn,
+		minioConn:  clients.minioConn,
 	}, nil
 }
 
@@ -184,9 +213,15 @@ func (a *OrchestratorApp) Close() {
 	if a == nil {
 		return
 	}
+	if a.ingestJobs != nil {
+		a.ingestJobs.Close()
+	}
 	if a.session != nil {
 		a.session.Close()
 	}
+	if a.replies != nil {
+		_ = a.replies.Close()
+	}
 	if a.publisher != nil {
 		_ = a.publisher.Close()
 	}
@@ -202,6 +237,9 @@ func (a *OrchestratorApp) Close() {
 	if a.dlConn != nil {
 		_ = a.dlConn.Close()
 	}
+	if a.minioConn != nil {
+		_ = a.minioConn.Close()
+	}
 	if a.logger != nil {
 		a.logger.Close()
 	}
@@ -220,6 +258,21 @@ func prepareOrchestratorDefaults(cfg *util.Config) {
 	if cfg.OrchestratorService.PreProcessing.StructOutput == nil {
 		cfg.OrchestratorService.PreProcessing.StructOutput = map[string]string{"NewQuery": "string", "CurrentQuery": "string"}
 	}
+	if _, ok := cfg.OrchestratorService.PreProcessing.StructOutput["Intent"]; !ok {
+		cfg.OrchestratorService.PreProcessing.StructOutput["Intent"] = chatUC.IntentOutputSchema
+	}
+	if strings.TrimSpace(cfg.OrchestratorService.Intent.Default) == "" {
+		cfg.OrchestratorService.Intent.Default = chatUC.IntentKnowledge
+	}
+	if len(cfg.OrchestratorService.Intent.Rules) == 0 {
+		cfg.OrchestratorService.Intent.Rules = []util.IntentRule{
+			{
+				Intent:   chatUC.IntentChitChat,
+				Patterns: []string{"xin chao", "chao ban", "hello", "hi", "hey", "alo", "good morning", "good afternoon"},
+				MaxWords: 4,
+			},
+		}
+	}
 	if cfg.OrchestratorService.MemoryHistoryTopK <= 0 {
 		cfg.OrchestratorService.MemoryHistoryTopK = 5
 	}
@@ -241,6 +294,48 @@ func prepareOrchestratorDefaults(cfg *util.Config) {
 	if strings.TrimSpace(cfg.OrchestratorService.Vectordb.ImageVectorDistance) == "" {
 		cfg.OrchestratorService.Vectordb.ImageVectorDistance = "cosine"
 	}
+	if strings.TrimSpace(cfg.OrchestratorService.SessionStore.Backend) == "" {
+		cfg.OrchestratorService.SessionStore.Backend = "memory"
+	}
+	if strings.TrimSpace(cfg.OrchestratorService.SessionStore.BoltPath) == "" {
+		cfg.OrchestratorService.SessionStore.BoltPath = "data/sessions.db"
+	}
+	if strings.TrimSpace(cfg.OrchestratorService.SessionStore.RedisKeyPrefix) == "" {
+		cfg.OrchestratorService.SessionStore.RedisKeyPrefix = "rag:orchestrator:"
+	}
+	if strings.TrimSpace(cfg.OrchestratorService.Rerank.Strategy) == "" {
+		cfg.OrchestratorService.Rerank.Strategy = "none"
+	}
+	if cfg.OrchestratorService.Rerank.CandidateTopK <= 0 {
+		cfg.OrchestratorService.Rerank.CandidateTopK = 20
+	}
+	if cfg.OrchestratorService.Rerank.TopN <= 0 {
+		cfg.OrchestratorService.Rerank.TopN = cfg.OrchestratorService.RAGRetrievalTopK
+	}
+	if strings.TrimSpace(cfg.OrchestratorService.MemorySummary.Model) == "" {
+		cfg.OrchestratorService.MemorySummary.Model = cfg.OrchestratorService.PreProcessing.Model
+	}
+	if cfg.OrchestratorService.MemorySummary.MaxChars <= 0 {
+		cfg.OrchestratorService.MemorySummary.MaxChars = 2000
+	}
+	if cfg.OrchestratorService.Context.TokenBudget <= 0 {
+		cfg.OrchestratorService.Context.TokenBudget = 3000
+	}
+	if cfg.OrchestratorService.Context.NearDuplicateThreshold <= 0 {
+		cfg.OrchestratorService.Context.NearDuplicateThreshold = 0.8
+	}
+	if cfg.OrchestratorService.IngestJobs.Workers <= 0 {
+		cfg.OrchestratorService.IngestJobs.Workers = 2
+	}
+	if cfg.OrchestratorService.IngestJobs.QueueSize <= 0 {
+		cfg.OrchestratorService.IngestJobs.QueueSize = 32
+	}
+	if cfg.OrchestratorService.IngestJobs.RetentionSeconds <= 0 {
+		cfg.OrchestratorService.IngestJobs.RetentionSeconds = 86400
+	}
+	if strings.TrimSpace(cfg.OrchestratorService.Rerank.Model) == "" {
+		cfg.OrchestratorService.Rerank.Model = cfg.OrchestratorService.PreProcessing.Model
+	}
 }
 
 func normalizeBrokers(raw []string) []string {
@@ -260,7 +355,7 @@ func normalizeBrokers(raw []string) []string {
 	return out
 }
 
-const defaultPromptAnswer = "Dua tren context duoc cung cap, hay tra loi cau hoi mot cach ngan gon, chinh xac va bang tieng Viet."
+const defaultPromptAnswer = "Dua tren context duoc cung cap, hay tra loi cau hoi mot cach ngan gon, chinh xac va bang tieng Viet. Cac doan context duoc danh so [1], [2], ...; khi su dung thong tin tu doan nao hay trich dan so tuong ung, vi du [1]."
 
 var promptPreprocessingCandidates = []string{
 	ProjectPath("data", "prompt", "preprocessing.txt"),
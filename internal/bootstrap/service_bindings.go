@@ -9,12 +9,20 @@ import (
 	"strings"
 	"time"
 
+	"github.com/redis/go-redis/v9"
+
 	inbound "rag_imagetotext_texttoimage/internal/adapter/inbound"
 	inboundRouter "rag_imagetotext_texttoimage/internal/adapter/inbound/router"
+	kafkaAdapter "rag_imagetotext_texttoimage/internal/adapter/kafka"
+	"rag_imagetotext_texttoimage/internal/application/ports"
+	portsOrchestrator "rag_imagetotext_texttoimage/internal/application/ports/orchestrator"
 	orchestratorUC "rag_imagetotext_texttoimage/internal/application/use_cases/orchestrator"
 	chatUC "rag_imagetotext_texttoimage/internal/application/use_cases/orchestrator/chat"
 	trainingfile "rag_imagetotext_texttoimage/internal/application/use_cases/orchestrator/training_file"
+	"rag_imagetotext_texttoimage/internal/infra/docparser"
 	infraKafka "rag_imagetotext_texttoimage/internal/infra/kafka"
+	"rag_imagetotext_texttoimage/internal/infra/monitoring"
+	infraSession "rag_imagetotext_texttoimage/internal/infra/session"
 	"rag_imagetotext_texttoimage/internal/util"
 )
 
@@ -46,6 +54,7 @@ type orchestratorBindingKeys struct {
 	KafkaInfraKey   string
 	SessionTTLKey   string
 	SessionStoreKey string
+	IngestJobsKey   string
 	HTTPServerKey   string
 }
 
@@ -148,13 +157,30 @@ func registerOrchestratorCoreInfra(container *DIContainer, keys orchestratorBind
 		}
 		logger.Info("orchestrator dependency ready", "service", "embedding", "host", dlHost, "port", dlPort)
 
+		// MinIO is only used to presign source images, so chat keeps working
+		// without it.
+		minioHost := strings.TrimSpace(os.Getenv("MINIO_SERVICE_HOST"))
+		if minioHost == "" {
+			minioHost = "localhost"
+		}
+		minioPort := strings.TrimSpace(cfg.MinIOService.GRPCPort)
+		minioClient, minioConn, err := orchestratorUC.NewMinioServiceClient(ctx, minioHost, minioPort)
+		if err != nil {
+			logger.Error("orchestrator dependency unavailable, source images will not be presigned", err, "service", "minio", "host", minioHost, "port", minioPort)
+			minioClient, minioConn = nil, nil
+		} else {
+			logger.Info("orchestrator dependency ready", "service", "minio", "host", minioHost, "port", minioPort)
+		}
+
 		return orchestratorClients{
-			ragClient: ragClient,
-			ragConn:   ragConn,
-			llmClient: llmClient,
-			llmConn:   llmConn,
-			dlClient:  dlClient,
-			dlConn:    dlConn,
+			ragClient:   ragClient,
+			ragConn:     ragConn,
+			llmClient:   llmClient,
+			llmConn:     llmConn,
+			dlClient:    dlClient,
+			dlConn:      dlConn,
+			minioClient: minioClient,
+			minioConn:   minioConn,
 		}, nil
 	}); err != nil {
 		return err
@@ -199,7 +225,8 @@ func registerOrchestratorCoreInfra(container *DIContainer, keys orchestratorBind
 			_ = publisher.Close()
 			return nil, fmt.Errorf("create kafka consumer: %w", err)
 		}
-		return orchestratorKafkaInfra{publisher: publisher, consumer: consumer}, nil
+		replies := kafkaAdapter.NewRequestReply(publisher, consumer, kafkaAdapter.ReplyGroupID("orchestrator"), logger)
+		return orchestratorKafkaInfra{publisher: publisher, consumer: consumer, replies: replies}, nil
 	}); err != nil {
 		return err
 	}
@@ -232,15 +259,67 @@ func registerOrchestratorCoreInfra(container *DIContainer, keys orchestratorBind
 		if err != nil {
 			return nil, err
 		}
-		logger.Info("orchestrator session config", "session_ttl_seconds", ttl)
-		return orchestratorUC.NewInMemorySessionStore(time.Duration(ttl) * time.Second), nil
+		cfg, err := ResolveAs[*util.Config](r, keys.ConfigKey)
+		if err != nil {
+			return nil, err
+		}
+		storeCfg := cfg.OrchestratorService.SessionStore
+		logger.Info("orchestrator session config", "session_ttl_seconds", ttl, "backend", storeCfg.Backend)
+		return newOrchestratorSessionStore(storeCfg, time.Duration(ttl)*time.Second, logger)
 	}); err != nil {
 		return err
 	}
 	return nil
 }
 
+func newOrchestratorSessionStore(storeCfg util.SessionStoreSettings, ttl time.Duration, logger util.Logger) (portsOrchestrator.ManagedSessionStore, error) {
+	switch strings.ToLower(strings.TrimSpace(storeCfg.Backend)) {
+	case "", "memory":
+		return orchestratorUC.NewInMemorySessionStore(ttl), nil
+	case "bolt":
+		store, err := infraSession.NewBoltSessionStore(storeCfg.BoltPath, ttl, logger)
+		if err != nil {
+			return nil, fmt.Errorf("create bolt session store: %w", err)
+		}
+		return store, nil
+	case "redis":
+		client := redis.NewClient(&redis.Options{
+			Addr:     storeCfg.RedisAddr,
+			Password: storeCfg.RedisPassword,
+			DB:       storeCfg.RedisDB,
+		})
+		store, err := infraSession.NewRedisSessionStore(client, storeCfg.RedisKeyPrefix, ttl, logger)
+		if err != nil {
+			_ = client.Close()
+			return nil, fmt.Errorf("create redis session store: %w", err)
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unsupported session store backend %q", storeCfg.Backend)
+	}
+}
+
 func registerOrchestratorHTTPServer(container *DIContainer, keys orchestratorBindingKeys) error {
+	if err := registerSingleton(container, keys.IngestJobsKey, func(r Resolver) (any, error) {
+		cfg, err := ResolveAs[*util.Config](r, keys.ConfigKey)
+		if err != nil {
+			return nil, err
+		}
+		logger, err := ResolveAs[util.Logger](r, keys.LoggerKey)
+		if err != nil {
+			return nil, err
+		}
+		kafkaInfra, err := ResolveAs[orchestratorKafkaInfra](r, keys.KafkaInfraKey)
+		if err != nil {
+			return nil, err
+		}
+		trainingFileUseCase := trainingfile.NewTrainingFileUseCase(logger, kafkaInfra.publisher, kafkaInfra.replies, *cfg, docparser.NewDocumentParser(logger, cfg.FileTraining.DocumentParser))
+		jobsCfg := cfg.OrchestratorService.IngestJobs
+		logger.Info("orchestrator ingest job config", "workers", jobsCfg.Workers, "queue_size", jobsCfg.QueueSize, "retention_seconds", jobsCfg.RetentionSeconds)
+		return trainingfile.NewIngestJobManager(logger, trainingFileUseCase, jobsCfg), nil
+	}); err != nil {
+		return err
+	}
 	if err := registerSingleton(container, keys.HTTPServerKey, func(r Resolver) (any, error) {
 		cfg, err := ResolveAs[*util.Config](r, keys.ConfigKey)
 		if err != nil {
@@ -262,22 +341,22 @@ func registerOrchestratorHTTPServer(container *DIContainer, keys orchestratorBin
 		if err != nil {
 			return nil, err
 		}
-		kafkaInfra, err := ResolveAs[orchestratorKafkaInfra](r, keys.KafkaInfraKey)
+		ingestJobs, err := ResolveAs[ports.IngestJobManager](r, keys.IngestJobsKey)
 		if err != nil {
 			return nil, err
 		}
-		sessionStore, err := ResolveAs[*orchestratorUC.InMemorySessionStore](r, keys.SessionStoreKey)
+		sessionStore, err := ResolveAs[portsOrchestrator.ManagedSessionStore](r, keys.SessionStoreKey)
 		if err != nil {
 			return nil, err
 		}
 
-		chatHandlerUC := chatUC.NewChatbotHandler(sessionStore, logger, *cfg, clients.ragClient, clients.dlClient, clients.llmClient, prompts.preprocessing, prompts.postprocessing, defaultPromptAnswer)
+		chatHandlerUC := chatUC.NewChatbotHandler(sessionStore, logger, *cfg, clients.ragClient, clients.dlClient, clients.llmClient, clients.minioClient, prompts.preprocessing, prompts.postprocessing, defaultPromptAnswer, monitoring.NewChatMetrics())
 		vectordbHandlerUC := orchestratorUC.NewVectordbHandler(clients.ragClient)
-		trainingFileUseCase := trainingfile.NewTrainingFileUseCase(logger, kafkaInfra.publisher, kafkaInfra.consumer, *cfg)
 		httpHandler := inbound.NewHTTPHandler(
 			inboundRouter.NewHTTPHandlerChat(chatHandlerUC),
 			inboundRouter.NewHTTPHandlerVectordb(vectordbHandlerUC, cfg.OrchestratorService.Vectordb),
-			inboundRouter.NewHTTPHandlerTrainingFile(trainingFileUseCase),
+			inboundRouter.NewHTTPHandlerTrainingFile(ingestJobs),
+			inboundRouter.NewHTTPHandlerSession(orchestratorUC.NewSessionManager(sessionStore)),
 		)
 		router := inbound.SetupRouter(httpHandler)
 		return inbound.NewHTTPServer(":"+httpPort, router), nil
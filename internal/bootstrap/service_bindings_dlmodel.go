@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"log/slog"
 	"net"
-	"strings"
 	"time"
 
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
@@ -13,8 +12,8 @@ import (
 
 	grpcAdapter "rag_imagetotext_texttoimage/internal/adapter/grpc"
 	kafkaAdapter "rag_imagetotext_texttoimage/internal/adapter/kafka"
-	"rag_imagetotext_texttoimage/internal/infra/cgo"
 	infraKafka "rag_imagetotext_texttoimage/internal/infra/kafka"
+	"rag_imagetotext_texttoimage/internal/infra/monitoring"
 	"rag_imagetotext_texttoimage/internal/util"
 	pb "rag_imagetotext_texttoimage/proto"
 )
@@ -22,8 +21,8 @@ import (
 type dlmodelBindingKeys struct {
 	ConfigKey      string
 	LoggerKey      string
-	JinaRuntimeKey string
-	JinaAdapterKey string
+	InferenceKey   string
+	BatcherKey     string
 	TopicsKey      string
 	KafkaInfraKey  string
 	GRPCServerKey  string
@@ -55,45 +54,17 @@ func registerDLModelConfigAndLogger(container *DIContainer, keys dlmodelBindingK
 }
 
 func registerDLModelRuntime(container *DIContainer, keys dlmodelBindingKeys) error {
-	if err := registerSingleton(container, keys.JinaRuntimeKey, func(r Resolver) (any, error) {
+	if err := registerSingleton(container, keys.InferenceKey, func(r Resolver) (any, error) {
 		cfg, logger, err := resolveServiceConfigAndLogger(r, keys.ConfigKey, keys.LoggerKey)
 		if err != nil {
 			return nil, err
 		}
-		resolvedJinaConfigPath, err := resolveJinaConfigPath(strings.TrimSpace(cfg.EmbeddingService.JinaConfigPath))
+		inference, err := newDLModelInference(cfg.EmbeddingService, logger)
 		if err != nil {
-			logger.Error("failed to resolve jina config path", err, "config_path", cfg.EmbeddingService.JinaConfigPath)
 			return nil, err
 		}
-		runtimeConfigPath, cleanup, err := prepareJinaRuntime(resolvedJinaConfigPath)
-		if err != nil {
-			logger.Error("failed to prepare jina runtime", err, "resolved_config_path", resolvedJinaConfigPath)
-			return nil, err
-		}
-		if err := validateJinaRuntimeConfig(runtimeConfigPath); err != nil {
-			cleanup()
-			logger.Error("invalid jina runtime config", err, "config_path", runtimeConfigPath)
-			return nil, err
-		}
-		return &dlmodelJinaRuntimeConfig{path: runtimeConfigPath, cleanup: cleanup}, nil
-	}); err != nil {
-		return err
-	}
-	if err := registerSingleton(container, keys.JinaAdapterKey, func(r Resolver) (any, error) {
-		_, logger, err := resolveServiceConfigAndLogger(r, keys.ConfigKey, keys.LoggerKey)
-		if err != nil {
-			return nil, err
-		}
-		runtimeCfg, err := ResolveAs[*dlmodelJinaRuntimeConfig](r, keys.JinaRuntimeKey)
-		if err != nil {
-			return nil, err
-		}
-		jina, err := cgo.NewJinaAdapter(runtimeCfg.path, logger)
-		if err != nil {
-			return nil, err
-		}
-		logger.Info("jina adapter ready", "runtime_config_path", runtimeCfg.path)
-		return jina, nil
+		logger.Info("embedding backend ready", "backend", inference.backend, "reranker", inference.reranker != nil)
+		return inference, nil
 	}); err != nil {
 		return err
 	}
@@ -137,16 +108,37 @@ func registerDLModelTopicsAndKafka(container *DIContainer, keys dlmodelBindingKe
 }
 
 func registerDLModelGRPC(container *DIContainer, keys dlmodelBindingKeys) error {
+	if err := registerSingleton(container, keys.BatcherKey, func(r Resolver) (any, error) {
+		cfg, logger, err := resolveServiceConfigAndLogger(r, keys.ConfigKey, keys.LoggerKey)
+		if err != nil {
+			return nil, err
+		}
+		if !cfg.EmbeddingService.GRPCBatching.Enabled {
+			logger.Info("embedding grpc batcher disabled")
+			return (*grpcAdapter.EmbeddingBatcher)(nil), nil
+		}
+		inference, err := ResolveAs[*dlmodelInference](r, keys.InferenceKey)
+		if err != nil {
+			return nil, err
+		}
+		return grpcAdapter.NewEmbeddingBatcher(inference.infer, cfg.EmbeddingService.GRPCBatching, monitoring.NewEmbeddingBatchMetrics(), logger), nil
+	}); err != nil {
+		return err
+	}
 	if err := registerSingleton(container, keys.GRPCServerKey, func(r Resolver) (any, error) {
 		_, logger, err := resolveServiceConfigAndLogger(r, keys.ConfigKey, keys.LoggerKey)
 		if err != nil {
 			return nil, err
 		}
-		jina, err := ResolveAs[*cgo.JinaAdapter](r, keys.JinaAdapterKey)
+		inference, err := ResolveAs[*dlmodelInference](r, keys.InferenceKey)
+		if err != nil {
+			return nil, err
+		}
+		batcher, err := ResolveAs[*grpcAdapter.EmbeddingBatcher](r, keys.BatcherKey)
 		if err != nil {
 			return nil, err
 		}
-		embeddingService := grpcAdapter.NewEmbeddingService(logger, jina)
+		embeddingService := grpcAdapter.NewEmbeddingService(logger, inference.infer, inference.reranker, batcher)
 		grpcServer := grpc.NewServer(
 			grpc.UnaryInterceptor(grpc_prometheus.UnaryServerInterceptor),
 			grpc.StreamInterceptor(grpc_prometheus.StreamServerInterceptor),
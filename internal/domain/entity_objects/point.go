@@ -1,8 +1,12 @@
 package domain
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type BoundingBox struct {
@@ -31,11 +35,17 @@ type PointPayload struct {
 	OCRText      string       `json:"ocr_text,omitempty"`
 	BBox         *BoundingBox `json:"bbox,omitempty"`
 	ImagePath    string       `json:"image_path,omitempty"`
+	ObjectKey    string       `json:"object_key,omitempty"`
 	SectionTitle string       `json:"section_title,omitempty"`
+	HeadingPath  string       `json:"heading_path,omitempty"`
 	Lang         string       `json:"lang,omitempty"`
 	HasTable     bool         `json:"has_table,omitempty"`
+	TableCSV     string       `json:"table_csv,omitempty"`
 	HasFigure    bool         `json:"has_figure,omitempty"`
 	ParentID     string       `json:"parent_id,omitempty"`
+	DocHash      string       `json:"doc_hash,omitempty"`
+	ChunkHash    string       `json:"chunk_hash,omitempty"`
+	IngestRunID  string       `json:"ingest_run_id,omitempty"`
 	ChunkIndex   int          `json:"chunk_index,omitempty"`
 	TokenCount   int          `json:"token_count,omitempty"`
 	Keywords     []string     `json:"keywords,omitempty"`
@@ -48,6 +58,38 @@ type PointObject struct {
 	Payload PointPayload `json:"payload"`
 }
 
+// HeadingPathSeparator joins the headings of PointPayload.HeadingPath,
+// outermost first.
+const HeadingPathSeparator = " > "
+
+// pointIDNamespace is the UUIDv5 namespace for point ids derived from
+// doc_id and chunk content.
+var pointIDNamespace = uuid.MustParse("5b0f7c9e-3a51-4c1d-9e52-6f2a8d7b4e10")
+
+// ContentHash is the hex sha256 of parts joined with a NUL separator.
+func ContentHash(parts ...string) string {
+	h := sha256.New()
+	for i, part := range parts {
+		if i > 0 {
+			h.Write([]byte{0})
+		}
+		h.Write([]byte(part))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ChunkContentHash hashes the fields that make up the content of a point, so
+// the same chunk produced by two ingest runs gets the same hash.
+func ChunkContentHash(payload PointPayload) string {
+	return ContentHash(payload.UnitType, payload.Text, payload.OCRText, payload.ImagePath)
+}
+
+// DeterministicPointID returns the UUIDv5 of doc_id and chunk hash; upserting
+// an unchanged chunk again overwrites the same point instead of adding one.
+func DeterministicPointID(docID string, chunkHash string) string {
+	return uuid.NewSHA1(pointIDNamespace, []byte(docID+"\x00"+chunkHash)).String()
+}
+
 func NewPointObject(id string, vector VectorObject, payload PointPayload) (*PointObject, error) {
 	point := &PointObject{
 		ID:      id,
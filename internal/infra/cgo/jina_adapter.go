@@ -19,6 +19,7 @@ import (
 )
 
 var _ ports.Inference = (*JinaAdapter)(nil)
+var _ ports.Reranker = (*JinaAdapter)(nil)
 
 type JinaAdapter struct {
 	handle C.JinaHandle
@@ -215,3 +216,46 @@ func (a *JinaAdapter) EmbedBatchImage(images [][]byte, width, height, channels i
 
 	return results, nil
 }
+
+func (a *JinaAdapter) Rerank(query string, documents []string) ([]float32, error) {
+	count := len(documents)
+	if count == 0 {
+		return nil, nil
+	}
+
+	a.mu.RLock()
+	if a.closed || a.handle == nil {
+		a.mu.RUnlock()
+		return nil, errors.New("jina adapter is closed")
+	}
+	handle := a.handle
+	defer a.mu.RUnlock()
+
+	cQuery := C.CString(query)
+	defer C.free(unsafe.Pointer(cQuery))
+
+	cArray := C.malloc(C.size_t(uintptr(count) * unsafe.Sizeof(uintptr(0))))
+	defer C.free(cArray)
+
+	cPtrs := (*[1 << 28]*C.char)(cArray)
+	for i, s := range documents {
+		cStr := C.CString(s)
+		defer C.free(unsafe.Pointer(cStr))
+		cPtrs[i] = cStr
+	}
+
+	scores := make([]float32, count)
+	res := C.jina_rerank(handle, cQuery, (**C.char)(cArray), C.int(count), (*C.float)(unsafe.Pointer(&scores[0])))
+	if res == -3 {
+		return nil, ports.ErrRerankerNotConfigured
+	}
+	if res != 0 {
+		lastErr := strings.TrimSpace(C.GoString(C.jina_last_error()))
+		err := fmt.Errorf("cgo error in rerank: %d %s", res, lastErr)
+		a.logger.Error("rerank failed", err, "count", count)
+		return nil, err
+	}
+	a.logger.Debug("rerank success", "count", count)
+
+	return scores, nil
+}
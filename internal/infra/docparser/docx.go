@@ -0,0 +1,279 @@
+package docparser
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"rag_imagetotext_texttoimage/internal/application/dtos"
+)
+
+// DocxParser reads word/document.xml: heading styles become markdown
+// headings, tables become pipe tables, embedded pictures are extracted and
+// explicit page breaks become page separators.
+type DocxParser struct{}
+
+func NewDocxParser() *DocxParser {
+	return &DocxParser{}
+}
+
+func (p *DocxParser) Name() string {
+	return ParserDocx
+}
+
+func (p *DocxParser) Parse(ctx context.Context, req dtos.ParseDocumentRequest) (dtos.ParseDocumentResult, error) {
+	if err := validateRequest(req); err != nil {
+		return dtos.ParseDocumentResult{}, err
+	}
+	archive, err := zip.OpenReader(req.FilePath)
+	if err != nil {
+		return dtos.ParseDocumentResult{}, fmt.Errorf("open docx: %w", err)
+	}
+	defer archive.Close()
+
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+	document, ok := files["word/document.xml"]
+	if !ok {
+		return dtos.ParseDocumentResult{}, errors.New("docx has no word/document.xml")
+	}
+	rels, err := readDocxRelationships(files["word/_rels/document.xml.rels"])
+	if err != nil {
+		return dtos.ParseDocumentResult{}, err
+	}
+
+	dir, _ := outputPaths(req)
+	r := &docxRenderer{
+		w:     newMarkdownWriter(dir),
+		files: files,
+		rels:  rels,
+	}
+	if err := r.render(ctx, document); err != nil {
+		return dtos.ParseDocumentResult{}, err
+	}
+	markdown := r.w.String()
+	if r.pageBreaks > 0 {
+		markdown = pageSeparator(0) + "\n\n" + markdown
+	}
+	return writeMarkdown(ParserDocx, req, markdown, r.w.images)
+}
+
+func readDocxRelationships(f *zip.File) (map[string]string, error) {
+	rels := map[string]string{}
+	if f == nil {
+		return rels, nil
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var doc struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := xml.NewDecoder(rc).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode docx relationships: %w", err)
+	}
+	for _, rel := range doc.Relationships {
+		rels[rel.ID] = rel.Target
+	}
+	return rels, nil
+}
+
+type docxRenderer struct {
+	w          *markdownWriter
+	files      map[string]*zip.File
+	rels       map[string]string
+	pageBreaks int
+
+	paragraph    strings.Builder
+	headingLevel int
+	listItem     bool
+
+	tableDepth int
+	rows       [][]string
+	cell       strings.Builder
+	tableImgs  []string
+}
+
+func (r *docxRenderer) render(ctx context.Context, document *zip.File) error {
+	rc, err := document.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	decoder := xml.NewDecoder(rc)
+	inText := false
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("decode docx document: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "p":
+				r.paragraph.Reset()
+				r.headingLevel = 0
+				r.listItem = false
+			case "pStyle":
+				r.headingLevel = docxHeadingLevel(xmlAttr(t, "val"))
+			case "outlineLvl":
+				if level, err := strconv.Atoi(xmlAttr(t, "val")); err == nil && r.headingLevel == 0 {
+					r.headingLevel = level + 1
+				}
+			case "numPr":
+				r.listItem = true
+			case "t":
+				inText = true
+			case "tab":
+				r.paragraph.WriteString(" ")
+			case "br":
+				if xmlAttr(t, "type") == "page" && r.tableDepth == 0 {
+					r.endParagraph()
+					r.pageBreaks++
+					r.w.pageBreak(r.pageBreaks)
+				} else {
+					r.paragraph.WriteString(" ")
+				}
+			case "blip":
+				r.image(xmlAttr(t, "embed"))
+			case "tbl":
+				if r.tableDepth == 0 {
+					r.rows = nil
+					r.tableImgs = nil
+				}
+				r.tableDepth++
+			case "tr":
+				if r.tableDepth == 1 {
+					r.rows = append(r.rows, []string{})
+				}
+			case "tc":
+				if r.tableDepth == 1 {
+					r.cell.Reset()
+				}
+			}
+		case xml.CharData:
+			if inText {
+				r.paragraph.Write(t)
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				r.endParagraph()
+			case "tc":
+				if r.tableDepth == 1 && len(r.rows) > 0 {
+					last := len(r.rows) - 1
+					r.rows[last] = append(r.rows[last], strings.TrimSpace(r.cell.String()))
+				}
+			case "tbl":
+				r.tableDepth--
+				if r.tableDepth == 0 {
+					r.w.table(r.rows)
+					for _, target := range r.tableImgs {
+						r.writeImage(target)
+					}
+				}
+			}
+		}
+	}
+}
+
+func (r *docxRenderer) endParagraph() {
+	text := strings.Join(strings.Fields(r.paragraph.String()), " ")
+	r.paragraph.Reset()
+	if text == "" {
+		return
+	}
+	if r.tableDepth > 0 {
+		if r.cell.Len() > 0 {
+			r.cell.WriteString(" ")
+		}
+		r.cell.WriteString(text)
+		return
+	}
+	switch {
+	case r.headingLevel > 0:
+		r.w.heading(r.headingLevel, text)
+	case r.listItem:
+		r.w.block("- " + text)
+	default:
+		r.w.block(text)
+	}
+}
+
+func (r *docxRenderer) image(relID string) {
+	target, ok := r.rels[relID]
+	if !ok {
+		return
+	}
+	if r.tableDepth > 0 {
+		r.tableImgs = append(r.tableImgs, target)
+		return
+	}
+	r.endParagraph()
+	r.writeImage(target)
+}
+
+func (r *docxRenderer) writeImage(target string) {
+	f, ok := r.files[path.Clean(path.Join("word", target))]
+	if !ok {
+		return
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return
+	}
+	_ = r.w.image(data, strings.ToLower(filepath.Ext(target)))
+}
+
+// docxHeadingLevel maps the built-in style ids Title and Heading1..Heading9.
+func docxHeadingLevel(styleID string) int {
+	id := strings.ToLower(strings.ReplaceAll(styleID, " ", ""))
+	if id == "title" {
+		return 1
+	}
+	if level, ok := strings.CutPrefix(id, "heading"); ok {
+		if n, err := strconv.Atoi(level); err == nil && n > 0 {
+			return min(n, 6)
+		}
+	}
+	return 0
+}
+
+func xmlAttr(el xml.StartElement, local string) string {
+	for _, attr := range el.Attr {
+		if attr.Name.Local == local {
+			return attr.Value
+		}
+	}
+	return ""
+}
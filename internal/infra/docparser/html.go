@@ -0,0 +1,214 @@
+package docparser
+
+import (
+	"context"
+	"encoding/base64"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"rag_imagetotext_texttoimage/internal/application/dtos"
+)
+
+// HTMLParser renders headings, paragraphs, lists and tables of an HTML page as
+// markdown. Embedded data: images and images next to the source file are
+// kept; remote images are dropped.
+type HTMLParser struct{}
+
+func NewHTMLParser() *HTMLParser {
+	return &HTMLParser{}
+}
+
+func (p *HTMLParser) Name() string {
+	return ParserHTML
+}
+
+func (p *HTMLParser) Parse(ctx context.Context, req dtos.ParseDocumentRequest) (dtos.ParseDocumentResult, error) {
+	if err := validateRequest(req); err != nil {
+		return dtos.ParseDocumentResult{}, err
+	}
+	file, err := os.Open(req.FilePath)
+	if err != nil {
+		return dtos.ParseDocumentResult{}, err
+	}
+	defer file.Close()
+
+	doc, err := html.Parse(file)
+	if err != nil {
+		return dtos.ParseDocumentResult{}, err
+	}
+
+	dir, _ := outputPaths(req)
+	r := &htmlRenderer{
+		w:         newMarkdownWriter(dir),
+		sourceDir: filepath.Dir(req.FilePath),
+	}
+	if err := r.walk(ctx, doc); err != nil {
+		return dtos.ParseDocumentResult{}, err
+	}
+	return writeMarkdown(ParserHTML, req, r.w.String(), r.w.images)
+}
+
+type htmlRenderer struct {
+	w         *markdownWriter
+	sourceDir string
+}
+
+func (r *htmlRenderer) walk(ctx context.Context, n *html.Node) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	switch n.Type {
+	case html.TextNode:
+		r.w.text(n.Data)
+		return nil
+	case html.ElementNode:
+	default:
+		return r.walkChildren(ctx, n)
+	}
+
+	switch n.DataAtom {
+	case atom.Script, atom.Style, atom.Noscript, atom.Head, atom.Template, atom.Svg:
+		return nil
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		r.w.heading(int(n.Data[1]-'0'), nodeText(n))
+		return nil
+	case atom.Table:
+		r.w.table(tableRows(n))
+		return nil
+	case atom.Img:
+		return r.image(n)
+	case atom.Br:
+		r.w.flush()
+		return nil
+	case atom.Li:
+		r.w.flush()
+		r.w.text("- ")
+	}
+
+	if err := r.walkChildren(ctx, n); err != nil {
+		return err
+	}
+	if isHTMLBlock(n.DataAtom) {
+		r.w.flush()
+	}
+	return nil
+}
+
+func (r *htmlRenderer) walkChildren(ctx context.Context, n *html.Node) error {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if err := r.walk(ctx, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *htmlRenderer) image(n *html.Node) error {
+	src := strings.TrimSpace(htmlAttr(n, "src"))
+	if strings.HasPrefix(src, "data:") {
+		meta, payload, ok := strings.Cut(strings.TrimPrefix(src, "data:"), ",")
+		if !ok || !strings.HasSuffix(meta, ";base64") {
+			return nil
+		}
+		data, err := base64.StdEncoding.DecodeString(payload)
+		if err != nil {
+			return nil
+		}
+		return r.w.image(data, imageExtForMIME(strings.TrimSuffix(meta, ";base64")))
+	}
+
+	u, err := url.Parse(src)
+	if err != nil || src == "" || u.Scheme != "" || u.Host != "" || filepath.IsAbs(u.Path) {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(r.sourceDir, filepath.FromSlash(u.Path)))
+	if err != nil {
+		return nil
+	}
+	return r.w.image(data, strings.ToLower(filepath.Ext(u.Path)))
+}
+
+func isHTMLBlock(a atom.Atom) bool {
+	switch a {
+	case atom.P, atom.Div, atom.Section, atom.Article, atom.Main, atom.Header, atom.Footer,
+		atom.Aside, atom.Nav, atom.Blockquote, atom.Pre, atom.Ul, atom.Ol, atom.Li,
+		atom.Dl, atom.Dt, atom.Dd, atom.Figure, atom.Figcaption, atom.Body, atom.Hr:
+		return true
+	}
+	return false
+}
+
+func tableRows(table *html.Node) [][]string {
+	rows := [][]string{}
+	var visit func(*html.Node)
+	visit = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.DataAtom == atom.Tr {
+			row := []string{}
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				if c.Type == html.ElementNode && (c.DataAtom == atom.Td || c.DataAtom == atom.Th) {
+					row = append(row, nodeText(c))
+				}
+			}
+			if len(row) > 0 {
+				rows = append(rows, row)
+			}
+			return
+		}
+		if n.Type == html.ElementNode && n.DataAtom == atom.Table && n != table {
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			visit(c)
+		}
+	}
+	visit(table)
+	return rows
+}
+
+func nodeText(n *html.Node) string {
+	var b strings.Builder
+	var visit func(*html.Node)
+	visit = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+			return
+		}
+		if n.Type == html.ElementNode && (n.DataAtom == atom.Script || n.DataAtom == atom.Style) {
+			return
+		}
+		if n.Type == html.ElementNode && n.DataAtom == atom.Br {
+			b.WriteString(" ")
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			visit(c)
+		}
+	}
+	visit(n)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+func htmlAttr(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if strings.EqualFold(attr.Key, key) {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+func imageExtForMIME(mime string) string {
+	switch strings.ToLower(strings.TrimSpace(mime)) {
+	case "image/jpeg", "image/jpg":
+		return ".jpg"
+	case "image/gif":
+		return ".gif"
+	case "image/webp":
+		return ".webp"
+	}
+	return ".png"
+}
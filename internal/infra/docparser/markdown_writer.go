@@ -0,0 +1,101 @@
+package docparser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// markdownWriter collects blocks for the native parsers. Inline text is
+// buffered into the current paragraph and written out by flush.
+type markdownWriter struct {
+	blocks    []string
+	paragraph strings.Builder
+	imageDir  string
+	images    []string
+}
+
+func newMarkdownWriter(imageDir string) *markdownWriter {
+	return &markdownWriter{imageDir: imageDir}
+}
+
+func (w *markdownWriter) text(s string) {
+	w.paragraph.WriteString(s)
+}
+
+func (w *markdownWriter) flush() {
+	text := strings.Join(strings.Fields(w.paragraph.String()), " ")
+	w.paragraph.Reset()
+	if text != "" {
+		w.blocks = append(w.blocks, text)
+	}
+}
+
+func (w *markdownWriter) block(s string) {
+	w.flush()
+	if s = strings.TrimSpace(s); s != "" {
+		w.blocks = append(w.blocks, s)
+	}
+}
+
+func (w *markdownWriter) heading(level int, text string) {
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
+		return
+	}
+	level = min(max(level, 1), 6)
+	w.block(strings.Repeat("#", level) + " " + text)
+}
+
+// table writes rows as a pipe table; the first row is the header.
+func (w *markdownWriter) table(rows [][]string) {
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row))
+	}
+	if width == 0 {
+		return
+	}
+	lines := make([]string, 0, len(rows)+1)
+	for i, row := range rows {
+		cells := make([]string, width)
+		for j := range cells {
+			if j < len(row) {
+				cells[j] = strings.ReplaceAll(strings.Join(strings.Fields(row[j]), " "), "|", `\|`)
+			}
+		}
+		lines = append(lines, "| "+strings.Join(cells, " | ")+" |")
+		if i == 0 {
+			lines = append(lines, "|"+strings.Repeat(" --- |", width))
+		}
+	}
+	w.block(strings.Join(lines, "\n"))
+}
+
+// image stores data under the image directory and links it in its own block.
+func (w *markdownWriter) image(data []byte, ext string) error {
+	if len(data) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(w.imageDir, 0755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("_image_%d%s", len(w.images), ext)
+	path := filepath.Join(w.imageDir, name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	w.images = append(w.images, path)
+	w.block(fmt.Sprintf("![](%s)", name))
+	return nil
+}
+
+func (w *markdownWriter) pageBreak(pageIndex int) {
+	w.block(pageSeparator(pageIndex))
+}
+
+func (w *markdownWriter) String() string {
+	w.flush()
+	return strings.Join(w.blocks, "\n\n")
+}
@@ -0,0 +1,87 @@
+package docparser
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"rag_imagetotext_texttoimage/internal/application/dtos"
+	"rag_imagetotext_texttoimage/internal/util"
+)
+
+// markerScript is embedded so the binary does not need the source tree at
+// run time; it is fed to bash on stdin.
+//
+//go:embed marker_single_file.sh
+var markerScript []byte
+
+type MarkerParser struct {
+	logger util.Logger
+}
+
+func NewMarkerParser(logger util.Logger) *MarkerParser {
+	return &MarkerParser{logger: logger}
+}
+
+func (p *MarkerParser) Name() string {
+	return ParserMarker
+}
+
+func (p *MarkerParser) Parse(ctx context.Context, req dtos.ParseDocumentRequest) (dtos.ParseDocumentResult, error) {
+	if err := validateRequest(req); err != nil {
+		return dtos.ParseDocumentResult{}, err
+	}
+
+	cmd := exec.CommandContext(
+		ctx,
+		"bash",
+		"-s",
+		"--",
+		"-pathfilesrc", req.FilePath,
+		"-destdir", req.OutputDir,
+		"-dev", strconv.FormatBool(req.Dev),
+	)
+	cmd.Stdin = bytes.NewReader(markerScript)
+	cmd.Env = os.Environ()
+
+	output, err := cmd.CombinedOutput()
+	if outputText := strings.TrimSpace(string(output)); outputText != "" {
+		p.logger.Info("internal.infra.docparser.MarkerParser.Parse script output", "output", outputText)
+	}
+	if err != nil {
+		wrappedErr := fmt.Errorf("marker script execution failed: %w", err)
+		p.logger.Error(
+			"internal.infra.docparser.MarkerParser.Parse script execution failed",
+			wrappedErr,
+			"file_path", req.FilePath,
+			"dest_dir", req.OutputDir,
+			"dev", req.Dev,
+		)
+		return dtos.ParseDocumentResult{}, wrappedErr
+	}
+
+	result := dtos.ParseDocumentResult{Parser: ParserMarker}
+	dir, markdownPath := outputPaths(req)
+	if _, err := os.Stat(markdownPath); err == nil {
+		result.MarkdownPath = markdownPath
+	}
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".png", ".jpg", ".jpeg", ".gif", ".webp":
+			result.ImagePaths = append(result.ImagePaths, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return result, nil
+}
+
+func markerInstalled() bool {
+	_, err := exec.LookPath("marker_single")
+	return err == nil
+}
@@ -130,9 +130,9 @@ echo "[INFO] Source: $SRC_ABS"
 echo "[INFO] Dest  : $OUT_DIR"
 
 if [[ "$MARKER_OUTPUT_FLAG" == "--output_dir" ]]; then
-  marker_single "$SRC_ABS" --output_dir "$OUT_DIR"
+  marker_single "$SRC_ABS" --output_dir "$OUT_DIR" --paginate_output
 else
-  marker_single "$SRC_ABS" --tput_dir "$OUT_DIR"
+  marker_single "$SRC_ABS" --tput_dir "$OUT_DIR" --paginate_output
 fi
 
 echo "[DONE] Processed successfully"
@@ -0,0 +1,164 @@
+package docparser
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"rag_imagetotext_texttoimage/internal/application/dtos"
+	"rag_imagetotext_texttoimage/internal/application/ports"
+	"rag_imagetotext_texttoimage/internal/util"
+)
+
+const (
+	ParserMarker   = "marker"
+	ParserMarkdown = "markdown"
+	ParserText     = "text"
+	ParserHTML     = "html"
+	ParserDocx     = "docx"
+	ParserPDFText  = "pdf_text"
+	parserAuto     = "auto"
+)
+
+// Router is the DocumentParser used by the ingest pipeline: it picks one of
+// the registered parsers from the file extension and the configuration.
+type Router struct {
+	logger   util.Logger
+	settings util.DocumentParserSettings
+	parsers  map[string]ports.DocumentParser
+}
+
+func NewDocumentParser(logger util.Logger, settings util.DocumentParserSettings) ports.DocumentParser {
+	parsers := []ports.DocumentParser{
+		NewMarkerParser(logger),
+		NewMarkdownParser(),
+		NewTextParser(),
+		NewHTMLParser(),
+		NewDocxParser(),
+		NewPDFTextParser(),
+	}
+	router := &Router{
+		logger:   logger,
+		settings: settings,
+		parsers:  make(map[string]ports.DocumentParser, len(parsers)),
+	}
+	for _, parser := range parsers {
+		router.parsers[parser.Name()] = parser
+	}
+	return router
+}
+
+func (r *Router) Name() string {
+	return "router"
+}
+
+func (r *Router) Parse(ctx context.Context, req dtos.ParseDocumentRequest) (dtos.ParseDocumentResult, error) {
+	name := r.resolve(req.FilePath)
+	parser, ok := r.parsers[name]
+	if !ok {
+		return dtos.ParseDocumentResult{}, fmt.Errorf("unknown document parser %q for %s", name, filepath.Base(req.FilePath))
+	}
+	r.logger.Info(
+		"internal.infra.docparser.Router.Parse parser selected",
+		"parser", name,
+		"file_path", req.FilePath,
+	)
+	result, err := parser.Parse(ctx, req)
+	if result.Parser == "" {
+		result.Parser = name
+	}
+	return result, err
+}
+
+// resolve applies, in order: the per-extension override, the native parser
+// of the extension, the pdf setting and finally the default parser.
+func (r *Router) resolve(filePath string) string {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	if name := normalizeParserName(r.settings.Extensions[ext]); name != "" {
+		return r.autoPDF(name)
+	}
+	switch ext {
+	case ".md", ".markdown":
+		return ParserMarkdown
+	case ".txt":
+		return ParserText
+	case ".html", ".htm":
+		return ParserHTML
+	case ".docx":
+		return ParserDocx
+	case ".pdf":
+		if name := normalizeParserName(r.settings.PDF); name != "" {
+			return r.autoPDF(name)
+		}
+		return ParserMarker
+	}
+	if name := normalizeParserName(r.settings.Default); name != "" {
+		return name
+	}
+	return ParserMarker
+}
+
+func (r *Router) autoPDF(name string) string {
+	if name != parserAuto {
+		return name
+	}
+	if markerInstalled() {
+		return ParserMarker
+	}
+	return ParserPDFText
+}
+
+func normalizeParserName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// outputPaths returns the directory and markdown path a parser writes to,
+// the same <output_dir>/<name>/<name>.md layout marker produces.
+func outputPaths(req dtos.ParseDocumentRequest) (string, string) {
+	base := filepath.Base(req.FilePath)
+	name := strings.TrimSuffix(base, filepath.Ext(base))
+	dir := filepath.Join(req.OutputDir, name)
+	return dir, filepath.Join(dir, name+".md")
+}
+
+func validateRequest(req dtos.ParseDocumentRequest) error {
+	if strings.TrimSpace(req.FilePath) == "" {
+		return fmt.Errorf("file_path is required")
+	}
+	if strings.TrimSpace(req.OutputDir) == "" {
+		return fmt.Errorf("output_dir is required")
+	}
+	info, err := os.Stat(req.FilePath)
+	if err != nil {
+		return fmt.Errorf("source file: %w", err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("source file %s is a directory", req.FilePath)
+	}
+	return nil
+}
+
+// writeMarkdown writes the rendered markdown for a native parser and returns
+// the result pointing at it.
+func writeMarkdown(parser string, req dtos.ParseDocumentRequest, markdown string, imagePaths []string) (dtos.ParseDocumentResult, error) {
+	dir, markdownPath := outputPaths(req)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return dtos.ParseDocumentResult{}, err
+	}
+	if err := os.WriteFile(markdownPath, []byte(strings.TrimSpace(markdown)+"\n"), 0644); err != nil {
+		return dtos.ParseDocumentResult{}, err
+	}
+	return dtos.ParseDocumentResult{
+		Parser:       parser,
+		MarkdownPath: markdownPath,
+		ImagePaths:   imagePaths,
+	}, nil
+}
+
+// pageSeparator is the separator marker writes with --paginate_output, so
+// every parser's pages are read the same way by the markdown stage.
+func pageSeparator(pageIndex int) string {
+	return fmt.Sprintf("{%d}%s", pageIndex, strings.Repeat("-", 48))
+}
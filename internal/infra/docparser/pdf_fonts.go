@@ -0,0 +1,264 @@
+package docparser
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// pdfFont decodes the bytes of a shown string to text: through the ToUnicode
+// CMap when the font has one, otherwise through the simple-font encoding.
+// Composite fonts without ToUnicode cannot be decoded and yield no text.
+type pdfFont struct {
+	toUnicode map[string]string
+	codeLen   int
+	simple    bool
+	encoding  [256]rune
+}
+
+func (d *pdfDocument) loadFont(v any) *pdfFont {
+	dict := d.dict(v)
+	font := &pdfFont{codeLen: 1, simple: true, encoding: winAnsiEncoding()}
+	if dict == nil {
+		return font
+	}
+	if subtype, _ := d.resolve(dict["Subtype"]).(pdfName); subtype == "Type0" {
+		font.simple = false
+		font.codeLen = 2
+	}
+
+	switch enc := d.resolve(dict["Encoding"]).(type) {
+	case pdfName:
+		font.applyBaseEncoding(enc)
+	case pdfDict:
+		if base, ok := d.resolve(enc["BaseEncoding"]).(pdfName); ok {
+			font.applyBaseEncoding(base)
+		}
+		if diffs, ok := d.resolve(enc["Differences"]).(pdfArray); ok {
+			code := 0
+			for _, item := range diffs {
+				switch t := d.resolve(item).(type) {
+				case float64:
+					code = int(t)
+				case pdfName:
+					if code >= 0 && code < 256 {
+						if r, ok := glyphNameToRune(string(t)); ok {
+							font.encoding[code] = r
+						}
+					}
+					code++
+				}
+			}
+		}
+	}
+
+	if stream := d.stream(dict["ToUnicode"]); stream != nil {
+		if data, err := d.decodeStream(stream); err == nil {
+			font.toUnicode, font.codeLen = parseToUnicodeCMap(data, font.codeLen)
+		}
+	}
+	return font
+}
+
+func (f *pdfFont) applyBaseEncoding(name pdfName) {
+	switch name {
+	case "MacRomanEncoding", "StandardEncoding":
+		for i := 0; i < 256; i++ {
+			f.encoding[i] = rune(i)
+		}
+	}
+}
+
+func (f *pdfFont) decode(s []byte) string {
+	var b strings.Builder
+	if len(f.toUnicode) > 0 {
+		for i := 0; i < len(s); {
+			n := min(f.codeLen, len(s)-i)
+			if text, ok := f.toUnicode[string(s[i:i+n])]; ok {
+				b.WriteString(text)
+			} else if f.simple {
+				if r := f.encoding[s[i]]; r != 0 {
+					b.WriteRune(r)
+				}
+				n = 1
+			}
+			i += n
+		}
+		return b.String()
+	}
+	if !f.simple {
+		return ""
+	}
+	for _, c := range s {
+		if r := f.encoding[c]; r != 0 {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// parseToUnicodeCMap reads bfchar and bfrange mappings. The code length comes
+// from the first codespacerange; defaultLen is used when there is none.
+func parseToUnicodeCMap(data []byte, defaultLen int) (map[string]string, int) {
+	out := map[string]string{}
+	codeLen := defaultLen
+	lex := &pdfLexer{data: data}
+	operands := []any{}
+	mode := ""
+	for {
+		obj, err := lex.object()
+		if err != nil {
+			break
+		}
+		kw, isKeyword := obj.(pdfKeyword)
+		if !isKeyword {
+			if mode != "" {
+				operands = append(operands, obj)
+			}
+			continue
+		}
+		switch kw {
+		case "begincodespacerange", "beginbfchar", "beginbfrange":
+			mode = string(kw)
+			operands = operands[:0]
+		case "endcodespacerange":
+			if len(operands) > 0 {
+				if lo, ok := operands[0].(pdfString); ok && len(lo) > 0 {
+					codeLen = len(lo)
+				}
+			}
+			mode = ""
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				src, ok1 := operands[i].(pdfString)
+				dst, ok2 := operands[i+1].(pdfString)
+				if ok1 && ok2 {
+					out[string(src)] = decodeUTF16BE(dst)
+				}
+			}
+			mode = ""
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				lo, ok1 := operands[i].(pdfString)
+				hi, ok2 := operands[i+1].(pdfString)
+				if !ok1 || !ok2 || len(lo) != len(hi) || len(lo) == 0 {
+					continue
+				}
+				start, end := bytesToInt(lo), bytesToInt(hi)
+				if end < start || end-start > 0xFFFF {
+					continue
+				}
+				switch dst := operands[i+2].(type) {
+				case pdfString:
+					base := []rune(decodeUTF16BE(dst))
+					if len(base) == 0 {
+						continue
+					}
+					for code := start; code <= end; code++ {
+						mapped := append([]rune{}, base...)
+						mapped[len(mapped)-1] += rune(code - start)
+						out[string(intToBytes(code, len(lo)))] = string(mapped)
+					}
+				case pdfArray:
+					for j, item := range dst {
+						if s, ok := item.(pdfString); ok && start+j <= end {
+							out[string(intToBytes(start+j, len(lo)))] = decodeUTF16BE(s)
+						}
+					}
+				}
+			}
+			mode = ""
+		}
+	}
+	return out, codeLen
+}
+
+func decodeUTF16BE(b []byte) string {
+	if len(b)%2 == 1 {
+		b = append(b, 0)
+	}
+	units := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+	}
+	return string(utf16.Decode(units))
+}
+
+func bytesToInt(b []byte) int {
+	v := 0
+	for _, c := range b {
+		v = v<<8 | int(c)
+	}
+	return v
+}
+
+func intToBytes(v int, n int) []byte {
+	out := make([]byte, n)
+	for i := n - 1; i >= 0; i-- {
+		out[i] = byte(v)
+		v >>= 8
+	}
+	return out
+}
+
+// winAnsiEncoding is Latin-1 with the Windows-1252 characters in 0x80-0x9F.
+func winAnsiEncoding() [256]rune {
+	var enc [256]rune
+	for i := 32; i < 256; i++ {
+		enc[i] = rune(i)
+	}
+	enc['\t'], enc['\n'], enc['\r'] = ' ', ' ', ' '
+	high := []rune{
+		'€', 0, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0, 'Ž', 0,
+		0, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0, 'ž', 'Ÿ',
+	}
+	for i, r := range high {
+		enc[0x80+i] = r
+	}
+	return enc
+}
+
+var glyphNames = map[string]rune{
+	"space": ' ', "exclam": '!', "quotedbl": '"', "numbersign": '#', "dollar": '$',
+	"percent": '%', "ampersand": '&', "quotesingle": '\'', "parenleft": '(', "parenright": ')',
+	"asterisk": '*', "plus": '+', "comma": ',', "hyphen": '-', "period": '.', "slash": '/',
+	"zero": '0', "one": '1', "two": '2', "three": '3', "four": '4', "five": '5', "six": '6',
+	"seven": '7', "eight": '8', "nine": '9', "colon": ':', "semicolon": ';', "less": '<',
+	"equal": '=', "greater": '>', "question": '?', "at": '@', "bracketleft": '[',
+	"backslash": '\\', "bracketright": ']', "underscore": '_', "braceleft": '{', "bar": '|',
+	"braceright": '}', "quoteleft": '‘', "quoteright": '’', "quotedblleft": '“',
+	"quotedblright": '”', "endash": '–', "emdash": '—', "bullet": '•', "ellipsis": '…',
+	"fi": 'ﬁ', "fl": 'ﬂ', "degree": '°', "copyright": '©', "registered": '®',
+}
+
+func glyphNameToRune(name string) (rune, bool) {
+	if r, ok := glyphNames[name]; ok {
+		return r, true
+	}
+	if len(name) == 1 {
+		return rune(name[0]), true
+	}
+	for _, prefix := range []string{"uni", "u"} {
+		if hexPart, ok := strings.CutPrefix(name, prefix); ok && len(hexPart) >= 4 {
+			if v, err := strconv.ParseUint(hexPart[:4], 16, 32); err == nil {
+				return rune(v), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// isLikelyText filters out decoded runs that are mostly control characters,
+// which is what an undecodable font usually produces.
+func isLikelyText(s string) bool {
+	if s == "" {
+		return false
+	}
+	bad := 0
+	for _, r := range s {
+		if (r < 32 && r != '\t') || r == '\ufffd' {
+			bad++
+		}
+	}
+	return bad*4 < len([]rune(s))
+}
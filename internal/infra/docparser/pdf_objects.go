@@ -0,0 +1,543 @@
+package docparser
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/ascii85"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+// The PDF reader only goes as far as text extraction needs: it finds objects
+// by scanning for "n g obj" instead of trusting the xref table, unpacks
+// object streams, and decodes Flate, ASCIIHex and ASCII85 streams.
+
+type pdfName string
+
+type pdfKeyword string
+
+type pdfRef struct {
+	Num int
+	Gen int
+}
+
+type pdfDict map[pdfName]any
+
+type pdfArray []any
+
+type pdfString []byte
+
+type pdfStream struct {
+	Dict pdfDict
+	Raw  []byte
+}
+
+var pdfObjectHeaderPattern = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+var pdfTrailerPattern = regexp.MustCompile(`trailer\s*<<`)
+
+type pdfDocument struct {
+	objects map[int]any
+	root    any
+}
+
+func parsePDFDocument(data []byte) (*pdfDocument, error) {
+	if !bytes.HasPrefix(bytes.TrimLeft(data, "\x00\t\n\r "), []byte("%PDF")) {
+		return nil, errors.New("not a pdf file")
+	}
+	doc := &pdfDocument{objects: map[int]any{}}
+	for _, loc := range pdfObjectHeaderPattern.FindAllSubmatchIndex(data, -1) {
+		num, err := strconv.Atoi(string(data[loc[2]:loc[3]]))
+		if err != nil {
+			continue
+		}
+		lex := &pdfLexer{data: data, pos: loc[1]}
+		obj, err := lex.object()
+		if err != nil {
+			continue
+		}
+		if dict, ok := obj.(pdfDict); ok {
+			if raw, ok := lex.streamData(dict); ok {
+				obj = &pdfStream{Dict: dict, Raw: raw}
+			}
+		}
+		doc.objects[num] = obj
+	}
+	if len(doc.objects) == 0 {
+		return nil, errors.New("no objects found in pdf")
+	}
+	trailers := []pdfDict{}
+	for _, loc := range pdfTrailerPattern.FindAllIndex(data, -1) {
+		lex := &pdfLexer{data: data, pos: loc[1]}
+		if dict, err := lex.dictionary(); err == nil {
+			trailers = append(trailers, dict)
+		}
+	}
+	for _, obj := range doc.objects {
+		if stream, ok := obj.(*pdfStream); ok && stream.Dict["Type"] == pdfName("XRef") {
+			trailers = append(trailers, stream.Dict)
+		}
+	}
+	for _, trailer := range trailers {
+		if trailer["Encrypt"] != nil {
+			return nil, errors.New("encrypted pdf is not supported")
+		}
+		if trailer["Root"] != nil {
+			doc.root = trailer["Root"]
+		}
+	}
+	doc.expandObjectStreams()
+	return doc, nil
+}
+
+// expandObjectStreams adds the objects packed in /Type /ObjStm streams.
+// Objects written directly in the file take precedence.
+func (d *pdfDocument) expandObjectStreams() {
+	streams := []*pdfStream{}
+	for _, obj := range d.objects {
+		if stream, ok := obj.(*pdfStream); ok && stream.Dict["Type"] == pdfName("ObjStm") {
+			streams = append(streams, stream)
+		}
+	}
+	for _, stream := range streams {
+		data, err := d.decodeStream(stream)
+		if err != nil {
+			continue
+		}
+		count, _ := d.resolve(stream.Dict["N"]).(float64)
+		first, _ := d.resolve(stream.Dict["First"]).(float64)
+		if !(count >= 0 && first >= 0 && first < float64(len(data))) {
+			continue
+		}
+		header := &pdfLexer{data: data}
+		for i := 0; i < int(min(count, float64(len(data)))); i++ {
+			numObj, err1 := header.object()
+			offObj, err2 := header.object()
+			num, ok1 := numObj.(float64)
+			off, ok2 := offObj.(float64)
+			if err1 != nil || err2 != nil || !ok1 || !ok2 {
+				break
+			}
+			if !(off >= 0 && first+off < float64(len(data))) {
+				continue
+			}
+			if _, exists := d.objects[int(num)]; exists {
+				continue
+			}
+			lex := &pdfLexer{data: data, pos: int(first + off)}
+			if obj, err := lex.object(); err == nil {
+				d.objects[int(num)] = obj
+			}
+		}
+	}
+}
+
+// resolve follows indirect references; a missing object resolves to nil.
+func (d *pdfDocument) resolve(v any) any {
+	for depth := 0; depth < 32; depth++ {
+		ref, ok := v.(pdfRef)
+		if !ok {
+			return v
+		}
+		v = d.objects[ref.Num]
+	}
+	return nil
+}
+
+func (d *pdfDocument) dict(v any) pdfDict {
+	switch t := d.resolve(v).(type) {
+	case pdfDict:
+		return t
+	case *pdfStream:
+		return t.Dict
+	}
+	return nil
+}
+
+func (d *pdfDocument) stream(v any) *pdfStream {
+	stream, _ := d.resolve(v).(*pdfStream)
+	return stream
+}
+
+func (d *pdfDocument) decodeStream(stream *pdfStream) ([]byte, error) {
+	data := stream.Raw
+	filters := []any{}
+	switch f := d.resolve(stream.Dict["Filter"]).(type) {
+	case pdfName:
+		filters = append(filters, f)
+	case pdfArray:
+		filters = append(filters, f...)
+	}
+	for _, f := range filters {
+		name, _ := d.resolve(f).(pdfName)
+		var err error
+		switch name {
+		case "FlateDecode", "Fl":
+			data, err = inflatePDF(data)
+		case "ASCIIHexDecode", "AHx":
+			data, err = decodePDFHex(data)
+		case "ASCII85Decode", "A85":
+			data, err = decodePDFASCII85(data)
+		default:
+			return nil, fmt.Errorf("unsupported pdf filter %s", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// inflatePDF tolerates truncated streams, which are common in the wild.
+func inflatePDF(data []byte) ([]byte, error) {
+	reader, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	out, err := io.ReadAll(reader)
+	if err != nil && len(out) == 0 {
+		return nil, err
+	}
+	return out, nil
+}
+
+func decodePDFHex(data []byte) ([]byte, error) {
+	clean := make([]byte, 0, len(data))
+	for _, c := range data {
+		if c == '>' {
+			break
+		}
+		if isPDFWhitespace(c) {
+			continue
+		}
+		clean = append(clean, c)
+	}
+	if len(clean)%2 == 1 {
+		clean = append(clean, '0')
+	}
+	out := make([]byte, len(clean)/2)
+	_, err := hex.Decode(out, clean)
+	return out, err
+}
+
+func decodePDFASCII85(data []byte) ([]byte, error) {
+	data = bytes.TrimSpace(data)
+	data = bytes.TrimPrefix(data, []byte("<~"))
+	if i := bytes.Index(data, []byte("~>")); i >= 0 {
+		data = data[:i]
+	}
+	out := make([]byte, len(data))
+	n, _, err := ascii85.Decode(out, data, true)
+	return out[:n], err
+}
+
+// pdfMaxNesting bounds how deep arrays and dictionaries may nest, so a file
+// of "[[[[..." cannot exhaust the stack.
+const pdfMaxNesting = 256
+
+// pdfLexer reads objects from data starting at pos. A position outside data,
+// from a bad offset in the file, reads as the end of the data.
+type pdfLexer struct {
+	data  []byte
+	pos   int
+	depth int
+}
+
+func isPDFWhitespace(c byte) bool {
+	switch c {
+	case 0, '\t', '\n', '\f', '\r', ' ':
+		return true
+	}
+	return false
+}
+
+func isPDFDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+func (l *pdfLexer) skipSpace() {
+	if l.pos < 0 || l.pos > len(l.data) {
+		l.pos = len(l.data)
+	}
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if isPDFWhitespace(c) {
+			l.pos++
+			continue
+		}
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		return
+	}
+}
+
+var (
+	errPDFEnd     = errors.New("unexpected end of pdf data")
+	errPDFTooDeep = errors.New("pdf objects nested too deeply")
+)
+
+// object reads the next object; keywords (operators in content streams,
+// "endobj", "stream") come back as pdfKeyword, and "]" and ">>" as the
+// keywords of the same text so callers can close containers.
+func (l *pdfLexer) object() (any, error) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return nil, errPDFEnd
+	}
+	c := l.data[l.pos]
+	switch {
+	case c == '/':
+		return l.name(), nil
+	case c == '(':
+		return l.literalString(), nil
+	case c == '<' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '<':
+		l.pos += 2
+		return l.nested(func() (any, error) { return l.dictionary() })
+	case c == '<':
+		return l.hexString(), nil
+	case c == '>' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '>':
+		l.pos += 2
+		return pdfKeyword(">>"), nil
+	case c == '[':
+		l.pos++
+		return l.nested(func() (any, error) { return l.array() })
+	case c == ']':
+		l.pos++
+		return pdfKeyword("]"), nil
+	case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		return l.number()
+	}
+
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFWhitespace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	if l.pos == start {
+		l.pos++
+		return pdfKeyword(string(c)), nil
+	}
+	word := string(l.data[start:l.pos])
+	switch word {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	return pdfKeyword(word), nil
+}
+
+func (l *pdfLexer) nested(read func() (any, error)) (any, error) {
+	if l.depth >= pdfMaxNesting {
+		return nil, errPDFTooDeep
+	}
+	l.depth++
+	defer func() { l.depth-- }()
+	return read()
+}
+
+func (l *pdfLexer) name() pdfName {
+	l.pos++
+	var out []byte
+	for l.pos < len(l.data) && !isPDFWhitespace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+		c := l.data[l.pos]
+		if c == '#' && l.pos+2 < len(l.data) {
+			if v, err := strconv.ParseUint(string(l.data[l.pos+1:l.pos+3]), 16, 8); err == nil {
+				out = append(out, byte(v))
+				l.pos += 3
+				continue
+			}
+		}
+		out = append(out, c)
+		l.pos++
+	}
+	return pdfName(out)
+}
+
+func (l *pdfLexer) literalString() pdfString {
+	l.pos++
+	depth := 1
+	var out []byte
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return out
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				return out
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				out = append(out, '\n')
+			case 'r':
+				out = append(out, '\r')
+			case 't':
+				out = append(out, '\t')
+			case 'b':
+				out = append(out, '\b')
+			case 'f':
+				out = append(out, '\f')
+			case '\r':
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+			case '\n':
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					out = append(out, byte(v))
+				} else {
+					out = append(out, e)
+				}
+			}
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+func (l *pdfLexer) hexString() pdfString {
+	l.pos++
+	end := bytes.IndexByte(l.data[l.pos:], '>')
+	if end < 0 {
+		end = len(l.data) - l.pos
+	}
+	raw := l.data[l.pos : l.pos+end]
+	l.pos = min(l.pos+end+1, len(l.data))
+	out, _ := decodePDFHex(raw)
+	return out
+}
+
+func (l *pdfLexer) number() (any, error) {
+	start := l.pos
+	l.pos++
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if (c >= '0' && c <= '9') || c == '.' {
+			l.pos++
+			continue
+		}
+		break
+	}
+	v, err := strconv.ParseFloat(string(l.data[start:l.pos]), 64)
+	if err != nil {
+		return pdfKeyword(string(l.data[start:l.pos])), nil
+	}
+
+	// "<num> <gen> R" is an indirect reference.
+	save := l.pos
+	l.skipSpace()
+	genStart := l.pos
+	for l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '9' {
+		l.pos++
+	}
+	if l.pos > genStart {
+		gen, _ := strconv.Atoi(string(l.data[genStart:l.pos]))
+		l.skipSpace()
+		if l.pos < len(l.data) && l.data[l.pos] == 'R' && (l.pos+1 == len(l.data) || isPDFWhitespace(l.data[l.pos+1]) || isPDFDelimiter(l.data[l.pos+1])) {
+			l.pos++
+			return pdfRef{Num: int(v), Gen: gen}, nil
+		}
+	}
+	l.pos = save
+	return v, nil
+}
+
+func (l *pdfLexer) array() (pdfArray, error) {
+	out := pdfArray{}
+	for {
+		obj, err := l.object()
+		if err != nil {
+			return out, err
+		}
+		if kw, ok := obj.(pdfKeyword); ok && kw == "]" {
+			return out, nil
+		}
+		out = append(out, obj)
+	}
+}
+
+func (l *pdfLexer) dictionary() (pdfDict, error) {
+	out := pdfDict{}
+	for {
+		key, err := l.object()
+		if err != nil {
+			return out, err
+		}
+		if kw, ok := key.(pdfKeyword); ok && kw == ">>" {
+			return out, nil
+		}
+		name, ok := key.(pdfName)
+		if !ok {
+			continue
+		}
+		value, err := l.object()
+		if err != nil {
+			return out, err
+		}
+		if kw, ok := value.(pdfKeyword); ok && kw == ">>" {
+			return out, nil
+		}
+		out[name] = value
+	}
+}
+
+// streamData returns the bytes between "stream" and "endstream" when the
+// dictionary just read is followed by a stream. /Length is used when it is a
+// direct number that lands on "endstream"; otherwise the keyword is searched.
+func (l *pdfLexer) streamData(dict pdfDict) ([]byte, bool) {
+	l.skipSpace()
+	if !bytes.HasPrefix(l.data[l.pos:], []byte("stream")) {
+		return nil, false
+	}
+	start := l.pos + len("stream")
+	if start < len(l.data) && l.data[start] == '\r' {
+		start++
+	}
+	if start < len(l.data) && l.data[start] == '\n' {
+		start++
+	}
+	if length, ok := dict["Length"].(float64); ok && length >= 0 && length <= float64(len(l.data)-start) {
+		end := start + int(length)
+		rest := bytes.TrimLeft(l.data[end:min(end+32, len(l.data))], "\r\n \t")
+		if bytes.HasPrefix(rest, []byte("endstream")) {
+			l.pos = end
+			return l.data[start:end], true
+		}
+	}
+	end := bytes.Index(l.data[start:], []byte("endstream"))
+	if end < 0 {
+		return nil, false
+	}
+	raw := bytes.TrimRight(l.data[start:start+end], "\r\n")
+	l.pos = start + end
+	return raw, true
+}
@@ -0,0 +1,139 @@
+package docparser
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// buildTestPDF writes a one-page PDF whose page and font objects sit in a
+// compressed object stream, which is what most current writers produce.
+func buildTestPDF(t testing.TB, objStmDict string) []byte {
+	t.Helper()
+	packed := []string{
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 4 0 R >> >> /Contents 5 0 R >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	}
+	var header, body strings.Builder
+	for i, obj := range packed {
+		fmt.Fprintf(&header, "%d %d ", i+2, body.Len())
+		body.WriteString(obj + "\n")
+	}
+	if objStmDict == "" {
+		objStmDict = fmt.Sprintf("/N %d /First %d", len(packed), header.Len())
+	}
+
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	_, _ = zw.Write([]byte(header.String() + body.String()))
+	_ = zw.Close()
+
+	content := "BT /F1 12 Tf 72 720 Td (Hello object streams.) Tj ET"
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.5\n")
+	out.WriteString("1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n")
+	fmt.Fprintf(&out, "5 0 obj\n<< /Length %d >>\nstream\n%s\nendstream\nendobj\n", len(content), content)
+	fmt.Fprintf(&out, "6 0 obj\n<< /Type /ObjStm %s /Filter /FlateDecode /Length %d >>\nstream\n", objStmDict, compressed.Len())
+	out.Write(compressed.Bytes())
+	out.WriteString("\nendstream\nendobj\n")
+	out.WriteString("trailer\n<< /Root 1 0 R /Size 7 >>\n%%EOF\n")
+	return out.Bytes()
+}
+
+// extractTestPDFText runs the reader the way PDFTextParser.Parse does,
+// without the recover, so a panic fails the test.
+func extractTestPDFText(data []byte) string {
+	doc, err := parsePDFDocument(data)
+	if err != nil {
+		return ""
+	}
+	var text strings.Builder
+	for _, page := range doc.pages() {
+		extractor := &pdfTextExtractor{doc: doc, fonts: map[string]*pdfFont{}}
+		extractor.run(page.contents(doc), page.resources, 0)
+		text.WriteString(extractor.text.String())
+	}
+	return text.String()
+}
+
+func TestParsePDFDocumentObjectStream(t *testing.T) {
+	text := extractTestPDFText(buildTestPDF(t, ""))
+	if !strings.Contains(text, "Hello object streams.") {
+		t.Fatalf("text = %q", text)
+	}
+}
+
+func TestParsePDFDocumentTruncated(t *testing.T) {
+	data := buildTestPDF(t, "")
+	for n := range len(data) {
+		extractTestPDFText(data[:n])
+	}
+}
+
+func TestExpandObjectStreamsBadOffsets(t *testing.T) {
+	for _, dict := range []string{
+		"/N 3 /First -1000",
+		"/N 3 /First 100000000",
+		"/N 1e30 /First 1e30",
+		"/N -5 /First 0",
+		"/N 3 /First -9223372036854775808",
+	} {
+		t.Run(dict, func(t *testing.T) {
+			extractTestPDFText(buildTestPDF(t, dict))
+		})
+	}
+
+	// Offsets inside the object stream header pointing before its start.
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	_, _ = zw.Write([]byte("2 -40 3 -1e300 4 99999999 << /Type /Pages >>"))
+	_ = zw.Close()
+	data := fmt.Appendf(nil, "%%PDF-1.5\n6 0 obj\n<< /Type /ObjStm /N 3 /First 27 /Filter /FlateDecode /Length %d >>\nstream\n%s\nendstream\nendobj\n", compressed.Len(), compressed.Bytes())
+	doc, err := parsePDFDocument(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, num := range []int{2, 3, 4} {
+		if _, ok := doc.objects[num]; ok {
+			t.Fatalf("object %d read from an out-of-range offset", num)
+		}
+	}
+}
+
+func TestPDFLexerBadPositions(t *testing.T) {
+	for _, pos := range []int{-1, -1 << 40, 10, 1 << 40} {
+		lex := &pdfLexer{data: []byte("<< /A 1 >>"), pos: pos}
+		if _, err := lex.object(); err != errPDFEnd {
+			t.Fatalf("pos %d: err = %v", pos, err)
+		}
+		lex.streamData(pdfDict{})
+	}
+
+	// A hex string at the very end used to leave pos past the data.
+	lex := &pdfLexer{data: []byte("<")}
+	lex.object()
+	if _, ok := lex.streamData(pdfDict{}); ok {
+		t.Fatal("stream read past the end")
+	}
+}
+
+func TestPDFLexerDeepNesting(t *testing.T) {
+	data := []byte(strings.Repeat("[", 1_000_000))
+	lex := &pdfLexer{data: data}
+	if _, err := lex.object(); err != errPDFTooDeep {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func FuzzParsePDFDocument(f *testing.F) {
+	f.Add(buildTestPDF(f, ""))
+	f.Add(buildTestPDF(f, "/N 3 /First -1000"))
+	f.Add([]byte("%PDF-1.4\n1 0 obj << /Length 1e30 >> stream\nendstream endobj"))
+	f.Add([]byte("%PDF-1.4\n1 0 obj <"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		extractTestPDFText(data)
+	})
+}
@@ -0,0 +1,414 @@
+package docparser
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"rag_imagetotext_texttoimage/internal/application/dtos"
+)
+
+const (
+	pdfMaxFormDepth     = 4
+	pdfMinImagePixels   = 100 * 100
+	pdfTJSpaceThreshold = -250
+)
+
+// PDFTextParser extracts the text layer of a PDF without marker: no OCR and
+// no layout analysis, so scanned pages come out empty. Pages are separated
+// the way marker's paginated output is, and JPEG images are extracted as is.
+type PDFTextParser struct{}
+
+func NewPDFTextParser() *PDFTextParser {
+	return &PDFTextParser{}
+}
+
+func (p *PDFTextParser) Name() string {
+	return ParserPDFText
+}
+
+// Parse recovers from a panic in the PDF reader and reports it as a parse
+// error, so one malformed upload cannot take the service down.
+func (p *PDFTextParser) Parse(ctx context.Context, req dtos.ParseDocumentRequest) (result dtos.ParseDocumentResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = dtos.ParseDocumentResult{}, fmt.Errorf("pdf parser failed on %s: %v", req.FilePath, r)
+		}
+	}()
+	if err := validateRequest(req); err != nil {
+		return dtos.ParseDocumentResult{}, err
+	}
+	data, err := os.ReadFile(req.FilePath)
+	if err != nil {
+		return dtos.ParseDocumentResult{}, err
+	}
+	doc, err := parsePDFDocument(data)
+	if err != nil {
+		return dtos.ParseDocumentResult{}, err
+	}
+	pages := doc.pages()
+	if len(pages) == 0 {
+		return dtos.ParseDocumentResult{}, errors.New("pdf has no pages")
+	}
+
+	dir, _ := outputPaths(req)
+	w := newMarkdownWriter(dir)
+	textFound := false
+	for i, page := range pages {
+		if err := ctx.Err(); err != nil {
+			return dtos.ParseDocumentResult{}, err
+		}
+		w.pageBreak(i)
+		extractor := &pdfTextExtractor{doc: doc, fonts: map[string]*pdfFont{}}
+		extractor.run(page.contents(doc), page.resources, 0)
+		for _, paragraph := range pdfParagraphs(extractor.text.String()) {
+			textFound = true
+			w.block(paragraph)
+		}
+		for _, img := range extractor.images {
+			_ = w.image(img, ".jpg")
+		}
+	}
+	if !textFound {
+		return dtos.ParseDocumentResult{}, errors.New("pdf has no extractable text layer")
+	}
+	return writeMarkdown(ParserPDFText, req, w.String(), w.images)
+}
+
+type pdfPage struct {
+	dict      pdfDict
+	resources pdfDict
+}
+
+// pages walks the page tree from the catalog, passing inherited resources
+// down. Without a usable catalog every /Type /Page object is taken in object
+// number order.
+func (d *pdfDocument) pages() []pdfPage {
+	out := []pdfPage{}
+	visited := map[any]bool{}
+	var walk func(node any, resources pdfDict)
+	walk = func(node any, resources pdfDict) {
+		if ref, ok := node.(pdfRef); ok {
+			if visited[ref] {
+				return
+			}
+			visited[ref] = true
+		}
+		dict := d.dict(node)
+		if dict == nil {
+			return
+		}
+		if res := d.dict(dict["Resources"]); res != nil {
+			resources = res
+		}
+		if kids, ok := d.resolve(dict["Kids"]).(pdfArray); ok {
+			for _, kid := range kids {
+				walk(kid, resources)
+			}
+			return
+		}
+		if t, _ := d.resolve(dict["Type"]).(pdfName); t == "Page" || dict["Contents"] != nil {
+			out = append(out, pdfPage{dict: dict, resources: resources})
+		}
+	}
+
+	catalog := d.dict(d.root)
+	if catalog == nil {
+		for _, obj := range d.objects {
+			if dict, ok := obj.(pdfDict); ok && dict["Type"] == pdfName("Catalog") {
+				catalog = dict
+				break
+			}
+		}
+	}
+	if catalog != nil {
+		walk(catalog["Pages"], nil)
+	}
+	if len(out) > 0 {
+		return out
+	}
+
+	nums := make([]int, 0, len(d.objects))
+	for num, obj := range d.objects {
+		if dict, ok := obj.(pdfDict); ok && dict["Type"] == pdfName("Page") {
+			nums = append(nums, num)
+		}
+	}
+	sort.Ints(nums)
+	for _, num := range nums {
+		dict := d.objects[num].(pdfDict)
+		out = append(out, pdfPage{dict: dict, resources: d.dict(dict["Resources"])})
+	}
+	return out
+}
+
+func (p pdfPage) contents(d *pdfDocument) []byte {
+	var parts []any
+	switch c := d.resolve(p.dict["Contents"]).(type) {
+	case *pdfStream:
+		parts = append(parts, c)
+	case pdfArray:
+		parts = append(parts, c...)
+	}
+	var buf bytes.Buffer
+	for _, part := range parts {
+		stream := d.stream(part)
+		if stream == nil {
+			continue
+		}
+		data, err := d.decodeStream(stream)
+		if err != nil {
+			continue
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+type pdfTextExtractor struct {
+	doc    *pdfDocument
+	fonts  map[string]*pdfFont
+	font   *pdfFont
+	text   strings.Builder
+	lastY  float64
+	hasY   bool
+	images [][]byte
+}
+
+func (e *pdfTextExtractor) newline() {
+	e.text.WriteString("\n")
+}
+
+func (e *pdfTextExtractor) space() {
+	e.text.WriteString(" ")
+}
+
+func (e *pdfTextExtractor) moveTo(y float64) {
+	if e.hasY && math.Abs(y-e.lastY) > 0.5 {
+		e.newline()
+	} else {
+		e.space()
+	}
+	e.lastY, e.hasY = y, true
+}
+
+func (e *pdfTextExtractor) show(s pdfString) {
+	if e.font == nil {
+		e.font = &pdfFont{codeLen: 1, simple: true, encoding: winAnsiEncoding()}
+	}
+	if text := e.font.decode(s); isLikelyText(text) {
+		e.text.WriteString(text)
+	}
+}
+
+// run interprets the text operators of a content stream; form XObjects are
+// followed up to pdfMaxFormDepth levels.
+func (e *pdfTextExtractor) run(content []byte, resources pdfDict, depth int) {
+	lex := &pdfLexer{data: content}
+	operands := []any{}
+	for {
+		obj, err := lex.object()
+		if err != nil {
+			return
+		}
+		op, ok := obj.(pdfKeyword)
+		if !ok {
+			operands = append(operands, obj)
+			continue
+		}
+
+		switch op {
+		case "BT":
+			e.hasY = false
+		case "Tf":
+			if len(operands) >= 1 {
+				if name, ok := operands[0].(pdfName); ok {
+					e.font = e.fontFor(resources, name)
+				}
+			}
+		case "Td", "TD":
+			if len(operands) >= 2 {
+				tx, _ := operands[0].(float64)
+				ty, _ := operands[1].(float64)
+				if ty != 0 {
+					e.newline()
+					e.lastY += ty
+				} else if tx > 0 {
+					e.space()
+				}
+			}
+		case "Tm":
+			if len(operands) >= 6 {
+				y, _ := operands[5].(float64)
+				e.moveTo(y)
+			}
+		case "T*":
+			e.newline()
+		case "Tj":
+			if len(operands) >= 1 {
+				if s, ok := operands[0].(pdfString); ok {
+					e.show(s)
+				}
+			}
+		case "'":
+			e.newline()
+			if len(operands) >= 1 {
+				if s, ok := operands[0].(pdfString); ok {
+					e.show(s)
+				}
+			}
+		case "\"":
+			e.newline()
+			if len(operands) >= 3 {
+				if s, ok := operands[2].(pdfString); ok {
+					e.show(s)
+				}
+			}
+		case "TJ":
+			if len(operands) >= 1 {
+				if items, ok := operands[0].(pdfArray); ok {
+					for _, item := range items {
+						switch t := item.(type) {
+						case pdfString:
+							e.show(t)
+						case float64:
+							if t < pdfTJSpaceThreshold {
+								e.space()
+							}
+						}
+					}
+				}
+			}
+		case "ET":
+			e.space()
+		case "Do":
+			if len(operands) >= 1 {
+				if name, ok := operands[0].(pdfName); ok {
+					e.xobject(resources, name, depth)
+				}
+			}
+		case "BI":
+			skipInlineImage(lex)
+		}
+		operands = operands[:0]
+	}
+}
+
+func (e *pdfTextExtractor) fontFor(resources pdfDict, name pdfName) *pdfFont {
+	fonts := e.doc.dict(resources["Font"])
+	if fonts == nil {
+		return nil
+	}
+	key := string(name)
+	if ref, ok := fonts[name].(pdfRef); ok {
+		key = string(name) + "@" + strconv.Itoa(ref.Num)
+	}
+	if font, ok := e.fonts[key]; ok {
+		return font
+	}
+	font := e.doc.loadFont(fonts[name])
+	e.fonts[key] = font
+	return font
+}
+
+func (e *pdfTextExtractor) xobject(resources pdfDict, name pdfName, depth int) {
+	xobjects := e.doc.dict(resources["XObject"])
+	if xobjects == nil {
+		return
+	}
+	stream := e.doc.stream(xobjects[name])
+	if stream == nil {
+		return
+	}
+	switch subtype, _ := e.doc.resolve(stream.Dict["Subtype"]).(pdfName); subtype {
+	case "Form":
+		if depth >= pdfMaxFormDepth {
+			return
+		}
+		data, err := e.doc.decodeStream(stream)
+		if err != nil {
+			return
+		}
+		formResources := e.doc.dict(stream.Dict["Resources"])
+		if formResources == nil {
+			formResources = resources
+		}
+		saved := e.font
+		e.run(data, formResources, depth+1)
+		e.font = saved
+	case "Image":
+		filter, _ := e.doc.resolve(stream.Dict["Filter"]).(pdfName)
+		width, _ := e.doc.resolve(stream.Dict["Width"]).(float64)
+		height, _ := e.doc.resolve(stream.Dict["Height"]).(float64)
+		if (filter == "DCTDecode" || filter == "DCT") && width*height >= pdfMinImagePixels {
+			e.images = append(e.images, stream.Raw)
+		}
+	}
+}
+
+// skipInlineImage moves past the binary data of BI ... ID <data> EI.
+func skipInlineImage(lex *pdfLexer) {
+	for {
+		obj, err := lex.object()
+		if err != nil {
+			return
+		}
+		if kw, ok := obj.(pdfKeyword); ok && kw == "ID" {
+			break
+		}
+	}
+	for i := lex.pos + 1; i+2 <= len(lex.data); i++ {
+		if lex.data[i] == 'E' && lex.data[i+1] == 'I' && isPDFWhitespace(lex.data[i-1]) &&
+			(i+2 == len(lex.data) || isPDFWhitespace(lex.data[i+2])) {
+			lex.pos = i + 2
+			return
+		}
+	}
+	lex.pos = len(lex.data)
+}
+
+// pdfParagraphs joins extracted lines into paragraphs: a line ending in
+// sentence punctuation closes a paragraph, and a word hyphenated across lines
+// is joined back.
+func pdfParagraphs(text string) []string {
+	out := []string{}
+	var current strings.Builder
+	flush := func() {
+		if p := strings.TrimSpace(current.String()); p != "" {
+			out = append(out, p)
+		}
+		current.Reset()
+	}
+	for _, raw := range strings.Split(text, "\n") {
+		line := strings.Join(strings.Fields(raw), " ")
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") || strings.HasPrefix(line, "|") {
+			line = `\` + line
+		}
+		prev := current.String()
+		switch {
+		case prev == "":
+			current.WriteString(line)
+		case strings.HasSuffix(prev, "-") && len(prev) > 1 && unicode.IsLetter(rune(prev[len(prev)-2])):
+			current.Reset()
+			current.WriteString(prev[:len(prev)-1] + line)
+		default:
+			current.WriteString(" " + line)
+		}
+		if strings.ContainsAny(line[len(line)-1:], ".!?:") {
+			flush()
+		}
+	}
+	flush()
+	return out
+}
@@ -0,0 +1,65 @@
+package docparser
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"rag_imagetotext_texttoimage/internal/application/dtos"
+)
+
+// MarkdownParser copies a markdown source as is; relative image links are
+// kept but the images themselves are not part of the download.
+type MarkdownParser struct{}
+
+func NewMarkdownParser() *MarkdownParser {
+	return &MarkdownParser{}
+}
+
+func (p *MarkdownParser) Name() string {
+	return ParserMarkdown
+}
+
+func (p *MarkdownParser) Parse(ctx context.Context, req dtos.ParseDocumentRequest) (dtos.ParseDocumentResult, error) {
+	if err := validateRequest(req); err != nil {
+		return dtos.ParseDocumentResult{}, err
+	}
+	raw, err := os.ReadFile(req.FilePath)
+	if err != nil {
+		return dtos.ParseDocumentResult{}, err
+	}
+	return writeMarkdown(ParserMarkdown, req, string(raw), nil)
+}
+
+// TextParser turns plain text into markdown paragraphs. Lines that would be
+// read as a heading or a table row are escaped.
+type TextParser struct{}
+
+func NewTextParser() *TextParser {
+	return &TextParser{}
+}
+
+func (p *TextParser) Name() string {
+	return ParserText
+}
+
+func (p *TextParser) Parse(ctx context.Context, req dtos.ParseDocumentRequest) (dtos.ParseDocumentResult, error) {
+	if err := validateRequest(req); err != nil {
+		return dtos.ParseDocumentResult{}, err
+	}
+	raw, err := os.ReadFile(req.FilePath)
+	if err != nil {
+		return dtos.ParseDocumentResult{}, err
+	}
+
+	text := strings.ReplaceAll(string(raw), "\r\n", "\n")
+	text = strings.TrimPrefix(text, "\ufeff")
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "|") {
+			lines[i] = `\` + trimmed
+		}
+	}
+	return writeMarkdown(ParserText, req, strings.Join(lines, "\n"), nil)
+}
@@ -0,0 +1,340 @@
+package embedding
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"rag_imagetotext_texttoimage/internal/application/ports"
+	"rag_imagetotext_texttoimage/internal/infra/monitoring"
+	"rag_imagetotext_texttoimage/internal/util"
+)
+
+const (
+	defaultCacheMaxEntries = 20000
+
+	cacheKindText  = "text"
+	cacheKindImage = "image"
+
+	cacheResultMemoryHit  = "memory_hit"
+	cacheResultDiskHit    = "disk_hit"
+	cacheResultMiss       = "miss"
+	cacheResultBatchDedup = "batch_dedup"
+)
+
+var _ ports.Inference = (*CachedInference)(nil)
+
+var embeddingCacheBucket = []byte("embeddings")
+
+// CachedInference caches the embeddings of an inner ports.Inference by
+// content: the key is a sha256 of the model version, the input kind and the
+// text or the image shape and pixels. Lookups go to an in-memory LRU first,
+// then to the optional bbolt file, whose entries are promoted to memory.
+// Change the model version whenever the model behind it changes, otherwise
+// stale vectors are served.
+type CachedInference struct {
+	inner        ports.Inference
+	modelVersion string
+	memory       *embeddingLRU
+	disk         *bolt.DB
+	metrics      *monitoring.EmbeddingCacheMetrics
+	logger       util.Logger
+}
+
+type EmbeddingCacheOptions struct {
+	ModelVersion string
+	MaxEntries   int
+	DiskPath     string
+}
+
+func NewCachedInference(inner ports.Inference, opts EmbeddingCacheOptions, metrics *monitoring.EmbeddingCacheMetrics, appLogger util.Logger) (*CachedInference, error) {
+	if inner == nil {
+		return nil, errors.New("embedding cache needs an inference backend")
+	}
+	if opts.ModelVersion == "" {
+		return nil, errors.New("embedding cache model version is empty")
+	}
+	maxEntries := opts.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = defaultCacheMaxEntries
+	}
+
+	c := &CachedInference{
+		inner:        inner,
+		modelVersion: opts.ModelVersion,
+		memory:       newEmbeddingLRU(maxEntries),
+		metrics:      metrics,
+		logger:       appLogger,
+	}
+	if opts.DiskPath != "" {
+		if err := os.MkdirAll(filepath.Dir(opts.DiskPath), 0755); err != nil {
+			return nil, fmt.Errorf("create embedding cache dir: %w", err)
+		}
+		db, err := bolt.Open(opts.DiskPath, 0600, &bolt.Options{Timeout: 2 * time.Second})
+		if err != nil {
+			return nil, fmt.Errorf("open embedding cache %s: %w", opts.DiskPath, err)
+		}
+		if err := db.Update(func(tx *bolt.Tx) error {
+			_, err := tx.CreateBucketIfNotExists(embeddingCacheBucket)
+			return err
+		}); err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("init embedding cache bucket: %w", err)
+		}
+		c.disk = db
+	}
+	appLogger.Info("embedding cache ready", "model_version", opts.ModelVersion, "max_entries", maxEntries, "disk_path", opts.DiskPath)
+	return c, nil
+}
+
+func (c *CachedInference) EmbedText(text string) ([]float32, error) {
+	return first(c.EmbedBatchText([]string{text}))
+}
+
+func (c *CachedInference) EmbedBatchText(texts []string) ([][]float32, error) {
+	keys := make([]string, len(texts))
+	for i, text := range texts {
+		keys[i] = c.key(cacheKindText, "", []byte(text))
+	}
+	return c.embed(cacheKindText, keys, func(missing []int) ([][]float32, error) {
+		batch := make([]string, len(missing))
+		for i, index := range missing {
+			batch[i] = texts[index]
+		}
+		if len(batch) == 1 {
+			embedding, err := c.inner.EmbedText(batch[0])
+			return [][]float32{embedding}, err
+		}
+		return c.inner.EmbedBatchText(batch)
+	})
+}
+
+func (c *CachedInference) EmbedImage(pixels []byte, width, height, channels int) ([]float32, error) {
+	return first(c.EmbedBatchImage([][]byte{pixels}, width, height, channels))
+}
+
+func (c *CachedInference) EmbedBatchImage(images [][]byte, width, height, channels int) ([][]float32, error) {
+	shape := strconv.Itoa(width) + "x" + strconv.Itoa(height) + "x" + strconv.Itoa(channels)
+	keys := make([]string, len(images))
+	for i, pixels := range images {
+		keys[i] = c.key(cacheKindImage, shape, pixels)
+	}
+	return c.embed(cacheKindImage, keys, func(missing []int) ([][]float32, error) {
+		batch := make([][]byte, len(missing))
+		for i, index := range missing {
+			batch[i] = images[index]
+		}
+		if len(batch) == 1 {
+			embedding, err := c.inner.EmbedImage(batch[0], width, height, channels)
+			return [][]float32{embedding}, err
+		}
+		return c.inner.EmbedBatchImage(batch, width, height, channels)
+	})
+}
+
+func (c *CachedInference) Close() {
+	c.inner.Close()
+	if c.disk != nil {
+		if err := c.disk.Close(); err != nil {
+			c.logger.Error("close embedding cache failed", err)
+		}
+	}
+}
+
+func (c *CachedInference) key(kind string, shape string, data []byte) string {
+	h := sha256.New()
+	h.Write([]byte(c.modelVersion))
+	h.Write([]byte{0})
+	h.Write([]byte(kind))
+	h.Write([]byte{0})
+	h.Write([]byte(shape))
+	h.Write([]byte{0})
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// embed serves what it can from the cache and sends only the missing inputs
+// to the backend, once each even when a batch repeats an input. Such repeats
+// are counted as batch_dedup, not as cache hits.
+func (c *CachedInference) embed(kind string, keys []string, compute func(missing []int) ([][]float32, error)) ([][]float32, error) {
+	out := make([][]float32, len(keys))
+	missingByKey := map[string][]int{}
+	missing := []int{}
+	for i, key := range keys {
+		if embedding, ok := c.memory.get(key); ok {
+			c.record(kind, cacheResultMemoryHit)
+			out[i] = embedding
+			continue
+		}
+		if prev, ok := missingByKey[key]; ok {
+			c.record(kind, cacheResultBatchDedup)
+			missingByKey[key] = append(prev, i)
+			continue
+		}
+		if embedding, ok := c.diskGet(key); ok {
+			c.record(kind, cacheResultDiskHit)
+			c.memory.put(key, embedding)
+			out[i] = embedding
+			continue
+		}
+		c.record(kind, cacheResultMiss)
+		missingByKey[key] = []int{i}
+		missing = append(missing, i)
+	}
+	if len(missing) > 0 {
+		embeddings, err := compute(missing)
+		if err != nil {
+			return nil, err
+		}
+		if len(embeddings) != len(missing) {
+			return nil, fmt.Errorf("embedding backend returned %d embeddings for %d inputs", len(embeddings), len(missing))
+		}
+		fresh := make(map[string][]float32, len(missing))
+		for i, index := range missing {
+			key := keys[index]
+			// Repeats of an input get their own copy, like a cache hit.
+			for n, target := range missingByKey[key] {
+				if n == 0 {
+					out[target] = embeddings[i]
+					continue
+				}
+				out[target] = append([]float32(nil), embeddings[i]...)
+			}
+			if len(embeddings[i]) == 0 {
+				continue
+			}
+			fresh[key] = embeddings[i]
+			c.memory.put(key, embeddings[i])
+		}
+		c.diskPut(fresh)
+	}
+	if c.metrics != nil {
+		c.metrics.MemoryEntries.Set(float64(c.memory.len()))
+	}
+	return out, nil
+}
+
+func (c *CachedInference) record(kind string, result string) {
+	if c.metrics != nil {
+		c.metrics.RecordLookup(kind, result)
+	}
+}
+
+func (c *CachedInference) diskGet(key string) ([]float32, bool) {
+	if c.disk == nil {
+		return nil, false
+	}
+	var embedding []float32
+	_ = c.disk.View(func(tx *bolt.Tx) error {
+		if raw := tx.Bucket(embeddingCacheBucket).Get([]byte(key)); raw != nil {
+			embedding = decodeEmbedding(raw)
+		}
+		return nil
+	})
+	return embedding, embedding != nil
+}
+
+// diskPut writes the new embeddings in one transaction. A failed write is
+// only logged: the cache is an optimisation.
+func (c *CachedInference) diskPut(embeddings map[string][]float32) {
+	if c.disk == nil || len(embeddings) == 0 {
+		return
+	}
+	if err := c.disk.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(embeddingCacheBucket)
+		for key, embedding := range embeddings {
+			if err := bucket.Put([]byte(key), encodeEmbedding(embedding)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		c.logger.Error("write embedding cache failed", err, "entries", len(embeddings))
+	}
+}
+
+func encodeEmbedding(embedding []float32) []byte {
+	raw := make([]byte, 4*len(embedding))
+	for i, v := range embedding {
+		binary.LittleEndian.PutUint32(raw[4*i:], math.Float32bits(v))
+	}
+	return raw
+}
+
+func decodeEmbedding(raw []byte) []float32 {
+	if len(raw) == 0 || len(raw)%4 != 0 {
+		return nil
+	}
+	embedding := make([]float32, len(raw)/4)
+	for i := range embedding {
+		embedding[i] = math.Float32frombits(binary.LittleEndian.Uint32(raw[4*i:]))
+	}
+	return embedding
+}
+
+// embeddingLRU is a fixed-size LRU of embeddings. Vectors are copied on the
+// way in and out so callers cannot change what is cached.
+type embeddingLRU struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	items    map[string]*list.Element
+}
+
+type embeddingLRUEntry struct {
+	key       string
+	embedding []float32
+}
+
+func newEmbeddingLRU(capacity int) *embeddingLRU {
+	return &embeddingLRU{
+		capacity: capacity,
+		order:    list.New(),
+		items:    map[string]*list.Element{},
+	}
+}
+
+func (l *embeddingLRU) get(key string) ([]float32, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	element, ok := l.items[key]
+	if !ok {
+		return nil, false
+	}
+	l.order.MoveToFront(element)
+	return append([]float32(nil), element.Value.(*embeddingLRUEntry).embedding...), true
+}
+
+func (l *embeddingLRU) put(key string, embedding []float32) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	embedding = append([]float32(nil), embedding...)
+	if element, ok := l.items[key]; ok {
+		element.Value.(*embeddingLRUEntry).embedding = embedding
+		l.order.MoveToFront(element)
+		return
+	}
+	l.items[key] = l.order.PushFront(&embeddingLRUEntry{key: key, embedding: embedding})
+	for l.order.Len() > l.capacity {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.items, oldest.Value.(*embeddingLRUEntry).key)
+	}
+}
+
+func (l *embeddingLRU) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.order.Len()
+}
@@ -0,0 +1,199 @@
+package embedding
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"rag_imagetotext_texttoimage/internal/infra/monitoring"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Info(string, ...any)         {}
+func (nopLogger) Error(string, error, ...any) {}
+func (nopLogger) Debug(string, ...any)        {}
+func (nopLogger) Close() error                { return nil }
+
+// countingInference embeds a text as {len(text), call number} and records
+// every batch it is asked for.
+type countingInference struct {
+	batches [][]string
+}
+
+func (f *countingInference) EmbedText(text string) ([]float32, error) {
+	out, err := f.EmbedBatchText([]string{text})
+	return out[0], err
+}
+
+func (f *countingInference) EmbedBatchText(texts []string) ([][]float32, error) {
+	f.batches = append(f.batches, append([]string(nil), texts...))
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		out[i] = []float32{float32(len(text)), float32(len(f.batches))}
+	}
+	return out, nil
+}
+
+func (f *countingInference) EmbedImage([]byte, int, int, int) ([]float32, error) {
+	return []float32{1}, nil
+}
+
+func (f *countingInference) EmbedBatchImage(images [][]byte, _, _, _ int) ([][]float32, error) {
+	out := make([][]float32, len(images))
+	for i := range images {
+		out[i] = []float32{1}
+	}
+	return out, nil
+}
+
+func (f *countingInference) Close() {}
+
+func newTestCacheMetrics() *monitoring.EmbeddingCacheMetrics {
+	return &monitoring.EmbeddingCacheMetrics{
+		Lookups:       prometheus.NewCounterVec(prometheus.CounterOpts{Name: "lookups"}, []string{"kind", "result"}),
+		MemoryEntries: prometheus.NewGauge(prometheus.GaugeOpts{Name: "entries"}),
+	}
+}
+
+func lookups(t *testing.T, metrics *monitoring.EmbeddingCacheMetrics, result string) float64 {
+	t.Helper()
+	var metric dto.Metric
+	if err := metrics.Lookups.WithLabelValues(cacheKindText, result).Write(&metric); err != nil {
+		t.Fatal(err)
+	}
+	return metric.GetCounter().GetValue()
+}
+
+func newTestCache(t *testing.T, inner *countingInference, opts EmbeddingCacheOptions) (*CachedInference, *monitoring.EmbeddingCacheMetrics) {
+	t.Helper()
+	if opts.ModelVersion == "" {
+		opts.ModelVersion = "test-v1"
+	}
+	metrics := newTestCacheMetrics()
+	cache, err := NewCachedInference(inner, opts, metrics, nopLogger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cache, metrics
+}
+
+func TestEmbeddingLRUEviction(t *testing.T) {
+	lru := newEmbeddingLRU(2)
+	lru.put("a", []float32{1})
+	lru.put("b", []float32{2})
+	if _, ok := lru.get("a"); !ok {
+		t.Fatal("a missing")
+	}
+	lru.put("c", []float32{3})
+
+	if _, ok := lru.get("b"); ok {
+		t.Fatal("b, the least recently used entry, was not evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := lru.get(key); !ok {
+			t.Fatalf("%s was evicted", key)
+		}
+	}
+	if lru.len() != 2 {
+		t.Fatalf("len = %d, want 2", lru.len())
+	}
+
+	got, _ := lru.get("a")
+	got[0] = 42
+	if again, _ := lru.get("a"); again[0] != 1 {
+		t.Fatal("changing a returned vector changed the cache")
+	}
+}
+
+func TestCachedInferenceBatchDedup(t *testing.T) {
+	inner := &countingInference{}
+	cache, metrics := newTestCache(t, inner, EmbeddingCacheOptions{})
+	defer cache.Close()
+
+	out, err := cache.EmbedBatchText([]string{"ab", "xyz", "ab", "ab"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inner.batches) != 1 || !slices.Equal(inner.batches[0], []string{"ab", "xyz"}) {
+		t.Fatalf("backend batches = %q", inner.batches)
+	}
+	for _, i := range []int{2, 3} {
+		if !slices.Equal(out[i], out[0]) {
+			t.Fatalf("out[%d] = %v, want %v", i, out[i], out[0])
+		}
+	}
+	out[2][0] = 42
+	if out[0][0] == 42 || out[3][0] == 42 {
+		t.Fatal("repeated inputs share one slice")
+	}
+
+	if got := lookups(t, metrics, cacheResultMiss); got != 2 {
+		t.Fatalf("miss = %v, want 2", got)
+	}
+	if got := lookups(t, metrics, cacheResultBatchDedup); got != 2 {
+		t.Fatalf("batch_dedup = %v, want 2", got)
+	}
+	if got := lookups(t, metrics, cacheResultMemoryHit); got != 0 {
+		t.Fatalf("memory_hit = %v, want 0", got)
+	}
+
+	if _, err := cache.EmbedBatchText([]string{"ab"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := lookups(t, metrics, cacheResultMemoryHit); got != 1 {
+		t.Fatalf("memory_hit = %v, want 1", got)
+	}
+	if len(inner.batches) != 1 {
+		t.Fatalf("cached input went to the backend: %q", inner.batches)
+	}
+}
+
+func TestCachedInferenceDiskRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "embeddings.db")
+	inner := &countingInference{}
+	cache, _ := newTestCache(t, inner, EmbeddingCacheOptions{DiskPath: path})
+	want, err := cache.EmbedText("hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.Close()
+
+	reopened := &countingInference{}
+	cache, metrics := newTestCache(t, reopened, EmbeddingCacheOptions{DiskPath: path})
+	got, err := cache.EmbedText("hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("disk tier returned %v, want %v", got, want)
+	}
+	if len(reopened.batches) != 0 {
+		t.Fatalf("disk hit went to the backend: %q", reopened.batches)
+	}
+	if got := lookups(t, metrics, cacheResultDiskHit); got != 1 {
+		t.Fatalf("disk_hit = %v, want 1", got)
+	}
+	// The disk hit is promoted to memory.
+	if _, err := cache.EmbedText("hello"); err != nil {
+		t.Fatal(err)
+	}
+	if got := lookups(t, metrics, cacheResultMemoryHit); got != 1 {
+		t.Fatalf("memory_hit = %v, want 1", got)
+	}
+	cache.Close()
+
+	// Another model version must not read the old vectors.
+	other := &countingInference{}
+	cache, _ = newTestCache(t, other, EmbeddingCacheOptions{DiskPath: path, ModelVersion: "test-v2"})
+	defer cache.Close()
+	if _, err := cache.EmbedText("hello"); err != nil {
+		t.Fatal(err)
+	}
+	if len(other.batches) != 1 {
+		t.Fatal("a new model version was served the old model's vectors")
+	}
+}
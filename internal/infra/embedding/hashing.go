@@ -0,0 +1,147 @@
+package embedding
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+
+	"rag_imagetotext_texttoimage/internal/application/ports"
+)
+
+const (
+	defaultHashingDimension = 768
+	hashingImageGrid        = 8
+	hashingImageLevels      = 8
+)
+
+var _ ports.Inference = (*HashingEmbedder)(nil)
+
+// HashingEmbedder is a deterministic embedder for tests and local runs: words
+// and word pairs of a text, or the coarse colours of an 8x8 grid of an image,
+// are hashed into signed buckets and the vector is L2-normalised. Texts that
+// share words score close; it has no notion of meaning.
+type HashingEmbedder struct {
+	dimension int
+}
+
+func NewHashingEmbedder(dimension int) *HashingEmbedder {
+	if dimension <= 0 {
+		dimension = defaultHashingDimension
+	}
+	return &HashingEmbedder{dimension: dimension}
+}
+
+func (e *HashingEmbedder) Dimension() int {
+	return e.dimension
+}
+
+func (e *HashingEmbedder) EmbedText(text string) ([]float32, error) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	features := make([]string, 0, 2*len(words))
+	for i, word := range words {
+		features = append(features, "w:"+word)
+		if i > 0 {
+			features = append(features, "b:"+words[i-1]+" "+word)
+		}
+	}
+	return e.vector(features), nil
+}
+
+func (e *HashingEmbedder) EmbedBatchText(texts []string) ([][]float32, error) {
+	out := make([][]float32, 0, len(texts))
+	for _, text := range texts {
+		embedding, _ := e.EmbedText(text)
+		out = append(out, embedding)
+	}
+	return out, nil
+}
+
+func (e *HashingEmbedder) EmbedImage(pixels []byte, width, height, channels int) ([]float32, error) {
+	if width <= 0 || height <= 0 || channels <= 0 {
+		return nil, fmt.Errorf("invalid image shape %dx%dx%d", width, height, channels)
+	}
+	if len(pixels) != width*height*channels {
+		return nil, fmt.Errorf("image has %d bytes, expected %d", len(pixels), width*height*channels)
+	}
+
+	features := make([]string, 0, hashingImageGrid*hashingImageGrid)
+	for gy := 0; gy < hashingImageGrid; gy++ {
+		for gx := 0; gx < hashingImageGrid; gx++ {
+			x0, y0 := gx*width/hashingImageGrid, gy*height/hashingImageGrid
+			x1 := max((gx+1)*width/hashingImageGrid, x0+1)
+			y1 := max((gy+1)*height/hashingImageGrid, y0+1)
+			sums := make([]int, min(channels, 3))
+			n := 0
+			for y := y0; y < min(y1, height); y++ {
+				for x := x0; x < min(x1, width); x++ {
+					p := (y*width + x) * channels
+					for c := range sums {
+						sums[c] += int(pixels[p+c])
+					}
+					n++
+				}
+			}
+			cell := fmt.Sprintf("c:%d:%d", gx, gy)
+			for c := range sums {
+				level := 0
+				if n > 0 {
+					level = sums[c] / n * hashingImageLevels / 256
+				}
+				cell += fmt.Sprintf(":%d", level)
+			}
+			features = append(features, cell)
+		}
+	}
+	return e.vector(features), nil
+}
+
+func (e *HashingEmbedder) EmbedBatchImage(images [][]byte, width, height, channels int) ([][]float32, error) {
+	out := make([][]float32, 0, len(images))
+	for i, pixels := range images {
+		embedding, err := e.EmbedImage(pixels, width, height, channels)
+		if err != nil {
+			return nil, fmt.Errorf("image %d: %w", i, err)
+		}
+		out = append(out, embedding)
+	}
+	return out, nil
+}
+
+func (e *HashingEmbedder) Close() {}
+
+// vector hashes every feature into one bucket with a sign taken from the
+// hash, so unrelated features cancel out instead of piling up. An empty input
+// still gets a non-zero vector.
+func (e *HashingEmbedder) vector(features []string) []float32 {
+	if len(features) == 0 {
+		features = []string{"empty"}
+	}
+	out := make([]float32, e.dimension)
+	for _, feature := range features {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(feature))
+		sum := h.Sum64()
+		if sum>>63 == 1 {
+			out[sum%uint64(e.dimension)]--
+		} else {
+			out[sum%uint64(e.dimension)]++
+		}
+	}
+	var norm float64
+	for _, v := range out {
+		norm += float64(v) * float64(v)
+	}
+	if norm == 0 {
+		out[0] = 1
+		return out
+	}
+	scale := float32(1 / math.Sqrt(norm))
+	for i := range out {
+		out[i] *= scale
+	}
+	return out
+}
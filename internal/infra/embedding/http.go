@@ -0,0 +1,147 @@
+package embedding
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"rag_imagetotext_texttoimage/internal/util"
+)
+
+const (
+	defaultHTTPTimeout  = 60 * time.Second
+	defaultHTTPMaxBatch = 32
+	maxErrorBodyBytes   = 512
+)
+
+var ErrImageNotSupported = errors.New("embedding backend does not support images")
+
+// httpBackend holds what the HTTP embedding clients share: the base URL, the
+// bearer token and a client with the configured timeout.
+type httpBackend struct {
+	baseURL  string
+	apiKey   string
+	model    string
+	maxBatch int
+	client   *http.Client
+	logger   util.Logger
+}
+
+func newHTTPBackend(cfg util.EmbeddingHTTPSettings, appLogger util.Logger) (httpBackend, error) {
+	baseURL := strings.TrimRight(strings.TrimSpace(cfg.BaseURL), "/")
+	if baseURL == "" {
+		return httpBackend{}, errors.New("embedding http base_url is empty")
+	}
+	timeout := time.Duration(cfg.TimeoutMs) * time.Millisecond
+	if timeout <= 0 {
+		timeout = defaultHTTPTimeout
+	}
+	maxBatch := cfg.MaxBatch
+	if maxBatch <= 0 {
+		maxBatch = defaultHTTPMaxBatch
+	}
+	return httpBackend{
+		baseURL:  baseURL,
+		apiKey:   strings.TrimSpace(cfg.APIKey),
+		model:    strings.TrimSpace(cfg.Model),
+		maxBatch: maxBatch,
+		client:   &http.Client{Timeout: timeout},
+		logger:   appLogger,
+	}, nil
+}
+
+func (b httpBackend) postJSON(ctx context.Context, path string, body any, out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshal embedding request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if b.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+b.apiKey)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("call %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+		return fmt.Errorf("call %s: status %d: %s", path, resp.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode %s response: %w", path, err)
+	}
+	return nil
+}
+
+// inBatches calls fn on consecutive slices of at most size items and joins
+// the results.
+func inBatches[T any](items []T, size int, fn func(batch []T) ([][]float32, error)) ([][]float32, error) {
+	out := make([][]float32, 0, len(items))
+	for start := 0; start < len(items); start += size {
+		end := min(start+size, len(items))
+		embeddings, err := fn(items[start:end])
+		if err != nil {
+			return nil, err
+		}
+		if len(embeddings) != end-start {
+			return nil, fmt.Errorf("embedding backend returned %d embeddings for %d inputs", len(embeddings), end-start)
+		}
+		out = append(out, embeddings...)
+	}
+	return out, nil
+}
+
+// pixelsToDataURL encodes interleaved RGB or RGBA pixels as a PNG data URL,
+// the image form multimodal embedding APIs accept.
+func pixelsToDataURL(pixels []byte, width, height, channels int) (string, error) {
+	if width <= 0 || height <= 0 {
+		return "", fmt.Errorf("invalid image size %dx%d", width, height)
+	}
+	if channels != 3 && channels != 4 {
+		return "", fmt.Errorf("unsupported image channels %d", channels)
+	}
+	if len(pixels) != width*height*channels {
+		return "", fmt.Errorf("image has %d bytes, expected %d", len(pixels), width*height*channels)
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for i, p := 0, 0; i < width*height; i, p = i+1, p+channels {
+		img.Pix[i*4] = pixels[p]
+		img.Pix[i*4+1] = pixels[p+1]
+		img.Pix[i*4+2] = pixels[p+2]
+		img.Pix[i*4+3] = 255
+		if channels == 4 {
+			img.Pix[i*4+3] = pixels[p+3]
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", fmt.Errorf("encode image: %w", err)
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+func first(embeddings [][]float32, err error) ([]float32, error) {
+	if err != nil {
+		return nil, err
+	}
+	if len(embeddings) != 1 {
+		return nil, fmt.Errorf("embedding backend returned %d embeddings for 1 input", len(embeddings))
+	}
+	return embeddings[0], nil
+}
@@ -0,0 +1,100 @@
+package embedding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"rag_imagetotext_texttoimage/internal/application/ports"
+	"rag_imagetotext_texttoimage/internal/util"
+)
+
+var _ ports.Inference = (*OpenAIEmbedder)(nil)
+
+// OpenAIEmbedder calls an OpenAI-compatible /v1/embeddings endpoint (OpenAI,
+// vLLM, Ollama, LiteLLM, the Jina API). Images are sent as
+// {"image": "data:image/png;base64,..."} inputs, which only multimodal
+// servers such as the Jina API accept.
+type OpenAIEmbedder struct {
+	http httpBackend
+	path string
+}
+
+type openAIEmbeddingRequest struct {
+	Model string `json:"model,omitempty"`
+	Input any    `json:"input"`
+}
+
+type openAIEmbeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+func NewOpenAIEmbedder(cfg util.EmbeddingHTTPSettings, appLogger util.Logger) (*OpenAIEmbedder, error) {
+	backend, err := newHTTPBackend(cfg, appLogger)
+	if err != nil {
+		return nil, err
+	}
+	if backend.model == "" {
+		return nil, errors.New("openai embedding model is empty")
+	}
+	path := "/v1/embeddings"
+	if strings.HasSuffix(backend.baseURL, "/v1") {
+		path = "/embeddings"
+	}
+	appLogger.Info("openai embedding backend ready", "base_url", backend.baseURL, "model", backend.model, "max_batch", backend.maxBatch)
+	return &OpenAIEmbedder{http: backend, path: path}, nil
+}
+
+func (e *OpenAIEmbedder) EmbedText(text string) ([]float32, error) {
+	return first(e.EmbedBatchText([]string{text}))
+}
+
+func (e *OpenAIEmbedder) EmbedBatchText(texts []string) ([][]float32, error) {
+	return inBatches(texts, e.http.maxBatch, func(batch []string) ([][]float32, error) {
+		return e.embed(batch, len(batch))
+	})
+}
+
+func (e *OpenAIEmbedder) EmbedImage(pixels []byte, width, height, channels int) ([]float32, error) {
+	return first(e.EmbedBatchImage([][]byte{pixels}, width, height, channels))
+}
+
+func (e *OpenAIEmbedder) EmbedBatchImage(images [][]byte, width, height, channels int) ([][]float32, error) {
+	return inBatches(images, e.http.maxBatch, func(batch [][]byte) ([][]float32, error) {
+		inputs := make([]map[string]string, 0, len(batch))
+		for i, pixels := range batch {
+			dataURL, err := pixelsToDataURL(pixels, width, height, channels)
+			if err != nil {
+				return nil, fmt.Errorf("image %d: %w", i, err)
+			}
+			inputs = append(inputs, map[string]string{"image": dataURL})
+		}
+		return e.embed(inputs, len(batch))
+	})
+}
+
+func (e *OpenAIEmbedder) embed(input any, count int) ([][]float32, error) {
+	var resp openAIEmbeddingResponse
+	if err := e.http.postJSON(context.Background(), e.path, openAIEmbeddingRequest{Model: e.http.model, Input: input}, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Data) != count {
+		return nil, fmt.Errorf("openai embedding returned %d embeddings for %d inputs", len(resp.Data), count)
+	}
+	out := make([][]float32, count)
+	for _, item := range resp.Data {
+		if item.Index < 0 || item.Index >= count || out[item.Index] != nil {
+			return nil, fmt.Errorf("openai embedding returned invalid index %d", item.Index)
+		}
+		out[item.Index] = item.Embedding
+	}
+	return out, nil
+}
+
+func (e *OpenAIEmbedder) Close() {
+	e.http.client.CloseIdleConnections()
+}
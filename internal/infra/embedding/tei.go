@@ -0,0 +1,91 @@
+package embedding
+
+import (
+	"context"
+	"fmt"
+
+	"rag_imagetotext_texttoimage/internal/application/ports"
+	"rag_imagetotext_texttoimage/internal/util"
+)
+
+var _ ports.Inference = (*TEIEmbedder)(nil)
+var _ ports.Reranker = (*TEIEmbedder)(nil)
+
+// TEIEmbedder calls a text-embeddings-inference server: /embed for
+// embeddings and /rerank when the server runs a cross-encoder. TEI serves
+// text models only, so image calls fail with ErrImageNotSupported.
+type TEIEmbedder struct {
+	http httpBackend
+}
+
+type teiEmbedRequest struct {
+	Inputs   []string `json:"inputs"`
+	Truncate bool     `json:"truncate"`
+}
+
+type teiRerankRequest struct {
+	Query    string   `json:"query"`
+	Texts    []string `json:"texts"`
+	Truncate bool     `json:"truncate"`
+}
+
+type teiRerankResult struct {
+	Index int     `json:"index"`
+	Score float32 `json:"score"`
+}
+
+func NewTEIEmbedder(cfg util.EmbeddingHTTPSettings, appLogger util.Logger) (*TEIEmbedder, error) {
+	backend, err := newHTTPBackend(cfg, appLogger)
+	if err != nil {
+		return nil, err
+	}
+	appLogger.Info("tei embedding backend ready", "base_url", backend.baseURL, "max_batch", backend.maxBatch)
+	return &TEIEmbedder{http: backend}, nil
+}
+
+func (e *TEIEmbedder) EmbedText(text string) ([]float32, error) {
+	return first(e.EmbedBatchText([]string{text}))
+}
+
+func (e *TEIEmbedder) EmbedBatchText(texts []string) ([][]float32, error) {
+	return inBatches(texts, e.http.maxBatch, func(batch []string) ([][]float32, error) {
+		var embeddings [][]float32
+		if err := e.http.postJSON(context.Background(), "/embed", teiEmbedRequest{Inputs: batch, Truncate: true}, &embeddings); err != nil {
+			return nil, err
+		}
+		return embeddings, nil
+	})
+}
+
+func (e *TEIEmbedder) EmbedImage(pixels []byte, width, height, channels int) ([]float32, error) {
+	return nil, ErrImageNotSupported
+}
+
+func (e *TEIEmbedder) EmbedBatchImage(images [][]byte, width, height, channels int) ([][]float32, error) {
+	return nil, ErrImageNotSupported
+}
+
+func (e *TEIEmbedder) Rerank(query string, documents []string) ([]float32, error) {
+	if len(documents) == 0 {
+		return []float32{}, nil
+	}
+	var results []teiRerankResult
+	if err := e.http.postJSON(context.Background(), "/rerank", teiRerankRequest{Query: query, Texts: documents, Truncate: true}, &results); err != nil {
+		return nil, err
+	}
+	if len(results) != len(documents) {
+		return nil, fmt.Errorf("tei rerank returned %d scores for %d documents", len(results), len(documents))
+	}
+	scores := make([]float32, len(documents))
+	for _, result := range results {
+		if result.Index < 0 || result.Index >= len(documents) {
+			return nil, fmt.Errorf("tei rerank returned invalid index %d", result.Index)
+		}
+		scores[result.Index] = result.Score
+	}
+	return scores, nil
+}
+
+func (e *TEIEmbedder) Close() {
+	e.http.client.CloseIdleConnections()
+}
@@ -140,8 +140,14 @@ func (c *Consumer) Consume(ctx context.Context, topic string, groupID string, ha
 			},
 		}
 
-		if err := handler(ctx, consumeMessage); err != nil {
-			wrappedErr := fmt.Errorf("consume handler failed: %w", err)
+		handlerErr := handler(ctx, consumeMessage)
+		stop := errors.Is(handlerErr, ports.ErrStopConsuming)
+		if handlerErr != nil && !stop {
+			if ctx.Err() != nil {
+				// Shutting down: leave the message uncommitted so it is redelivered.
+				return nil
+			}
+			wrappedErr := fmt.Errorf("consume handler failed: %w", handlerErr)
 			if c.appLogger != nil {
 				c.appLogger.Error("consume handler failed", wrappedErr, "topic", topic, "group_id", groupID, "offset", msg.Offset)
 			}
@@ -158,6 +164,9 @@ func (c *Consumer) Consume(ctx context.Context, topic string, groupID string, ha
 			}
 			return wrappedErr
 		}
+		if stop {
+			return nil
+		}
 	}
 }
 
@@ -206,3 +206,135 @@ func (G *Gemini) GenerateTextToImage(
 
 	return response, nil
 }
+
+func (G *Gemini) GenerateTextToTextStream(
+	ctx context.Context,
+	model string,
+	temp float32,
+	prompt string,
+	history []ports.ChatHistory,
+	onDelta ports.LLMStreamHandler) (*ports.LLMResponse, error) {
+
+	model = strings.TrimSpace(model)
+	if model == "" {
+		model = G.defaultModel
+	}
+	if temp <= 0 {
+		temp = G.defaultTemp
+	}
+
+	config := &genai.GenerateContentConfig{
+		Temperature: &temp,
+	}
+
+	contents := historyToContents(history)
+	promptContents := genai.Text(prompt)
+	if len(promptContents) > 0 {
+		promptContents[0].Role = "user"
+		contents = append(contents, promptContents...)
+	}
+
+	text, err := G.streamContent(ctx, model, contents, config, onDelta)
+	if err != nil {
+		G.appLogger.Error("generate text to text stream failed", err, "model", model)
+		return nil, err
+	}
+
+	G.appLogger.Info("generate text to text stream success", "model", model, "text_len", len(text))
+	return &ports.LLMResponse{Text: text}, nil
+}
+
+func (G *Gemini) GenerateTextToImageStream(
+	ctx context.Context,
+	model string,
+	temp float32,
+	imagePath string,
+	prompt string,
+	history []ports.ChatHistory,
+	onDelta ports.LLMStreamHandler) (*ports.LLMResponse, error) {
+
+	model = strings.TrimSpace(model)
+	if model == "" {
+		model = G.defaultModel
+	}
+	if temp <= 0 {
+		temp = G.defaultTemp
+	}
+
+	config := &genai.GenerateContentConfig{
+		Temperature: &temp,
+	}
+
+	imgData, err := os.ReadFile(imagePath)
+	if err != nil {
+		G.appLogger.Error("generate text to image stream read image failed", err, "image_path", imagePath)
+		return nil, err
+	}
+
+	parts := []*genai.Part{
+		genai.NewPartFromText(prompt),
+		&genai.Part{
+			InlineData: &genai.Blob{
+				MIMEType: "image/jpeg",
+				Data:     imgData,
+			},
+		},
+	}
+
+	contents := historyToContents(history)
+	contents = append(contents, genai.NewContentFromParts(parts, genai.RoleUser))
+
+	text, err := G.streamContent(ctx, model, contents, config, onDelta)
+	if err != nil {
+		G.appLogger.Error("generate text to image stream failed", err, "model", model)
+		return nil, err
+	}
+
+	G.appLogger.Info("generate text to image stream success", "model", model, "text_len", len(text))
+	return &ports.LLMResponse{Text: text}, nil
+}
+
+func (G *Gemini) streamContent(
+	ctx context.Context,
+	model string,
+	contents []*genai.Content,
+	config *genai.GenerateContentConfig,
+	onDelta ports.LLMStreamHandler) (string, error) {
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var builder strings.Builder
+	for result, err := range G.Client.Models.GenerateContentStream(ctx, model, contents, config) {
+		if err != nil {
+			return "", err
+		}
+		if result == nil {
+			continue
+		}
+		delta := result.Text()
+		if delta == "" {
+			continue
+		}
+		builder.WriteString(delta)
+		if onDelta != nil {
+			if err := onDelta(delta); err != nil {
+				return "", err
+			}
+		}
+	}
+	return builder.String(), nil
+}
+
+func historyToContents(history []ports.ChatHistory) []*genai.Content {
+	var contents []*genai.Content
+	for _, h := range history {
+		historyContents := genai.Text(h.Content)
+		if len(historyContents) > 0 {
+			historyContents[0].Role = h.Role
+			contents = append(contents, historyContents...)
+		}
+	}
+	return contents
+}
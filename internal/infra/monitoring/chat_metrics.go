@@ -0,0 +1,33 @@
+package monitoring
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type ChatMetrics struct {
+	IntentTotal  *prometheus.CounterVec
+	PipelineTime *prometheus.HistogramVec
+}
+
+func NewChatMetrics() *ChatMetrics {
+	return &ChatMetrics{
+		IntentTotal: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name: "orchestrator_chat_intent_total",
+			Help: "Total chat requests by routed intent and the stage that decided it.",
+		}, []string{"intent", "source"}),
+		PipelineTime: promauto.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "orchestrator_chat_pipeline_seconds",
+			Help:    "Chat pipeline duration in seconds by intent and status.",
+			Buckets: []float64{0.25, 0.5, 1, 2, 4, 8, 16, 32, 64},
+		}, []string{"intent", "status"}),
+	}
+}
+
+func (m *ChatMetrics) RecordChatIntent(intent string, source string) {
+	m.IntentTotal.WithLabelValues(intent, source).Inc()
+}
+
+func (m *ChatMetrics) RecordChatPipeline(intent string, status string, duration float64) {
+	m.PipelineTime.WithLabelValues(intent, status).Observe(duration)
+}
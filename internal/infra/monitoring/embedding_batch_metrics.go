@@ -0,0 +1,26 @@
+package monitoring
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type EmbeddingBatchMetrics struct {
+	QueueDepth *prometheus.HistogramVec
+	BatchSize  *prometheus.HistogramVec
+}
+
+func NewEmbeddingBatchMetrics() *EmbeddingBatchMetrics {
+	return &EmbeddingBatchMetrics{
+		QueueDepth: promauto.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "embedding_grpc_batch_queue_depth",
+			Help:    "Requests already waiting in the gRPC embedding batcher when a request arrives, by input kind.",
+			Buckets: []float64{0, 1, 2, 4, 8, 16, 32, 64, 128, 256},
+		}, []string{"kind"}),
+		BatchSize: promauto.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "embedding_grpc_batch_size",
+			Help:    "Requests per inference call run by the gRPC embedding batcher, by input kind.",
+			Buckets: []float64{1, 2, 4, 8, 16, 32, 64},
+		}, []string{"kind"}),
+	}
+}
@@ -0,0 +1,28 @@
+package monitoring
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type EmbeddingCacheMetrics struct {
+	Lookups       *prometheus.CounterVec
+	MemoryEntries prometheus.Gauge
+}
+
+func NewEmbeddingCacheMetrics() *EmbeddingCacheMetrics {
+	return &EmbeddingCacheMetrics{
+		Lookups: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name: "embedding_cache_lookups_total",
+			Help: "Embedding cache lookups by input kind and result (memory_hit, disk_hit, miss, batch_dedup).",
+		}, []string{"kind", "result"}),
+		MemoryEntries: promauto.NewGauge(prometheus.GaugeOpts{
+			Name: "embedding_cache_memory_entries",
+			Help: "Current number of embeddings held in the in-memory cache tier.",
+		}),
+	}
+}
+
+func (m *EmbeddingCacheMetrics) RecordLookup(kind string, result string) {
+	m.Lookups.WithLabelValues(kind, result).Inc()
+}
@@ -10,6 +10,7 @@ type Metrics struct {
 	QueueLength    prometheus.Gauge
 	ProcessedTotal *prometheus.CounterVec
 	RetryTotal     *prometheus.CounterVec
+	DeadLetter     *prometheus.CounterVec
 	PanicsTotal    *prometheus.CounterVec
 	ProcessingTime *prometheus.HistogramVec
 }
@@ -32,6 +33,10 @@ func NewMetrics() *Metrics {
 			Name: "kafka_task_retry_total",
 			Help: "Total retried tasks by topic, handler and error type.",
 		}, []string{"topic", "handler", "error_type"}),
+		DeadLetter: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name: "kafka_task_dead_letter_total",
+			Help: "Total tasks moved to a dead-letter topic by topic, handler and reason.",
+		}, []string{"topic", "handler", "reason"}),
 		PanicsTotal: promauto.NewCounterVec(prometheus.CounterOpts{
 			Name: "kafka_task_panics_total",
 			Help: "Total panics recovered by topic and handler.",
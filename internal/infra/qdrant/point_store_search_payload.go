@@ -56,6 +56,12 @@ func payloadFromQdrant(payload map[string]*qdrant.Value) domain.PointPayload {
 	if v, ok := getString(payload, "section_title"); ok {
 		out.SectionTitle = v
 	}
+	if v, ok := getString(payload, "table_csv"); ok {
+		out.TableCSV = v
+	}
+	if v, ok := getString(payload, "heading_path"); ok {
+		out.HeadingPath = v
+	}
 	if v, ok := getString(payload, "lang"); ok {
 		out.Lang = v
 	}
@@ -65,9 +71,21 @@ func payloadFromQdrant(payload map[string]*qdrant.Value) domain.PointPayload {
 	if v, ok := getBool(payload, "has_figure"); ok {
 		out.HasFigure = v
 	}
+	if v, ok := getString(payload, "object_key"); ok {
+		out.ObjectKey = v
+	}
 	if v, ok := getString(payload, "parent_id"); ok {
 		out.ParentID = v
 	}
+	if v, ok := getString(payload, "doc_hash"); ok {
+		out.DocHash = v
+	}
+	if v, ok := getString(payload, "chunk_hash"); ok {
+		out.ChunkHash = v
+	}
+	if v, ok := getString(payload, "ingest_run_id"); ok {
+		out.IngestRunID = v
+	}
 	if v, ok := getInt(payload, "chunk_index"); ok {
 		out.ChunkIndex = v
 	}
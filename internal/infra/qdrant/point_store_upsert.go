@@ -57,12 +57,30 @@ func (p *PointStore) Upsert(ctx context.Context, collectionName string, points [
 		if point.Payload.SectionTitle != "" {
 			payload["section_title"] = point.Payload.SectionTitle
 		}
+		if point.Payload.TableCSV != "" {
+			payload["table_csv"] = point.Payload.TableCSV
+		}
+		if point.Payload.HeadingPath != "" {
+			payload["heading_path"] = point.Payload.HeadingPath
+		}
 		if point.Payload.Lang != "" {
 			payload["lang"] = point.Payload.Lang
 		}
+		if point.Payload.ObjectKey != "" {
+			payload["object_key"] = point.Payload.ObjectKey
+		}
 		if point.Payload.ParentID != "" {
 			payload["parent_id"] = point.Payload.ParentID
 		}
+		if point.Payload.DocHash != "" {
+			payload["doc_hash"] = point.Payload.DocHash
+		}
+		if point.Payload.ChunkHash != "" {
+			payload["chunk_hash"] = point.Payload.ChunkHash
+		}
+		if point.Payload.IngestRunID != "" {
+			payload["ingest_run_id"] = point.Payload.IngestRunID
+		}
 		if len(point.Payload.Keywords) > 0 {
 			keywords := make([]any, 0, len(point.Payload.Keywords))
 			for _, kw := range point.Payload.Keywords {
@@ -121,7 +139,13 @@ func (p *PointStore) Upsert(ctx context.Context, collectionName string, points [
 	return nil
 }
 
+// buildBM25Text indexes only the caption of a figure point: its text and
+// section title are copies from the parent chunk, which is already indexed,
+// and indexing them again would give the parent k+1 BM25 hits.
 func buildBM25Text(payload domain.PointPayload) string {
+	if payload.UnitType == unitTypeFigure {
+		return strings.TrimSpace(payload.OCRText)
+	}
 	parts := make([]string, 0, 4)
 	if text := strings.TrimSpace(payload.SectionTitle); text != "" {
 		parts = append(parts, text)
@@ -0,0 +1,28 @@
+package qdrant
+
+import (
+	"testing"
+
+	domain "rag_imagetotext_texttoimage/internal/domain/entity_objects"
+)
+
+func TestBuildBM25TextFigureIndexesOnlyCaption(t *testing.T) {
+	parent := domain.PointPayload{
+		UnitType:     "semantic_chunk",
+		SectionTitle: "Results",
+		Text:         "Accuracy rises with the batch size.",
+	}
+	if got := buildBM25Text(parent); got != "Results\nAccuracy rises with the batch size." {
+		t.Fatalf("chunk bm25 text = %q", got)
+	}
+
+	figure := parent
+	figure.UnitType = unitTypeFigure
+	if got := buildBM25Text(figure); got != "" {
+		t.Fatalf("figure without caption indexed %q", got)
+	}
+	figure.OCRText = "Accuracy by batch size"
+	if got := buildBM25Text(figure); got != "Accuracy by batch size" {
+		t.Fatalf("figure bm25 text = %q", got)
+	}
+}
@@ -9,3 +9,5 @@ const (
 
 	vectorModelBM25 = "qdrant/bm25"
 )
+
+const unitTypeFigure = "figure"
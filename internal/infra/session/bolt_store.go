@@ -0,0 +1,346 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"rag_imagetotext_texttoimage/internal/application/ports/orchestrator"
+	"rag_imagetotext_texttoimage/internal/util"
+)
+
+var _ orchestrator.ManagedSessionStore = (*BoltSessionStore)(nil)
+
+var boltSessionsBucket = []byte("sessions")
+
+// BoltSessionStore keeps sessions in a single bbolt file. bbolt takes an
+// exclusive file lock, so the file cannot be shared between replicas; use the
+// Redis store for that.
+type BoltSessionStore struct {
+	db                *bolt.DB
+	appLogger         util.Logger
+	sessionTTL        time.Duration
+	cleanupTicker     *time.Ticker
+	stopCleanupCh     chan struct{}
+	closeOnce         sync.Once
+	mu                sync.RWMutex
+	onSessionReleased func(sessionID string)
+}
+
+type boltChatMessageManager struct {
+	store     *BoltSessionStore
+	sessionID string
+}
+
+func NewBoltSessionStore(path string, sessionTTL time.Duration, appLogger util.Logger) (*BoltSessionStore, error) {
+	if path == "" {
+		return nil, fmt.Errorf("bolt session store path is empty")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("create bolt session store dir: %w", err)
+	}
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 2 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open bolt session store %s: %w", path, err)
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltSessionsBucket)
+		return err
+	}); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("init bolt session bucket: %w", err)
+	}
+
+	sessionTTL, cleanupInterval := normalizeTTL(sessionTTL)
+	store := &BoltSessionStore{
+		db:            db,
+		appLogger:     appLogger,
+		sessionTTL:    sessionTTL,
+		cleanupTicker: time.NewTicker(cleanupInterval),
+		stopCleanupCh: make(chan struct{}),
+	}
+	go store.startCleanupLoop()
+	return store, nil
+}
+
+func (s *BoltSessionStore) CreateSession(sessionID string) error {
+	now := time.Now()
+	record := sessionRecord{
+		SessionID: sessionID,
+		Metadata: orchestrator.Metadata{
+			CreatedAt: now,
+			ExpiresAt: now.Add(s.sessionTTL),
+		},
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return putBoltRecord(tx, record)
+	})
+}
+
+func (s *BoltSessionStore) GetSession(sessionID string) (orchestrator.SessionData, error) {
+	var (
+		record   sessionRecord
+		found    bool
+		released bool
+	)
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		current, ok, err := getBoltRecord(tx, sessionID)
+		if err != nil || !ok {
+			return err
+		}
+		now := time.Now()
+		if current.expired(now) {
+			released = true
+			return tx.Bucket(boltSessionsBucket).Delete([]byte(sessionID))
+		}
+		current.touch(now, s.sessionTTL)
+		record = current
+		found = true
+		return putBoltRecord(tx, current)
+	})
+	if err != nil {
+		return orchestrator.SessionData{}, err
+	}
+	if released {
+		s.release(sessionID)
+	}
+	if !found {
+		return orchestrator.SessionData{}, nil
+	}
+	return record.toSessionData(&boltChatMessageManager{store: s, sessionID: sessionID}), nil
+}
+
+func (s *BoltSessionStore) DeleteSession(sessionID string) error {
+	existed := false
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltSessionsBucket)
+		existed = bucket.Get([]byte(sessionID)) != nil
+		return bucket.Delete([]byte(sessionID))
+	})
+	if err != nil {
+		return err
+	}
+	if existed {
+		s.release(sessionID)
+	}
+	return nil
+}
+
+func (s *BoltSessionStore) SessionExists(sessionID string) (bool, error) {
+	var (
+		record sessionRecord
+		found  bool
+	)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		record, found, err = getBoltRecord(tx, sessionID)
+		return err
+	})
+	if err != nil || !found {
+		return false, err
+	}
+	if !record.expired(time.Now()) {
+		return true, nil
+	}
+
+	released := false
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		current, ok, err := getBoltRecord(tx, sessionID)
+		if err != nil || !ok || !current.expired(time.Now()) {
+			return err
+		}
+		released = true
+		return tx.Bucket(boltSessionsBucket).Delete([]byte(sessionID))
+	})
+	if err != nil {
+		return false, err
+	}
+	if released {
+		s.release(sessionID)
+	}
+	return false, nil
+}
+
+func (s *BoltSessionStore) ListSessions() ([]orchestrator.SessionSummary, error) {
+	summaries := make([]orchestrator.SessionSummary, 0)
+	now := time.Now()
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltSessionsBucket).ForEach(func(_, value []byte) error {
+			var record sessionRecord
+			if err := json.Unmarshal(value, &record); err != nil {
+				return nil
+			}
+			if record.expired(now) {
+				return nil
+			}
+			summaries = append(summaries, record.toSummary(len(record.Messages)))
+			return nil
+		})
+	})
+	return summaries, err
+}
+
+func (s *BoltSessionStore) ExtendSession(sessionID string, ttl time.Duration) (time.Time, error) {
+	if ttl <= 0 {
+		ttl = s.sessionTTL
+	}
+	var expiresAt time.Time
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		record, ok, err := getBoltRecord(tx, sessionID)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		if !ok || record.expired(now) {
+			return fmt.Errorf("%w: %s", orchestrator.ErrSessionNotFound, sessionID)
+		}
+		record.ExpiresAt = now.Add(ttl)
+		expiresAt = record.ExpiresAt
+		return putBoltRecord(tx, record)
+	})
+	return expiresAt, err
+}
+
+func (s *BoltSessionStore) UpdateMetadata(sessionID string, update func(metadata *orchestrator.Metadata)) error {
+	return s.updateRecord(sessionID, func(record *sessionRecord) {
+		record.applyMetadata(update)
+	})
+}
+
+// Close stops the cleanup loop and closes the file. Unlike the in-memory
+// store, sessions are not released because they outlive the process.
+func (s *BoltSessionStore) Close() {
+	if s == nil {
+		return
+	}
+	s.closeOnce.Do(func() {
+		close(s.stopCleanupCh)
+		if err := s.db.Close(); err != nil && s.appLogger != nil {
+			s.appLogger.Error("internal.infra.session.BoltSessionStore.Close failed", err)
+		}
+	})
+}
+
+func (s *BoltSessionStore) SetOnSessionReleased(callback func(sessionID string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onSessionReleased = callback
+}
+
+func (s *BoltSessionStore) startCleanupLoop() {
+	for {
+		select {
+		case <-s.cleanupTicker.C:
+			s.cleanupExpiredSessions(time.Now())
+		case <-s.stopCleanupCh:
+			s.cleanupTicker.Stop()
+			return
+		}
+	}
+}
+
+func (s *BoltSessionStore) cleanupExpiredSessions(now time.Time) {
+	expiredSessionIDs := make([]string, 0)
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltSessionsBucket)
+		cursor := bucket.Cursor()
+		for key, value := cursor.First(); key != nil; key, value = cursor.Next() {
+			var record sessionRecord
+			if err := json.Unmarshal(value, &record); err != nil {
+				continue
+			}
+			if record.expired(now) {
+				expiredSessionIDs = append(expiredSessionIDs, string(key))
+			}
+		}
+		for _, sessionID := range expiredSessionIDs {
+			if err := bucket.Delete([]byte(sessionID)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if s.appLogger != nil {
+			s.appLogger.Error("internal.infra.session.BoltSessionStore.cleanupExpiredSessions failed", err)
+		}
+		return
+	}
+	s.mu.RLock()
+	callback := s.onSessionReleased
+	s.mu.RUnlock()
+	releaseAll(callback, expiredSessionIDs)
+}
+
+func (s *BoltSessionStore) release(sessionID string) {
+	s.mu.RLock()
+	callback := s.onSessionReleased
+	s.mu.RUnlock()
+	if callback != nil {
+		callback(sessionID)
+	}
+}
+
+func (s *BoltSessionStore) updateRecord(sessionID string, update func(record *sessionRecord)) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		record, ok, err := getBoltRecord(tx, sessionID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("%w: %s", orchestrator.ErrSessionNotFound, sessionID)
+		}
+		update(&record)
+		return putBoltRecord(tx, record)
+	})
+}
+
+func (m *boltChatMessageManager) AddMessage(message orchestrator.ChatMessage) error {
+	return m.store.updateRecord(m.sessionID, func(record *sessionRecord) {
+		record.Messages = append(record.Messages, message)
+	})
+}
+
+func (m *boltChatMessageManager) GetChatHistory() ([]orchestrator.ChatMessage, error) {
+	history := []orchestrator.ChatMessage{}
+	err := m.store.db.View(func(tx *bolt.Tx) error {
+		record, ok, err := getBoltRecord(tx, m.sessionID)
+		if err != nil || !ok {
+			return err
+		}
+		history = append(history, record.Messages...)
+		return nil
+	})
+	return history, err
+}
+
+func (m *boltChatMessageManager) ClearChatHistory() error {
+	return m.store.updateRecord(m.sessionID, func(record *sessionRecord) {
+		record.Messages = nil
+	})
+}
+
+func getBoltRecord(tx *bolt.Tx, sessionID string) (sessionRecord, bool, error) {
+	var record sessionRecord
+	raw := tx.Bucket(boltSessionsBucket).Get([]byte(sessionID))
+	if raw == nil {
+		return record, false, nil
+	}
+	if err := json.Unmarshal(raw, &record); err != nil {
+		return record, false, fmt.Errorf("decode session %s: %w", sessionID, err)
+	}
+	return record, true, nil
+}
+
+func putBoltRecord(tx *bolt.Tx, record sessionRecord) error {
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("encode session %s: %w", record.SessionID, err)
+	}
+	return tx.Bucket(boltSessionsBucket).Put([]byte(record.SessionID), raw)
+}
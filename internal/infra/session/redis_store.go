@@ -0,0 +1,452 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"rag_imagetotext_texttoimage/internal/application/ports/orchestrator"
+	"rag_imagetotext_texttoimage/internal/util"
+)
+
+var _ orchestrator.ManagedSessionStore = (*RedisSessionStore)(nil)
+
+// redisUpdateAttempts bounds the retries of a metadata update that keeps
+// losing the WATCH race to other writers.
+const redisUpdateAttempts = 16
+
+var errRedisSessionExpired = errors.New("session expired")
+
+// RedisSessionStore keeps session metadata in a string key, the history in a
+// list and every session in a sorted set scored by expiry. The sorted set is
+// what drives expiry and the release callback; the key TTL is only a backstop
+// for sessions left behind when no orchestrator is running. Metadata updates
+// are WATCH transactions, so concurrent writers do not drop each other's
+// changes.
+type RedisSessionStore struct {
+	client            redis.UniversalClient
+	appLogger         util.Logger
+	keyPrefix         string
+	sessionTTL        time.Duration
+	backstopTTL       time.Duration
+	cleanupTicker     *time.Ticker
+	stopCleanupCh     chan struct{}
+	closeOnce         sync.Once
+	mu                sync.RWMutex
+	onSessionReleased func(sessionID string)
+}
+
+type redisChatMessageManager struct {
+	store     *RedisSessionStore
+	sessionID string
+}
+
+// NewRedisSessionStore takes any go-redis client, so a miniredis instance can
+// stand in for a real server.
+func NewRedisSessionStore(client redis.UniversalClient, keyPrefix string, sessionTTL time.Duration, appLogger util.Logger) (*RedisSessionStore, error) {
+	if client == nil {
+		return nil, fmt.Errorf("redis client is nil")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultRequestTimeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("ping redis session store: %w", err)
+	}
+
+	sessionTTL, cleanupInterval := normalizeTTL(sessionTTL)
+	store := &RedisSessionStore{
+		client:        client,
+		appLogger:     appLogger,
+		keyPrefix:     keyPrefix,
+		sessionTTL:    sessionTTL,
+		backstopTTL:   sessionTTL + 2*cleanupInterval,
+		cleanupTicker: time.NewTicker(cleanupInterval),
+		stopCleanupCh: make(chan struct{}),
+	}
+	go store.startCleanupLoop()
+	return store, nil
+}
+
+func (s *RedisSessionStore) CreateSession(sessionID string) error {
+	ctx, cancel := s.requestContext()
+	defer cancel()
+
+	now := time.Now()
+	record := sessionRecord{
+		SessionID: sessionID,
+		Metadata: orchestrator.Metadata{
+			CreatedAt: now,
+			ExpiresAt: now.Add(s.sessionTTL),
+		},
+	}
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("encode session %s: %w", sessionID, err)
+	}
+
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, s.metaKey(sessionID), raw, s.backstopTTL)
+		pipe.Del(ctx, s.messagesKey(sessionID))
+		pipe.ZAdd(ctx, s.expiryKey(), redis.Z{Score: expiryScore(record.ExpiresAt), Member: sessionID})
+		return nil
+	})
+	return err
+}
+
+func (s *RedisSessionStore) GetSession(sessionID string) (orchestrator.SessionData, error) {
+	ctx, cancel := s.requestContext()
+	defer cancel()
+
+	record, err := s.updateRecord(ctx, sessionID, func(record *sessionRecord) error {
+		now := time.Now()
+		if record.expired(now) {
+			return errRedisSessionExpired
+		}
+		record.touch(now, s.sessionTTL)
+		return nil
+	})
+	switch {
+	case errors.Is(err, orchestrator.ErrSessionNotFound):
+		return orchestrator.SessionData{}, nil
+	case errors.Is(err, errRedisSessionExpired):
+		return orchestrator.SessionData{}, s.expire(ctx, sessionID)
+	case err != nil:
+		return orchestrator.SessionData{}, err
+	}
+	return record.toSessionData(&redisChatMessageManager{store: s, sessionID: sessionID}), nil
+}
+
+func (s *RedisSessionStore) DeleteSession(sessionID string) error {
+	ctx, cancel := s.requestContext()
+	defer cancel()
+	return s.expire(ctx, sessionID)
+}
+
+func (s *RedisSessionStore) SessionExists(sessionID string) (bool, error) {
+	ctx, cancel := s.requestContext()
+	defer cancel()
+
+	record, found, err := s.loadRecord(ctx, s.client, sessionID)
+	if err != nil || !found {
+		return false, err
+	}
+	if record.expired(time.Now()) {
+		return false, s.expire(ctx, sessionID)
+	}
+	return true, nil
+}
+
+func (s *RedisSessionStore) ListSessions() ([]orchestrator.SessionSummary, error) {
+	ctx, cancel := s.requestContext()
+	defer cancel()
+
+	sessionIDs, err := s.client.ZRangeByScore(ctx, s.expiryKey(), &redis.ZRangeBy{
+		Min: "(" + strconv.FormatInt(time.Now().UnixMilli(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(sessionIDs) == 0 {
+		return []orchestrator.SessionSummary{}, nil
+	}
+
+	metaCmds := make([]*redis.StringCmd, len(sessionIDs))
+	countCmds := make([]*redis.IntCmd, len(sessionIDs))
+	_, err = s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, sessionID := range sessionIDs {
+			metaCmds[i] = pipe.Get(ctx, s.metaKey(sessionID))
+			countCmds[i] = pipe.LLen(ctx, s.messagesKey(sessionID))
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	summaries := make([]orchestrator.SessionSummary, 0, len(sessionIDs))
+	for i := range sessionIDs {
+		raw, err := metaCmds[i].Bytes()
+		if err != nil {
+			continue
+		}
+		var record sessionRecord
+		if err := json.Unmarshal(raw, &record); err != nil {
+			continue
+		}
+		summaries = append(summaries, record.toSummary(int(countCmds[i].Val())))
+	}
+	return summaries, nil
+}
+
+func (s *RedisSessionStore) ExtendSession(sessionID string, ttl time.Duration) (time.Time, error) {
+	if ttl <= 0 {
+		ttl = s.sessionTTL
+	}
+	ctx, cancel := s.requestContext()
+	defer cancel()
+
+	record, err := s.updateRecord(ctx, sessionID, func(record *sessionRecord) error {
+		now := time.Now()
+		if record.expired(now) {
+			return fmt.Errorf("%w: %s", orchestrator.ErrSessionNotFound, sessionID)
+		}
+		record.ExpiresAt = now.Add(ttl)
+		return nil
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	return record.ExpiresAt, nil
+}
+
+func (s *RedisSessionStore) UpdateMetadata(sessionID string, update func(metadata *orchestrator.Metadata)) error {
+	ctx, cancel := s.requestContext()
+	defer cancel()
+
+	_, err := s.updateRecord(ctx, sessionID, func(record *sessionRecord) error {
+		record.applyMetadata(update)
+		return nil
+	})
+	return err
+}
+
+// Close stops the sweeper and closes the client. Sessions stay in Redis so
+// another orchestrator instance can pick them up.
+func (s *RedisSessionStore) Close() {
+	if s == nil {
+		return
+	}
+	s.closeOnce.Do(func() {
+		close(s.stopCleanupCh)
+		if err := s.client.Close(); err != nil && s.appLogger != nil {
+			s.appLogger.Error("internal.infra.session.RedisSessionStore.Close failed", err)
+		}
+	})
+}
+
+func (s *RedisSessionStore) SetOnSessionReleased(callback func(sessionID string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onSessionReleased = callback
+}
+
+func (s *RedisSessionStore) startCleanupLoop() {
+	for {
+		select {
+		case <-s.cleanupTicker.C:
+			s.cleanupExpiredSessions(time.Now())
+		case <-s.stopCleanupCh:
+			s.cleanupTicker.Stop()
+			return
+		}
+	}
+}
+
+func (s *RedisSessionStore) cleanupExpiredSessions(now time.Time) {
+	ctx, cancel := s.requestContext()
+	defer cancel()
+
+	sessionIDs, err := s.client.ZRangeByScore(ctx, s.expiryKey(), &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(now.UnixMilli(), 10),
+	}).Result()
+	if err != nil {
+		if s.appLogger != nil {
+			s.appLogger.Error("internal.infra.session.RedisSessionStore.cleanupExpiredSessions failed", err)
+		}
+		return
+	}
+	for _, sessionID := range sessionIDs {
+		if err := s.expire(ctx, sessionID); err != nil && s.appLogger != nil {
+			s.appLogger.Error("internal.infra.session.RedisSessionStore.cleanupExpiredSessions expire failed", err, "session_id", sessionID)
+		}
+	}
+}
+
+// expire removes the session and fires the release callback. ZREM decides the
+// winner when several instances race on the same session, so the callback
+// runs once per session across the cluster.
+func (s *RedisSessionStore) expire(ctx context.Context, sessionID string) error {
+	removed, err := s.client.ZRem(ctx, s.expiryKey(), sessionID).Result()
+	if err != nil {
+		return err
+	}
+	if err := s.client.Del(ctx, s.metaKey(sessionID), s.messagesKey(sessionID)).Err(); err != nil {
+		return err
+	}
+	if removed == 1 {
+		s.mu.RLock()
+		callback := s.onSessionReleased
+		s.mu.RUnlock()
+		if callback != nil {
+			callback(sessionID)
+		}
+	}
+	return nil
+}
+
+func (s *RedisSessionStore) loadRecord(ctx context.Context, client redis.Cmdable, sessionID string) (sessionRecord, bool, error) {
+	var record sessionRecord
+	raw, err := client.Get(ctx, s.metaKey(sessionID)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return record, false, nil
+	}
+	if err != nil {
+		return record, false, err
+	}
+	if err := json.Unmarshal(raw, &record); err != nil {
+		return record, false, fmt.Errorf("decode session %s: %w", sessionID, err)
+	}
+	return record, true, nil
+}
+
+// updateRecord is an optimistic read-modify-write of the session metadata:
+// the key is WATCHed while it is read and changed, and the write is retried
+// from a fresh read, after a short random pause, when another client wrote it
+// in between, so update can run more than once. update may return an error to abort without writing; a
+// missing session fails with ErrSessionNotFound.
+func (s *RedisSessionStore) updateRecord(ctx context.Context, sessionID string, update func(record *sessionRecord) error) (sessionRecord, error) {
+	var record sessionRecord
+	for attempt := 0; attempt < redisUpdateAttempts; attempt++ {
+		err := s.client.Watch(ctx, func(tx *redis.Tx) error {
+			current, found, err := s.loadRecord(ctx, tx, sessionID)
+			if err != nil {
+				return err
+			}
+			if !found {
+				return fmt.Errorf("%w: %s", orchestrator.ErrSessionNotFound, sessionID)
+			}
+			if err := update(&current); err != nil {
+				return err
+			}
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				return s.queueSave(ctx, pipe, current)
+			})
+			if err == nil {
+				record = current
+			}
+			return err
+		}, s.metaKey(sessionID))
+		if !errors.Is(err, redis.TxFailedErr) {
+			return record, err
+		}
+		select {
+		case <-ctx.Done():
+			return record, ctx.Err()
+		case <-time.After(time.Duration(rand.Int64N(int64(attempt+1) * int64(time.Millisecond)))):
+		}
+	}
+	return record, fmt.Errorf("update session %s: %w", sessionID, redis.TxFailedErr)
+}
+
+// queueSave writes the metadata and moves the session in the expiry set. The
+// key TTL follows the longer of the session TTL and the record's own expiry so
+// an extended session is not dropped by the backstop.
+func (s *RedisSessionStore) queueSave(ctx context.Context, pipe redis.Pipeliner, record sessionRecord) error {
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("encode session %s: %w", record.SessionID, err)
+	}
+	keyTTL := s.backstopTTL
+	if untilExpiry := time.Until(record.ExpiresAt) + (s.backstopTTL - s.sessionTTL); untilExpiry > keyTTL {
+		keyTTL = untilExpiry
+	}
+	pipe.Set(ctx, s.metaKey(record.SessionID), raw, keyTTL)
+	pipe.PExpire(ctx, s.messagesKey(record.SessionID), keyTTL)
+	pipe.ZAdd(ctx, s.expiryKey(), redis.Z{Score: expiryScore(record.ExpiresAt), Member: record.SessionID})
+	return nil
+}
+
+func (s *RedisSessionStore) requireSession(ctx context.Context, sessionID string) error {
+	exists, err := s.client.Exists(ctx, s.metaKey(sessionID)).Result()
+	if err != nil {
+		return err
+	}
+	if exists == 0 {
+		return fmt.Errorf("%w: %s", orchestrator.ErrSessionNotFound, sessionID)
+	}
+	return nil
+}
+
+func (s *RedisSessionStore) requestContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), defaultRequestTimeout)
+}
+
+func (s *RedisSessionStore) metaKey(sessionID string) string {
+	return s.keyPrefix + "session:" + sessionID
+}
+
+func (s *RedisSessionStore) messagesKey(sessionID string) string {
+	return s.keyPrefix + "session:" + sessionID + ":messages"
+}
+
+func (s *RedisSessionStore) expiryKey() string {
+	return s.keyPrefix + "sessions:expiry"
+}
+
+func (m *redisChatMessageManager) AddMessage(message orchestrator.ChatMessage) error {
+	ctx, cancel := m.store.requestContext()
+	defer cancel()
+
+	// The history takes the TTL of the metadata key, which outlives the
+	// backstop when the session was extended.
+	keyTTL, err := m.store.client.PTTL(ctx, m.store.metaKey(m.sessionID)).Result()
+	if err != nil {
+		return err
+	}
+	if keyTTL == -2 {
+		return fmt.Errorf("%w: %s", orchestrator.ErrSessionNotFound, m.sessionID)
+	}
+	keyTTL = max(keyTTL, m.store.backstopTTL)
+	raw, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("encode chat message: %w", err)
+	}
+	_, err = m.store.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.RPush(ctx, m.store.messagesKey(m.sessionID), raw)
+		pipe.PExpire(ctx, m.store.messagesKey(m.sessionID), keyTTL)
+		return nil
+	})
+	return err
+}
+
+func (m *redisChatMessageManager) GetChatHistory() ([]orchestrator.ChatMessage, error) {
+	ctx, cancel := m.store.requestContext()
+	defer cancel()
+
+	rawMessages, err := m.store.client.LRange(ctx, m.store.messagesKey(m.sessionID), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	history := make([]orchestrator.ChatMessage, 0, len(rawMessages))
+	for _, raw := range rawMessages {
+		var message orchestrator.ChatMessage
+		if err := json.Unmarshal([]byte(raw), &message); err != nil {
+			return nil, fmt.Errorf("decode chat message: %w", err)
+		}
+		history = append(history, message)
+	}
+	return history, nil
+}
+
+func (m *redisChatMessageManager) ClearChatHistory() error {
+	ctx, cancel := m.store.requestContext()
+	defer cancel()
+
+	if err := m.store.requireSession(ctx, m.sessionID); err != nil {
+		return err
+	}
+	return m.store.client.Del(ctx, m.store.messagesKey(m.sessionID)).Err()
+}
+
+func expiryScore(expiresAt time.Time) float64 {
+	return float64(expiresAt.UnixMilli())
+}
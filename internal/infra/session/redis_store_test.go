@@ -0,0 +1,103 @@
+package session
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"rag_imagetotext_texttoimage/internal/application/ports/orchestrator"
+)
+
+func newTestRedisStore(t *testing.T, sessionTTL time.Duration) (*RedisSessionStore, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	store, err := NewRedisSessionStore(redis.NewClient(&redis.Options{Addr: server.Addr()}), "test:", sessionTTL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(store.Close)
+	return store, server
+}
+
+func TestRedisSessionStoreConcurrentUpdates(t *testing.T) {
+	store, _ := newTestRedisStore(t, time.Hour)
+	if err := store.CreateSession("s1"); err != nil {
+		t.Fatal(err)
+	}
+
+	const writers = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			errs <- store.UpdateMetadata("s1", func(metadata *orchestrator.Metadata) {
+				metadata.SummarizedMessages++
+			})
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := store.GetSession("s1")
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := store.GetSession("s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Metadata.SummarizedMessages != writers {
+		t.Fatalf("SummarizedMessages = %d, want %d: updates were lost", data.Metadata.SummarizedMessages, writers)
+	}
+}
+
+func TestRedisSessionStoreGetKeepsExtendedTTL(t *testing.T) {
+	store, server := newTestRedisStore(t, time.Hour)
+	if err := store.CreateSession("s1"); err != nil {
+		t.Fatal(err)
+	}
+	extended, err := store.ExtendSession("s1", 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := store.GetSession("s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Metadata.ExpiresAt.Before(extended) {
+		t.Fatalf("GetSession moved expiry from %v back to %v", extended, data.Metadata.ExpiresAt)
+	}
+
+	history := data.ChatHistory
+	if err := history.AddMessage(orchestrator.ChatMessage{}); err != nil {
+		t.Fatal(err)
+	}
+	if ttl := server.TTL(store.messagesKey("s1")); ttl < 24*time.Hour {
+		t.Fatalf("history TTL = %v, shorter than the extended session", ttl)
+	}
+}
+
+func TestRedisSessionStoreMissingSession(t *testing.T) {
+	store, _ := newTestRedisStore(t, time.Hour)
+	data, err := store.GetSession("missing")
+	if err != nil || data.SessionID != "" {
+		t.Fatalf("GetSession = %+v, %v", data, err)
+	}
+	if _, err := store.ExtendSession("missing", time.Hour); err == nil {
+		t.Fatal("ExtendSession of a missing session succeeded")
+	}
+	if err := store.UpdateMetadata("missing", func(*orchestrator.Metadata) {}); err == nil {
+		t.Fatal("UpdateMetadata of a missing session succeeded")
+	}
+}
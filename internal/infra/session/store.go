@@ -0,0 +1,77 @@
+package session
+
+import (
+	"time"
+
+	"rag_imagetotext_texttoimage/internal/application/ports/orchestrator"
+)
+
+const (
+	defaultSessionTTL     = 30 * time.Minute
+	minCleanupInterval    = 10 * time.Second
+	defaultRequestTimeout = 5 * time.Second
+)
+
+type sessionRecord struct {
+	SessionID string `json:"session_id"`
+	orchestrator.Metadata
+	Messages []orchestrator.ChatMessage `json:"messages,omitempty"`
+}
+
+func (r sessionRecord) expired(now time.Time) bool {
+	return now.After(r.ExpiresAt)
+}
+
+// touch slides the expiry to now+ttl on access. It never shortens an expiry
+// that ExtendSession set further out.
+func (r *sessionRecord) touch(now time.Time, ttl time.Duration) {
+	if next := now.Add(ttl); next.After(r.ExpiresAt) {
+		r.ExpiresAt = next
+	}
+}
+
+func (r sessionRecord) toSessionData(history orchestrator.ChatMessageManager) orchestrator.SessionData {
+	return orchestrator.SessionData{
+		SessionID:   r.SessionID,
+		ChatHistory: history,
+		Metadata:    r.Metadata,
+	}
+}
+
+func (r sessionRecord) toSummary(messageCount int) orchestrator.SessionSummary {
+	return orchestrator.SessionSummary{
+		SessionID:    r.SessionID,
+		Metadata:     r.Metadata,
+		MessageCount: messageCount,
+	}
+}
+
+// applyMetadata runs update on a copy so the store keeps ownership of the
+// timestamps.
+func (r *sessionRecord) applyMetadata(update func(metadata *orchestrator.Metadata)) {
+	metadata := r.Metadata
+	update(&metadata)
+	metadata.CreatedAt = r.CreatedAt
+	metadata.ExpiresAt = r.ExpiresAt
+	r.Metadata = metadata
+}
+
+func normalizeTTL(sessionTTL time.Duration) (time.Duration, time.Duration) {
+	if sessionTTL <= 0 {
+		sessionTTL = defaultSessionTTL
+	}
+	cleanupInterval := sessionTTL / 2
+	if cleanupInterval < minCleanupInterval {
+		cleanupInterval = minCleanupInterval
+	}
+	return sessionTTL, cleanupInterval
+}
+
+func releaseAll(callback func(sessionID string), sessionIDs []string) {
+	if callback == nil {
+		return
+	}
+	for _, sessionID := range sessionIDs {
+		callback(sessionID)
+	}
+}
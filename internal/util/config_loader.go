@@ -24,13 +24,89 @@ type Config struct {
 }
 
 type OrchestratorSettings struct {
-	Port              string        `yaml:"port"`
-	LogPath           string        `yaml:"log_path"`
-	SessionTTLSeconds int           `yaml:"session_ttl_seconds"`
-	MemoryHistoryTopK int           `yaml:"memory_history_top_k"`
-	RAGRetrievalTopK  int           `yaml:"rag_retrieval_top_k"`
-	PreProcessing     PreProcessing `yaml:"pre_processing"`
-	Vectordb          VectordbSetup `yaml:"vectordb"`
+	Port              string                `yaml:"port"`
+	LogPath           string                `yaml:"log_path"`
+	SessionTTLSeconds int                   `yaml:"session_ttl_seconds"`
+	MemoryHistoryTopK int                   `yaml:"memory_history_top_k"`
+	RAGRetrievalTopK  int                   `yaml:"rag_retrieval_top_k"`
+	PreProcessing     PreProcessing         `yaml:"pre_processing"`
+	Vectordb          VectordbSetup         `yaml:"vectordb"`
+	SessionStore      SessionStoreSettings  `yaml:"session_store"`
+	Retrieval         RetrievalSettings     `yaml:"retrieval"`
+	Rerank            RerankSettings        `yaml:"rerank"`
+	Context           ContextSettings       `yaml:"context"`
+	MemorySummary     MemorySummarySettings `yaml:"memory_summary"`
+	Intent            IntentSettings        `yaml:"intent"`
+	IngestJobs        IngestJobSettings     `yaml:"ingest_jobs"`
+	Workspaces        map[string][]string   `yaml:"workspaces"`
+}
+
+// IngestJobSettings sizes the background process-and-ingest worker pool.
+// Finished jobs stay queryable for RetentionSeconds.
+type IngestJobSettings struct {
+	Workers          int `yaml:"workers"`
+	QueueSize        int `yaml:"queue_size"`
+	RetentionSeconds int `yaml:"retention_seconds"`
+}
+
+// ContextSettings bounds the retrieved context sent to the answer LLM.
+// StageBudgets overrides TokenBudget per retrieval stage (new_query,
+// current_query, multimodal_query).
+type ContextSettings struct {
+	TokenBudget            int            `yaml:"token_budget"`
+	StageBudgets           map[string]int `yaml:"stage_budgets"`
+	NearDuplicateThreshold float64        `yaml:"near_duplicate_threshold"`
+}
+
+// IntentSettings configures the chat intent router. Rules are checked in
+// order before the intent returned by the preprocessing LLM.
+type IntentSettings struct {
+	Default        string       `yaml:"default"`
+	Rules          []IntentRule `yaml:"rules"`
+	RefusalMessage string       `yaml:"refusal_message"`
+	SummaryTopK    int          `yaml:"summary_top_k"`
+}
+
+// IntentRule matches when one of Patterns appears in the normalized query as
+// whole words. MaxWords > 0 limits the rule to short queries.
+type IntentRule struct {
+	Intent   string   `yaml:"intent"`
+	Patterns []string `yaml:"patterns"`
+	MaxWords int      `yaml:"max_words"`
+}
+
+type MemorySummarySettings struct {
+	Model       string  `yaml:"model"`
+	Temperature float32 `yaml:"temperature"`
+	MaxChars    int     `yaml:"max_chars"`
+}
+
+type RerankSettings struct {
+	Strategy      string  `yaml:"strategy"`
+	CandidateTopK int     `yaml:"candidate_top_k"`
+	TopN          int     `yaml:"top_n"`
+	Model         string  `yaml:"model"`
+	Temperature   float32 `yaml:"temperature"`
+}
+
+type RetrievalSettings struct {
+	Fusion      RetrievalFusion            `yaml:"fusion"`
+	Collections map[string]RetrievalFusion `yaml:"collections"`
+}
+
+type RetrievalFusion struct {
+	Strategy      string             `yaml:"strategy"`
+	RRFK          float32            `yaml:"rrf_k"`
+	VectorWeights map[string]float32 `yaml:"vector_weights"`
+}
+
+type SessionStoreSettings struct {
+	Backend        string `yaml:"backend"`
+	BoltPath       string `yaml:"bolt_path"`
+	RedisAddr      string `yaml:"redis_addr"`
+	RedisPassword  string `yaml:"redis_password"`
+	RedisDB        int    `yaml:"redis_db"`
+	RedisKeyPrefix string `yaml:"redis_key_prefix"`
 }
 
 type VectordbSetup struct {
@@ -89,16 +165,64 @@ type MinIOTopics struct {
 }
 
 type KafkaConfig struct {
-	Brokers []string `yaml:"brokers"`
+	Brokers []string           `yaml:"brokers"`
+	Retry   KafkaRetrySettings `yaml:"retry"`
+}
+
+// KafkaRetrySettings drives the retry topics of the Kafka handlers: a failed
+// message is retried max_retries times with exponential backoff before it is
+// moved to <topic>.dlq.
+type KafkaRetrySettings struct {
+	MaxRetries       int `yaml:"max_retries"`
+	InitialBackoffMs int `yaml:"initial_backoff_ms"`
+	MaxBackoffMs     int `yaml:"max_backoff_ms"`
 }
 
 type EmbeddingSettings struct {
-	Port           string          `yaml:"port"`
-	IDMonitoring   string          `yaml:"id_monitoring"`
-	PortMetricGRPC string          `yaml:"port_metric_grpc"`
-	LogPath        string          `yaml:"log_path"`
-	JinaConfigPath string          `yaml:"jina_config_path"`
-	Topics         EmbeddingTopics `yaml:"topics"`
+	Port           string                   `yaml:"port"`
+	IDMonitoring   string                   `yaml:"id_monitoring"`
+	PortMetricGRPC string                   `yaml:"port_metric_grpc"`
+	LogPath        string                   `yaml:"log_path"`
+	JinaConfigPath string                   `yaml:"jina_config_path"`
+	Backend        string                   `yaml:"backend"`
+	HTTP           EmbeddingHTTPSettings    `yaml:"http"`
+	Hashing        EmbeddingHashingSettings `yaml:"hashing"`
+	Cache          EmbeddingCacheSettings   `yaml:"cache"`
+	GRPCBatching   EmbeddingBatchSettings   `yaml:"grpc_batching"`
+	Topics         EmbeddingTopics          `yaml:"topics"`
+}
+
+// EmbeddingHTTPSettings configures the openai and tei embedding backends.
+type EmbeddingHTTPSettings struct {
+	BaseURL   string `yaml:"base_url"`
+	APIKey    string `yaml:"api_key"`
+	Model     string `yaml:"model"`
+	TimeoutMs int    `yaml:"timeout_ms"`
+	MaxBatch  int    `yaml:"max_batch"`
+}
+
+type EmbeddingHashingSettings struct {
+	Dimension int `yaml:"dimension"`
+}
+
+// EmbeddingCacheSettings configures the content-addressed embedding cache.
+// An empty ModelVersion is derived from the backend settings; DiskPath empty
+// keeps the cache in memory only.
+type EmbeddingCacheSettings struct {
+	Enabled      bool   `yaml:"enabled"`
+	ModelVersion string `yaml:"model_version"`
+	MaxEntries   int    `yaml:"max_entries"`
+	DiskPath     string `yaml:"disk_path"`
+}
+
+// EmbeddingBatchSettings configures how concurrent gRPC embedding requests
+// are coalesced: a batch runs after MaxWaitMs or once it holds MaxBatch
+// requests, with at most MaxInFlight batches running at a time.
+type EmbeddingBatchSettings struct {
+	Enabled     bool `yaml:"enabled"`
+	MaxWaitMs   int  `yaml:"max_wait_ms"`
+	MaxBatch    int  `yaml:"max_batch"`
+	MaxInFlight int  `yaml:"max_in_flight"`
 }
 
 type EmbeddingTopics struct {
@@ -133,18 +257,46 @@ type FileTrainingTopics struct {
 }
 
 type FileTrainingSettings struct {
-	Port                        string             `yaml:"port"`
-	IDMonitoring                string             `yaml:"id_monitoring"`
-	PortMetricGRPC              string             `yaml:"port_metric_grpc"`
-	LogPath                     string             `yaml:"log_path"`
-	BatchSize                   int                `yaml:"batch_size"`
-	MarkerDevMode               bool               `yaml:"marker_dev_mode"`
-	MinChunkChars               int                `yaml:"min_chunk_chars"`
-	MinChunkTokens              int                `yaml:"min_chunk_tokens"`
-	MaxChunkTokens              int                `yaml:"max_chunk_tokens"`
-	SemanticSimilarityThreshold float32            `yaml:"semantic_similarity_threshold"`
-	ChunkOverlapSentences       int                `yaml:"chunk_overlap_sentences"`
-	Topics                      FileTrainingTopics `yaml:"topics"`
+	Port                        string                 `yaml:"port"`
+	IDMonitoring                string                 `yaml:"id_monitoring"`
+	PortMetricGRPC              string                 `yaml:"port_metric_grpc"`
+	LogPath                     string                 `yaml:"log_path"`
+	BatchSize                   int                    `yaml:"batch_size"`
+	MarkerDevMode               bool                   `yaml:"marker_dev_mode"`
+	MinChunkChars               int                    `yaml:"min_chunk_chars"`
+	MinChunkTokens              int                    `yaml:"min_chunk_tokens"`
+	MaxChunkTokens              int                    `yaml:"max_chunk_tokens"`
+	SemanticSimilarityThreshold float32                `yaml:"semantic_similarity_threshold"`
+	ChunkOverlapSentences       int                    `yaml:"chunk_overlap_sentences"`
+	MaxTableRows                int                    `yaml:"max_table_rows"`
+	ImageBatchSize              int                    `yaml:"image_batch_size"`
+	ImageBatchConcurrency       int                    `yaml:"image_batch_concurrency"`
+	DocumentParser              DocumentParserSettings `yaml:"document_parser"`
+	FigureCaption               FigureCaptionSettings  `yaml:"figure_caption"`
+	Topics                      FileTrainingTopics     `yaml:"topics"`
+}
+
+// DocumentParserSettings picks the parser per file type. PDF is "marker",
+// "pdf_text" or "auto" (marker when marker_single is installed); Default is
+// used for types without a native parser; Extensions overrides both, keyed by
+// extension with the dot (".pdf": "pdf_text").
+type DocumentParserSettings struct {
+	PDF        string            `yaml:"pdf"`
+	Default    string            `yaml:"default"`
+	Extensions map[string]string `yaml:"extensions"`
+}
+
+// FigureCaptionSettings controls the optional ingest step that asks the LLM
+// service to caption every figure. MaxPerDocument caps the LLM calls of one
+// document; cached captions (CacheDir, keyed by image content, model and
+// prompt) do not count against it.
+type FigureCaptionSettings struct {
+	Enabled        bool   `yaml:"enabled"`
+	Model          string `yaml:"model"`
+	Prompt         string `yaml:"prompt"`
+	MaxPerDocument int    `yaml:"max_per_document"`
+	TimeoutSeconds int    `yaml:"timeout_seconds"`
+	CacheDir       string `yaml:"cache_dir"`
 }
 
 func (m MinIOSettings) Bucket(name string) string {
@@ -395,6 +547,34 @@ func (c *ConfigLoader) applyEnvOverrides() {
 			c.config.FileTraining.ChunkOverlapSentences = parsed
 		}
 	}
+	if v := firstNonEmptyEnv("PROCESS_FILE_SERVICE_MAX_TABLE_ROWS"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			c.config.FileTraining.MaxTableRows = parsed
+		}
+	}
+	if v := firstNonEmptyEnv("PROCESS_FILE_SERVICE_IMAGE_BATCH_SIZE"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			c.config.FileTraining.ImageBatchSize = parsed
+		}
+	}
+	if v := firstNonEmptyEnv("PROCESS_FILE_SERVICE_IMAGE_BATCH_CONCURRENCY"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			c.config.FileTraining.ImageBatchConcurrency = parsed
+		}
+	}
+	if v := firstNonEmptyEnv("PROCESS_FILE_SERVICE_FIGURE_CAPTION_ENABLED"); v != "" {
+		if parsed, err := strconv.ParseBool(v); err == nil {
+			c.config.FileTraining.FigureCaption.Enabled = parsed
+		}
+	}
+	if v := firstNonEmptyEnv("PROCESS_FILE_SERVICE_FIGURE_CAPTION_MAX_PER_DOCUMENT"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			c.config.FileTraining.FigureCaption.MaxPerDocument = parsed
+		}
+	}
+	if v := firstNonEmptyEnv("PROCESS_FILE_SERVICE_PDF_PARSER"); v != "" {
+		c.config.FileTraining.DocumentParser.PDF = v
+	}
 
 	if v := firstNonEmptyEnv("ORCHESTRATOR_SERVICE_SESSION_TTL_SECONDS", "ORCHESTRATOR_SESSION_TTL_SECONDS"); v != "" {
 		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
@@ -447,6 +627,154 @@ func (c *ConfigLoader) applyEnvOverrides() {
 	if v := firstNonEmptyEnv("ORCHESTRATOR_VECTORDB_IMAGE_VECTOR_DISTANCE"); v != "" {
 		c.config.OrchestratorService.Vectordb.ImageVectorDistance = v
 	}
+	if v := firstNonEmptyEnv("ORCHESTRATOR_RETRIEVAL_FUSION_STRATEGY"); v != "" {
+		c.config.OrchestratorService.Retrieval.Fusion.Strategy = strings.ToLower(v)
+	}
+	if v := firstNonEmptyEnv("ORCHESTRATOR_RETRIEVAL_RRF_K"); v != "" {
+		if parsed, err := strconv.ParseFloat(v, 32); err == nil && parsed > 0 {
+			c.config.OrchestratorService.Retrieval.Fusion.RRFK = float32(parsed)
+		}
+	}
+	if v := firstNonEmptyEnv("ORCHESTRATOR_RERANK_STRATEGY"); v != "" {
+		c.config.OrchestratorService.Rerank.Strategy = strings.ToLower(v)
+	}
+	if v := firstNonEmptyEnv("ORCHESTRATOR_RERANK_CANDIDATE_TOP_K"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			c.config.OrchestratorService.Rerank.CandidateTopK = parsed
+		}
+	}
+	if v := firstNonEmptyEnv("ORCHESTRATOR_RERANK_TOP_N"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			c.config.OrchestratorService.Rerank.TopN = parsed
+		}
+	}
+	if v := firstNonEmptyEnv("ORCHESTRATOR_INTENT_DEFAULT"); v != "" {
+		c.config.OrchestratorService.Intent.Default = strings.ToLower(v)
+	}
+	if v := firstNonEmptyEnv("ORCHESTRATOR_MEMORY_SUMMARY_MODEL"); v != "" {
+		c.config.OrchestratorService.MemorySummary.Model = v
+	}
+	if v := firstNonEmptyEnv("ORCHESTRATOR_MEMORY_SUMMARY_MAX_CHARS"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			c.config.OrchestratorService.MemorySummary.MaxChars = parsed
+		}
+	}
+	if v := firstNonEmptyEnv("ORCHESTRATOR_CONTEXT_TOKEN_BUDGET"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			c.config.OrchestratorService.Context.TokenBudget = parsed
+		}
+	}
+	if v := firstNonEmptyEnv("ORCHESTRATOR_INGEST_JOBS_WORKERS"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			c.config.OrchestratorService.IngestJobs.Workers = parsed
+		}
+	}
+	if v := firstNonEmptyEnv("ORCHESTRATOR_INGEST_JOBS_QUEUE_SIZE"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			c.config.OrchestratorService.IngestJobs.QueueSize = parsed
+		}
+	}
+	if v := firstNonEmptyEnv("ORCHESTRATOR_RERANK_MODEL"); v != "" {
+		c.config.OrchestratorService.Rerank.Model = v
+	}
+	if v := firstNonEmptyEnv("ORCHESTRATOR_SESSION_STORE_BACKEND"); v != "" {
+		c.config.OrchestratorService.SessionStore.Backend = strings.ToLower(v)
+	}
+	if v := firstNonEmptyEnv("ORCHESTRATOR_SESSION_STORE_BOLT_PATH"); v != "" {
+		c.config.OrchestratorService.SessionStore.BoltPath = v
+	}
+	if v := firstNonEmptyEnv("ORCHESTRATOR_SESSION_STORE_REDIS_ADDR", "REDIS_ADDR"); v != "" {
+		c.config.OrchestratorService.SessionStore.RedisAddr = v
+	}
+	if v := firstNonEmptyEnv("ORCHESTRATOR_SESSION_STORE_REDIS_PASSWORD", "REDIS_PASSWORD"); v != "" {
+		c.config.OrchestratorService.SessionStore.RedisPassword = v
+	}
+	if v := firstNonEmptyEnv("ORCHESTRATOR_SESSION_STORE_REDIS_DB"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed >= 0 {
+			c.config.OrchestratorService.SessionStore.RedisDB = parsed
+		}
+	}
+	if v := firstNonEmptyEnv("ORCHESTRATOR_SESSION_STORE_REDIS_KEY_PREFIX"); v != "" {
+		c.config.OrchestratorService.SessionStore.RedisKeyPrefix = v
+	}
+	if v := firstNonEmptyEnv("EMBEDDING_SERVICE_BACKEND"); v != "" {
+		c.config.EmbeddingService.Backend = strings.ToLower(v)
+	}
+	if v := firstNonEmptyEnv("EMBEDDING_SERVICE_HTTP_BASE_URL"); v != "" {
+		c.config.EmbeddingService.HTTP.BaseURL = v
+	}
+	if v := firstNonEmptyEnv("EMBEDDING_SERVICE_HTTP_API_KEY"); v != "" {
+		c.config.EmbeddingService.HTTP.APIKey = v
+	}
+	if v := firstNonEmptyEnv("EMBEDDING_SERVICE_HTTP_MODEL"); v != "" {
+		c.config.EmbeddingService.HTTP.Model = v
+	}
+	if v := firstNonEmptyEnv("EMBEDDING_SERVICE_HTTP_TIMEOUT_MS"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			c.config.EmbeddingService.HTTP.TimeoutMs = parsed
+		}
+	}
+	if v := firstNonEmptyEnv("EMBEDDING_SERVICE_HTTP_MAX_BATCH"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			c.config.EmbeddingService.HTTP.MaxBatch = parsed
+		}
+	}
+	if v := firstNonEmptyEnv("EMBEDDING_SERVICE_HASHING_DIMENSION"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			c.config.EmbeddingService.Hashing.Dimension = parsed
+		}
+	}
+	if v := firstNonEmptyEnv("EMBEDDING_SERVICE_CACHE_ENABLED"); v != "" {
+		if parsed, err := strconv.ParseBool(v); err == nil {
+			c.config.EmbeddingService.Cache.Enabled = parsed
+		}
+	}
+	if v := firstNonEmptyEnv("EMBEDDING_SERVICE_CACHE_MODEL_VERSION"); v != "" {
+		c.config.EmbeddingService.Cache.ModelVersion = v
+	}
+	if v := firstNonEmptyEnv("EMBEDDING_SERVICE_CACHE_MAX_ENTRIES"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			c.config.EmbeddingService.Cache.MaxEntries = parsed
+		}
+	}
+	if v := firstNonEmptyEnv("EMBEDDING_SERVICE_CACHE_DISK_PATH"); v != "" {
+		c.config.EmbeddingService.Cache.DiskPath = v
+	}
+	if v := firstNonEmptyEnv("EMBEDDING_SERVICE_GRPC_BATCHING_ENABLED"); v != "" {
+		if parsed, err := strconv.ParseBool(v); err == nil {
+			c.config.EmbeddingService.GRPCBatching.Enabled = parsed
+		}
+	}
+	if v := firstNonEmptyEnv("EMBEDDING_SERVICE_GRPC_BATCHING_MAX_WAIT_MS"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed >= 0 {
+			c.config.EmbeddingService.GRPCBatching.MaxWaitMs = parsed
+		}
+	}
+	if v := firstNonEmptyEnv("EMBEDDING_SERVICE_GRPC_BATCHING_MAX_BATCH"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			c.config.EmbeddingService.GRPCBatching.MaxBatch = parsed
+		}
+	}
+	if v := firstNonEmptyEnv("EMBEDDING_SERVICE_GRPC_BATCHING_MAX_IN_FLIGHT"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			c.config.EmbeddingService.GRPCBatching.MaxInFlight = parsed
+		}
+	}
+	if v := firstNonEmptyEnv("KAFKA_RETRY_MAX_RETRIES"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed >= 0 {
+			c.config.Kafka.Retry.MaxRetries = parsed
+		}
+	}
+	if v := firstNonEmptyEnv("KAFKA_RETRY_INITIAL_BACKOFF_MS"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			c.config.Kafka.Retry.InitialBackoffMs = parsed
+		}
+	}
+	if v := firstNonEmptyEnv("KAFKA_RETRY_MAX_BACKOFF_MS"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			c.config.Kafka.Retry.MaxBackoffMs = parsed
+		}
+	}
 }
 
 func (c *ConfigLoader) GetConfig() *Config {
@@ -285,6 +285,66 @@ func (x *LLMResponse) GetJson() map[string]string {
 	return nil
 }
 
+type LLMStreamChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Delta         string                 `protobuf:"bytes,1,opt,name=delta,proto3" json:"delta,omitempty"`
+	Done          bool                   `protobuf:"varint,2,opt,name=done,proto3" json:"done,omitempty"`
+	Text          string                 `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LLMStreamChunk) Reset() {
+	*x = LLMStreamChunk{}
+	mi := &file_llm_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LLMStreamChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LLMStreamChunk) ProtoMessage() {}
+
+func (x *LLMStreamChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_llm_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LLMStreamChunk.ProtoReflect.Descriptor instead.
+func (*LLMStreamChunk) Descriptor() ([]byte, []int) {
+	return file_llm_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *LLMStreamChunk) GetDelta() string {
+	if x != nil {
+		return x.Delta
+	}
+	return ""
+}
+
+func (x *LLMStreamChunk) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
+func (x *LLMStreamChunk) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
 var File_llm_service_proto protoreflect.FileDescriptor
 
 const file_llm_service_proto_rawDesc = "" +
@@ -318,11 +378,17 @@ const file_llm_service_proto_rawDesc = "" +
 	"\x04json\x18\x02 \x03(\v2\x16.LLMResponse.JsonEntryR\x04json\x1a7\n" +
 	"\tJsonEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x012~\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"N\n" +
+	"\x0eLLMStreamChunk\x12\x14\n" +
+	"\x05delta\x18\x01 \x01(\tR\x05delta\x12\x12\n" +
+	"\x04done\x18\x02 \x01(\bR\x04done\x12\x12\n" +
+	"\x04text\x18\x03 \x01(\tR\x04text2\x86\x02\n" +
 	"\n" +
 	"LlmService\x126\n" +
 	"\x12GenerateTextToText\x12\x12.TextToTextRequest\x1a\f.LLMResponse\x128\n" +
-	"\x13GenerateTextToImage\x12\x13.TextToImageRequest\x1a\f.LLMResponseB)Z'rag_imagetotext_texttoimage/proto;protob\x06proto3"
+	"\x13GenerateTextToImage\x12\x13.TextToImageRequest\x1a\f.LLMResponse\x12A\n" +
+	"\x18GenerateTextToTextStream\x12\x12.TextToTextRequest\x1a\x0f.LLMStreamChunk0\x01\x12C\n" +
+	"\x19GenerateTextToImageStream\x12\x13.TextToImageRequest\x1a\x0f.LLMStreamChunk0\x01B)Z'rag_imagetotext_texttoimage/proto;protob\x06proto3"
 
 var (
 	file_llm_service_proto_rawDescOnce sync.Once
@@ -336,28 +402,33 @@ func file_llm_service_proto_rawDescGZIP() []byte {
 	return file_llm_service_proto_rawDescData
 }
 
-var file_llm_service_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_llm_service_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_llm_service_proto_goTypes = []any{
 	(*TextToTextRequest)(nil),  // 0: TextToTextRequest
 	(*TextToImageRequest)(nil), // 1: TextToImageRequest
 	(*ChatHistory)(nil),        // 2: ChatHistory
 	(*LLMResponse)(nil),        // 3: LLMResponse
-	nil,                        // 4: TextToTextRequest.StructureOutputEntry
-	nil,                        // 5: TextToImageRequest.StructureOutputEntry
-	nil,                        // 6: LLMResponse.JsonEntry
+	(*LLMStreamChunk)(nil),     // 4: LLMStreamChunk
+	nil,                        // 5: TextToTextRequest.StructureOutputEntry
+	nil,                        // 6: TextToImageRequest.StructureOutputEntry
+	nil,                        // 7: LLMResponse.JsonEntry
 }
 var file_llm_service_proto_depIdxs = []int32{
 	2, // 0: TextToTextRequest.history:type_name -> ChatHistory
-	4, // 1: TextToTextRequest.structure_output:type_name -> TextToTextRequest.StructureOutputEntry
+	5, // 1: TextToTextRequest.structure_output:type_name -> TextToTextRequest.StructureOutputEntry
 	2, // 2: TextToImageRequest.history:type_name -> ChatHistory
-	5, // 3: TextToImageRequest.structure_output:type_name -> TextToImageRequest.StructureOutputEntry
-	6, // 4: LLMResponse.json:type_name -> LLMResponse.JsonEntry
+	6, // 3: TextToImageRequest.structure_output:type_name -> TextToImageRequest.StructureOutputEntry
+	7, // 4: LLMResponse.json:type_name -> LLMResponse.JsonEntry
 	0, // 5: LlmService.GenerateTextToText:input_type -> TextToTextRequest
 	1, // 6: LlmService.GenerateTextToImage:input_type -> TextToImageRequest
-	3, // 7: LlmService.GenerateTextToText:output_type -> LLMResponse
-	3, // 8: LlmService.GenerateTextToImage:output_type -> LLMResponse
-	7, // [7:9] is the sub-list for method output_type
-	5, // [5:7] is the sub-list for method input_type
+	0, // 7: LlmService.GenerateTextToTextStream:input_type -> TextToTextRequest
+	1, // 8: LlmService.GenerateTextToImageStream:input_type -> TextToImageRequest
+	3, // 9: LlmService.GenerateTextToText:output_type -> LLMResponse
+	3, // 10: LlmService.GenerateTextToImage:output_type -> LLMResponse
+	4, // 11: LlmService.GenerateTextToTextStream:output_type -> LLMStreamChunk
+	4, // 12: LlmService.GenerateTextToImageStream:output_type -> LLMStreamChunk
+	9, // [9:13] is the sub-list for method output_type
+	5, // [5:9] is the sub-list for method input_type
 	5, // [5:5] is the sub-list for extension type_name
 	5, // [5:5] is the sub-list for extension extendee
 	0, // [0:5] is the sub-list for field type_name
@@ -374,7 +445,7 @@ func file_llm_service_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_llm_service_proto_rawDesc), len(file_llm_service_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   7,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,8 +19,10 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	LlmService_GenerateTextToText_FullMethodName  = "/LlmService/GenerateTextToText"
-	LlmService_GenerateTextToImage_FullMethodName = "/LlmService/GenerateTextToImage"
+	LlmService_GenerateTextToText_FullMethodName        = "/LlmService/GenerateTextToText"
+	LlmService_GenerateTextToImage_FullMethodName       = "/LlmService/GenerateTextToImage"
+	LlmService_GenerateTextToTextStream_FullMethodName  = "/LlmService/GenerateTextToTextStream"
+	LlmService_GenerateTextToImageStream_FullMethodName = "/LlmService/GenerateTextToImageStream"
 )
 
 // LlmServiceClient is the client API for LlmService service.
@@ -29,6 +31,8 @@ const (
 type LlmServiceClient interface {
 	GenerateTextToText(ctx context.Context, in *TextToTextRequest, opts ...grpc.CallOption) (*LLMResponse, error)
 	GenerateTextToImage(ctx context.Context, in *TextToImageRequest, opts ...grpc.CallOption) (*LLMResponse, error)
+	GenerateTextToTextStream(ctx context.Context, in *TextToTextRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LLMStreamChunk], error)
+	GenerateTextToImageStream(ctx context.Context, in *TextToImageRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LLMStreamChunk], error)
 }
 
 type llmServiceClient struct {
@@ -59,12 +63,52 @@ func (c *llmServiceClient) GenerateTextToImage(ctx context.Context, in *TextToIm
 	return out, nil
 }
 
+func (c *llmServiceClient) GenerateTextToTextStream(ctx context.Context, in *TextToTextRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LLMStreamChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LlmService_ServiceDesc.Streams[0], LlmService_GenerateTextToTextStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[TextToTextRequest, LLMStreamChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LlmService_GenerateTextToTextStreamClient = grpc.ServerStreamingClient[LLMStreamChunk]
+
+func (c *llmServiceClient) GenerateTextToImageStream(ctx context.Context, in *TextToImageRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LLMStreamChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LlmService_ServiceDesc.Streams[1], LlmService_GenerateTextToImageStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[TextToImageRequest, LLMStreamChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LlmService_GenerateTextToImageStreamClient = grpc.ServerStreamingClient[LLMStreamChunk]
+
 // LlmServiceServer is the server API for LlmService service.
 // All implementations must embed UnimplementedLlmServiceServer
 // for forward compatibility.
 type LlmServiceServer interface {
 	GenerateTextToText(context.Context, *TextToTextRequest) (*LLMResponse, error)
 	GenerateTextToImage(context.Context, *TextToImageRequest) (*LLMResponse, error)
+	GenerateTextToTextStream(*TextToTextRequest, grpc.ServerStreamingServer[LLMStreamChunk]) error
+	GenerateTextToImageStream(*TextToImageRequest, grpc.ServerStreamingServer[LLMStreamChunk]) error
 	mustEmbedUnimplementedLlmServiceServer()
 }
 
@@ -81,6 +125,12 @@ func (UnimplementedLlmServiceServer) GenerateTextToText(context.Context, *TextTo
 func (UnimplementedLlmServiceServer) GenerateTextToImage(context.Context, *TextToImageRequest) (*LLMResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GenerateTextToImage not implemented")
 }
+func (UnimplementedLlmServiceServer) GenerateTextToTextStream(*TextToTextRequest, grpc.ServerStreamingServer[LLMStreamChunk]) error {
+	return status.Error(codes.Unimplemented, "method GenerateTextToTextStream not implemented")
+}
+func (UnimplementedLlmServiceServer) GenerateTextToImageStream(*TextToImageRequest, grpc.ServerStreamingServer[LLMStreamChunk]) error {
+	return status.Error(codes.Unimplemented, "method GenerateTextToImageStream not implemented")
+}
 func (UnimplementedLlmServiceServer) mustEmbedUnimplementedLlmServiceServer() {}
 func (UnimplementedLlmServiceServer) testEmbeddedByValue()                    {}
 
@@ -138,6 +188,28 @@ func _LlmService_GenerateTextToImage_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LlmService_GenerateTextToTextStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TextToTextRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LlmServiceServer).GenerateTextToTextStream(m, &grpc.GenericServerStream[TextToTextRequest, LLMStreamChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LlmService_GenerateTextToTextStreamServer = grpc.ServerStreamingServer[LLMStreamChunk]
+
+func _LlmService_GenerateTextToImageStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TextToImageRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LlmServiceServer).GenerateTextToImageStream(m, &grpc.GenericServerStream[TextToImageRequest, LLMStreamChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LlmService_GenerateTextToImageStreamServer = grpc.ServerStreamingServer[LLMStreamChunk]
+
 // LlmService_ServiceDesc is the grpc.ServiceDesc for LlmService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -154,6 +226,17 @@ var LlmService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _LlmService_GenerateTextToImage_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GenerateTextToTextStream",
+			Handler:       _LlmService_GenerateTextToTextStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GenerateTextToImageStream",
+			Handler:       _LlmService_GenerateTextToImageStream_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "llm_service.proto",
 }
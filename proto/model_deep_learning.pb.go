@@ -253,6 +253,110 @@ func (x *EmbedImageResponse) GetStatus() bool {
 	return false
 }
 
+type RerankRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Documents     []string               `protobuf:"bytes,2,rep,name=documents,proto3" json:"documents,omitempty"` // Candidate passages, scored independently against query
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RerankRequest) Reset() {
+	*x = RerankRequest{}
+	mi := &file_model_deep_learning_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RerankRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RerankRequest) ProtoMessage() {}
+
+func (x *RerankRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_model_deep_learning_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RerankRequest.ProtoReflect.Descriptor instead.
+func (*RerankRequest) Descriptor() ([]byte, []int) {
+	return file_model_deep_learning_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RerankRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *RerankRequest) GetDocuments() []string {
+	if x != nil {
+		return x.Documents
+	}
+	return nil
+}
+
+type RerankResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Scores        []float32              `protobuf:"fixed32,1,rep,packed,name=scores,proto3" json:"scores,omitempty"` // One relevance score per document, same order as the request
+	Status        bool                   `protobuf:"varint,2,opt,name=status,proto3" json:"status,omitempty"`         // Status of the rerank operation
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RerankResponse) Reset() {
+	*x = RerankResponse{}
+	mi := &file_model_deep_learning_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RerankResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RerankResponse) ProtoMessage() {}
+
+func (x *RerankResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_model_deep_learning_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RerankResponse.ProtoReflect.Descriptor instead.
+func (*RerankResponse) Descriptor() ([]byte, []int) {
+	return file_model_deep_learning_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RerankResponse) GetScores() []float32 {
+	if x != nil {
+		return x.Scores
+	}
+	return nil
+}
+
+func (x *RerankResponse) GetStatus() bool {
+	if x != nil {
+		return x.Status
+	}
+	return false
+}
+
 var File_model_deep_learning_proto protoreflect.FileDescriptor
 
 const file_model_deep_learning_proto_rawDesc = "" +
@@ -272,11 +376,18 @@ const file_model_deep_learning_proto_rawDesc = "" +
 	"\x12EmbedImageResponse\x12\x1c\n" +
 	"\tembedding\x18\x01 \x03(\x02R\tembedding\x12\x1c\n" +
 	"\tdimension\x18\x02 \x01(\x05R\tdimension\x12\x16\n" +
-	"\x06status\x18\x03 \x01(\bR\x06status2\x80\x01\n" +
+	"\x06status\x18\x03 \x01(\bR\x06status\"C\n" +
+	"\rRerankRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x1c\n" +
+	"\tdocuments\x18\x02 \x03(\tR\tdocuments\"@\n" +
+	"\x0eRerankResponse\x12\x16\n" +
+	"\x06scores\x18\x01 \x03(\x02R\x06scores\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\bR\x06status2\xab\x01\n" +
 	"\x13DeepLearningService\x122\n" +
 	"\tEmbedText\x12\x11.EmbedTextRequest\x1a\x12.EmbedTextResponse\x125\n" +
 	"\n" +
-	"EmbedImage\x12\x12.EmbedImageRequest\x1a\x13.EmbedImageResponseB)Z'rag_imagetotext_texttoimage/proto;protob\x06proto3"
+	"EmbedImage\x12\x12.EmbedImageRequest\x1a\x13.EmbedImageResponse\x12)\n" +
+	"\x06Rerank\x12\x0e.RerankRequest\x1a\x0f.RerankResponseB)Z'rag_imagetotext_texttoimage/proto;protob\x06proto3"
 
 var (
 	file_model_deep_learning_proto_rawDescOnce sync.Once
@@ -290,20 +401,24 @@ func file_model_deep_learning_proto_rawDescGZIP() []byte {
 	return file_model_deep_learning_proto_rawDescData
 }
 
-var file_model_deep_learning_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_model_deep_learning_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
 var file_model_deep_learning_proto_goTypes = []any{
 	(*EmbedTextRequest)(nil),   // 0: EmbedTextRequest
 	(*EmbedTextResponse)(nil),  // 1: EmbedTextResponse
 	(*EmbedImageRequest)(nil),  // 2: EmbedImageRequest
 	(*EmbedImageResponse)(nil), // 3: EmbedImageResponse
+	(*RerankRequest)(nil),      // 4: RerankRequest
+	(*RerankResponse)(nil),     // 5: RerankResponse
 }
 var file_model_deep_learning_proto_depIdxs = []int32{
 	0, // 0: DeepLearningService.EmbedText:input_type -> EmbedTextRequest
 	2, // 1: DeepLearningService.EmbedImage:input_type -> EmbedImageRequest
-	1, // 2: DeepLearningService.EmbedText:output_type -> EmbedTextResponse
-	3, // 3: DeepLearningService.EmbedImage:output_type -> EmbedImageResponse
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
+	4, // 2: DeepLearningService.Rerank:input_type -> RerankRequest
+	1, // 3: DeepLearningService.EmbedText:output_type -> EmbedTextResponse
+	3, // 4: DeepLearningService.EmbedImage:output_type -> EmbedImageResponse
+	5, // 5: DeepLearningService.Rerank:output_type -> RerankResponse
+	3, // [3:6] is the sub-list for method output_type
+	0, // [0:3] is the sub-list for method input_type
 	0, // [0:0] is the sub-list for extension type_name
 	0, // [0:0] is the sub-list for extension extendee
 	0, // [0:0] is the sub-list for field type_name
@@ -320,7 +435,7 @@ func file_model_deep_learning_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_model_deep_learning_proto_rawDesc), len(file_model_deep_learning_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   6,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
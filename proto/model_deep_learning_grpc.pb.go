@@ -21,6 +21,7 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	DeepLearningService_EmbedText_FullMethodName  = "/DeepLearningService/EmbedText"
 	DeepLearningService_EmbedImage_FullMethodName = "/DeepLearningService/EmbedImage"
+	DeepLearningService_Rerank_FullMethodName     = "/DeepLearningService/Rerank"
 )
 
 // DeepLearningServiceClient is the client API for DeepLearningService service.
@@ -29,6 +30,7 @@ const (
 type DeepLearningServiceClient interface {
 	EmbedText(ctx context.Context, in *EmbedTextRequest, opts ...grpc.CallOption) (*EmbedTextResponse, error)
 	EmbedImage(ctx context.Context, in *EmbedImageRequest, opts ...grpc.CallOption) (*EmbedImageResponse, error)
+	Rerank(ctx context.Context, in *RerankRequest, opts ...grpc.CallOption) (*RerankResponse, error)
 }
 
 type deepLearningServiceClient struct {
@@ -59,12 +61,23 @@ func (c *deepLearningServiceClient) EmbedImage(ctx context.Context, in *EmbedIma
 	return out, nil
 }
 
+func (c *deepLearningServiceClient) Rerank(ctx context.Context, in *RerankRequest, opts ...grpc.CallOption) (*RerankResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RerankResponse)
+	err := c.cc.Invoke(ctx, DeepLearningService_Rerank_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DeepLearningServiceServer is the server API for DeepLearningService service.
 // All implementations must embed UnimplementedDeepLearningServiceServer
 // for forward compatibility.
 type DeepLearningServiceServer interface {
 	EmbedText(context.Context, *EmbedTextRequest) (*EmbedTextResponse, error)
 	EmbedImage(context.Context, *EmbedImageRequest) (*EmbedImageResponse, error)
+	Rerank(context.Context, *RerankRequest) (*RerankResponse, error)
 	mustEmbedUnimplementedDeepLearningServiceServer()
 }
 
@@ -81,6 +94,9 @@ func (UnimplementedDeepLearningServiceServer) EmbedText(context.Context, *EmbedT
 func (UnimplementedDeepLearningServiceServer) EmbedImage(context.Context, *EmbedImageRequest) (*EmbedImageResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method EmbedImage not implemented")
 }
+func (UnimplementedDeepLearningServiceServer) Rerank(context.Context, *RerankRequest) (*RerankResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Rerank not implemented")
+}
 func (UnimplementedDeepLearningServiceServer) mustEmbedUnimplementedDeepLearningServiceServer() {}
 func (UnimplementedDeepLearningServiceServer) testEmbeddedByValue()                             {}
 
@@ -138,6 +154,24 @@ func _DeepLearningService_EmbedImage_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DeepLearningService_Rerank_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RerankRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeepLearningServiceServer).Rerank(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeepLearningService_Rerank_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeepLearningServiceServer).Rerank(ctx, req.(*RerankRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // DeepLearningService_ServiceDesc is the grpc.ServiceDesc for DeepLearningService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -153,6 +187,10 @@ var DeepLearningService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "EmbedImage",
 			Handler:    _DeepLearningService_EmbedImage_Handler,
 		},
+		{
+			MethodName: "Rerank",
+			Handler:    _DeepLearningService_Rerank_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "model_deep_learning.proto",
@@ -733,8 +733,14 @@ type SearchPointRequest struct {
 	ScoreThreshold *float32               `protobuf:"fixed32,6,opt,name=score_threshold,json=scoreThreshold,proto3,oneof" json:"score_threshold,omitempty"`
 	WithPayload    bool                   `protobuf:"varint,7,opt,name=with_payload,json=withPayload,proto3" json:"with_payload,omitempty"`
 	Filter         *Filter                `protobuf:"bytes,8,opt,name=filter,proto3,oneof" json:"filter,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// fusion_strategy: "weighted_minmax" (default) | "rrf" | "dbsf"
+	FusionStrategy string `protobuf:"bytes,9,opt,name=fusion_strategy,json=fusionStrategy,proto3" json:"fusion_strategy,omitempty"`
+	// Per-vector fusion weights keyed by vector name (text_dense, image_dense,
+	// bm25). Missing names fall back to the server defaults.
+	VectorWeights map[string]float32 `protobuf:"bytes,10,rep,name=vector_weights,json=vectorWeights,proto3" json:"vector_weights,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed32,2,opt,name=value"`
+	RrfK          *float32           `protobuf:"fixed32,11,opt,name=rrf_k,json=rrfK,proto3,oneof" json:"rrf_k,omitempty"` // RRF rank constant, default 60
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SearchPointRequest) Reset() {
@@ -823,6 +829,27 @@ func (x *SearchPointRequest) GetFilter() *Filter {
 	return nil
 }
 
+func (x *SearchPointRequest) GetFusionStrategy() string {
+	if x != nil {
+		return x.FusionStrategy
+	}
+	return ""
+}
+
+func (x *SearchPointRequest) GetVectorWeights() map[string]float32 {
+	if x != nil {
+		return x.VectorWeights
+	}
+	return nil
+}
+
+func (x *SearchPointRequest) GetRrfK() float32 {
+	if x != nil && x.RrfK != nil {
+		return *x.RrfK
+	}
+	return 0
+}
+
 // SearchResultItem is a single scored point returned from search.
 type SearchResultItem struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -888,8 +915,13 @@ type ResponseSearchPoint struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	CollectionName string                 `protobuf:"bytes,1,opt,name=collection_name,json=collectionName,proto3" json:"collection_name,omitempty"`
 	Results        []*SearchResultItem    `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	FusionStrategy string                 `protobuf:"bytes,3,opt,name=fusion_strategy,json=fusionStrategy,proto3" json:"fusion_strategy,omitempty"`
+	// normalization_mode is "minmax", "rank" or "dbsf" for the fused result;
+	// vector_normalization holds the per-vector mode (e.g. raw_single, raw_flat).
+	NormalizationMode   string            `protobuf:"bytes,4,opt,name=normalization_mode,json=normalizationMode,proto3" json:"normalization_mode,omitempty"`
+	VectorNormalization map[string]string `protobuf:"bytes,5,rep,name=vector_normalization,json=vectorNormalization,proto3" json:"vector_normalization,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
 }
 
 func (x *ResponseSearchPoint) Reset() {
@@ -936,6 +968,27 @@ func (x *ResponseSearchPoint) GetResults() []*SearchResultItem {
 	return nil
 }
 
+func (x *ResponseSearchPoint) GetFusionStrategy() string {
+	if x != nil {
+		return x.FusionStrategy
+	}
+	return ""
+}
+
+func (x *ResponseSearchPoint) GetNormalizationMode() string {
+	if x != nil {
+		return x.NormalizationMode
+	}
+	return ""
+}
+
+func (x *ResponseSearchPoint) GetVectorNormalization() map[string]string {
+	if x != nil {
+		return x.VectorNormalization
+	}
+	return nil
+}
+
 // DeletePointFilterRequest maps to (collectionName + ports.Filter).
 type DeletePointFilterRequest struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
@@ -1094,7 +1147,7 @@ const file_rag_service_proto_rawDesc = "" +
 	"\x06Filter\x12#\n" +
 	"\x04must\x18\x01 \x03(\v2\x0f.FieldConditionR\x04must\x12'\n" +
 	"\x06should\x18\x02 \x03(\v2\x0f.FieldConditionR\x06should\x12*\n" +
-	"\bmust_not\x18\x03 \x03(\v2\x0f.FieldConditionR\amustNot\"\xc1\x02\n" +
+	"\bmust_not\x18\x03 \x03(\v2\x0f.FieldConditionR\amustNot\"\x9f\x04\n" +
 	"\x12SearchPointRequest\x12'\n" +
 	"\x0fcollection_name\x18\x01 \x01(\tR\x0ecollectionName\x12\x1f\n" +
 	"\vvector_name\x18\x02 \x01(\tR\n" +
@@ -1105,19 +1158,33 @@ const file_rag_service_proto_rawDesc = "" +
 	"\x05limit\x18\x05 \x01(\x04R\x05limit\x12,\n" +
 	"\x0fscore_threshold\x18\x06 \x01(\x02H\x00R\x0escoreThreshold\x88\x01\x01\x12!\n" +
 	"\fwith_payload\x18\a \x01(\bR\vwithPayload\x12$\n" +
-	"\x06filter\x18\b \x01(\v2\a.FilterH\x01R\x06filter\x88\x01\x01B\x12\n" +
+	"\x06filter\x18\b \x01(\v2\a.FilterH\x01R\x06filter\x88\x01\x01\x12'\n" +
+	"\x0ffusion_strategy\x18\t \x01(\tR\x0efusionStrategy\x12M\n" +
+	"\x0evector_weights\x18\n" +
+	" \x03(\v2&.SearchPointRequest.VectorWeightsEntryR\rvectorWeights\x12\x18\n" +
+	"\x05rrf_k\x18\v \x01(\x02H\x02R\x04rrfK\x88\x01\x01\x1a@\n" +
+	"\x12VectorWeightsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x02R\x05value:\x028\x01B\x12\n" +
 	"\x10_score_thresholdB\t\n" +
-	"\a_filter\"\xae\x01\n" +
+	"\a_filterB\b\n" +
+	"\x06_rrf_k\"\xae\x01\n" +
 	"\x10SearchResultItem\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05score\x18\x02 \x01(\x02R\x05score\x128\n" +
 	"\apayload\x18\x03 \x03(\v2\x1e.SearchResultItem.PayloadEntryR\apayload\x1a:\n" +
 	"\fPayloadEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"k\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xed\x02\n" +
 	"\x13ResponseSearchPoint\x12'\n" +
 	"\x0fcollection_name\x18\x01 \x01(\tR\x0ecollectionName\x12+\n" +
-	"\aresults\x18\x02 \x03(\v2\x11.SearchResultItemR\aresults\"d\n" +
+	"\aresults\x18\x02 \x03(\v2\x11.SearchResultItemR\aresults\x12'\n" +
+	"\x0ffusion_strategy\x18\x03 \x01(\tR\x0efusionStrategy\x12-\n" +
+	"\x12normalization_mode\x18\x04 \x01(\tR\x11normalizationMode\x12`\n" +
+	"\x14vector_normalization\x18\x05 \x03(\v2-.ResponseSearchPoint.VectorNormalizationEntryR\x13vectorNormalization\x1aF\n" +
+	"\x18VectorNormalizationEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"d\n" +
 	"\x18DeletePointFilterRequest\x12'\n" +
 	"\x0fcollection_name\x18\x01 \x01(\tR\x0ecollectionName\x12\x1f\n" +
 	"\x06filter\x18\x02 \x01(\v2\a.FilterR\x06filter\"\\\n" +
@@ -1144,7 +1211,7 @@ func file_rag_service_proto_rawDescGZIP() []byte {
 	return file_rag_service_proto_rawDescData
 }
 
-var file_rag_service_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_rag_service_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_rag_service_proto_goTypes = []any{
 	(*CollectionVectorConfig)(nil),    // 0: CollectionVectorConfig
 	(*SchemaCollection)(nil),          // 1: SchemaCollection
@@ -1163,7 +1230,9 @@ var file_rag_service_proto_goTypes = []any{
 	(*DeletePointFilterRequest)(nil),  // 14: DeletePointFilterRequest
 	(*ResponseDeletePointFilter)(nil), // 15: ResponseDeletePointFilter
 	nil,                               // 16: Point.PayloadEntry
-	nil,                               // 17: SearchResultItem.PayloadEntry
+	nil,                               // 17: SearchPointRequest.VectorWeightsEntry
+	nil,                               // 18: SearchResultItem.PayloadEntry
+	nil,                               // 19: ResponseSearchPoint.VectorNormalizationEntry
 }
 var file_rag_service_proto_depIdxs = []int32{
 	0,  // 0: SchemaCollection.vectors:type_name -> CollectionVectorConfig
@@ -1174,24 +1243,26 @@ var file_rag_service_proto_depIdxs = []int32{
 	9,  // 5: Filter.should:type_name -> FieldCondition
 	9,  // 6: Filter.must_not:type_name -> FieldCondition
 	10, // 7: SearchPointRequest.filter:type_name -> Filter
-	17, // 8: SearchResultItem.payload:type_name -> SearchResultItem.PayloadEntry
-	12, // 9: ResponseSearchPoint.results:type_name -> SearchResultItem
-	10, // 10: DeletePointFilterRequest.filter:type_name -> Filter
-	1,  // 11: RagService.CreateCollection:input_type -> SchemaCollection
-	3,  // 12: RagService.DeleteCollection:input_type -> DeleteCollectionRequest
-	7,  // 13: RagService.InsertPoint:input_type -> InsertPointRequest
-	11, // 14: RagService.SearchPoint:input_type -> SearchPointRequest
-	14, // 15: RagService.DeletePointFilter:input_type -> DeletePointFilterRequest
-	2,  // 16: RagService.CreateCollection:output_type -> ResponseCreateCollection
-	4,  // 17: RagService.DeleteCollection:output_type -> ResponseDeleteCollection
-	8,  // 18: RagService.InsertPoint:output_type -> ResponseInsertPoint
-	13, // 19: RagService.SearchPoint:output_type -> ResponseSearchPoint
-	15, // 20: RagService.DeletePointFilter:output_type -> ResponseDeletePointFilter
-	16, // [16:21] is the sub-list for method output_type
-	11, // [11:16] is the sub-list for method input_type
-	11, // [11:11] is the sub-list for extension type_name
-	11, // [11:11] is the sub-list for extension extendee
-	0,  // [0:11] is the sub-list for field type_name
+	17, // 8: SearchPointRequest.vector_weights:type_name -> SearchPointRequest.VectorWeightsEntry
+	18, // 9: SearchResultItem.payload:type_name -> SearchResultItem.PayloadEntry
+	12, // 10: ResponseSearchPoint.results:type_name -> SearchResultItem
+	19, // 11: ResponseSearchPoint.vector_normalization:type_name -> ResponseSearchPoint.VectorNormalizationEntry
+	10, // 12: DeletePointFilterRequest.filter:type_name -> Filter
+	1,  // 13: RagService.CreateCollection:input_type -> SchemaCollection
+	3,  // 14: RagService.DeleteCollection:input_type -> DeleteCollectionRequest
+	7,  // 15: RagService.InsertPoint:input_type -> InsertPointRequest
+	11, // 16: RagService.SearchPoint:input_type -> SearchPointRequest
+	14, // 17: RagService.DeletePointFilter:input_type -> DeletePointFilterRequest
+	2,  // 18: RagService.CreateCollection:output_type -> ResponseCreateCollection
+	4,  // 19: RagService.DeleteCollection:output_type -> ResponseDeleteCollection
+	8,  // 20: RagService.InsertPoint:output_type -> ResponseInsertPoint
+	13, // 21: RagService.SearchPoint:output_type -> ResponseSearchPoint
+	15, // 22: RagService.DeletePointFilter:output_type -> ResponseDeletePointFilter
+	18, // [18:23] is the sub-list for method output_type
+	13, // [13:18] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
 }
 
 func init() { file_rag_service_proto_init() }
@@ -1211,7 +1282,7 @@ func file_rag_service_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rag_service_proto_rawDesc), len(file_rag_service_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   18,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
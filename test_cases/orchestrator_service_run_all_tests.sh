@@ -5,6 +5,9 @@ source "$(cd "$(dirname "$0")" && pwd)/_common.sh"
 SCRIPTS=(
   orchestrator_service_test_healthz.sh
   orchestrator_service_test_chat.sh
+  orchestrator_service_test_chat_stream.sh
+  orchestrator_service_test_chat_multi_collection.sh
+  orchestrator_service_test_sessions.sh
   orchestrator_service_test_vectordb_deletecollection.sh
   orchestrator_service_test_vectordb_createcollection.sh
   orchestrator_service_test_process_and_ingest.sh
@@ -0,0 +1,52 @@
+#!/usr/bin/env bash
+set -euo pipefail
+source "$(cd "$(dirname "$0")" && pwd)/_common.sh"
+
+ORCHESTRATOR_HOST="${ORCHESTRATOR_HOST:-${SERVICE_HOST}:${ORCHESTRATOR_SERVICE_PORT:-8080}}"
+BASE_URL="http://${ORCHESTRATOR_HOST}"
+
+SESSION_ID="${SESSION_ID:-session_multi_collection_001}"
+QUERY="${QUERY:-So sanh noi dung cac tai lieu nay}"
+COLLECTIONS="${COLLECTIONS:-ai_sota_0022,ai_sota_0023}"
+
+COLLECTIONS_JSON="$(echo "$COLLECTIONS" | jq -R 'split(",") | map(select(length > 0))')"
+
+echo "== [1] Call orchestrator chat API across collections: ${COLLECTIONS} =="
+RAW="$(curl -sS -m 180 -w $'\n%{http_code}' \
+  -X POST "${BASE_URL}/api/v1/orchestrator/chat" \
+  -H "Content-Type: application/json" \
+  -d "{
+    \"session_id\": \"${SESSION_ID}\",
+    \"query\": \"${QUERY}\",
+    \"collections\": ${COLLECTIONS_JSON}
+  }")"
+
+HTTP_CODE="$(echo "$RAW" | tail -n1)"
+BODY="$(echo "$RAW" | sed '$d')"
+
+echo "HTTP ${HTTP_CODE}"
+echo "$BODY" | jq .
+
+if [[ "$HTTP_CODE" != "200" ]]; then
+  echo "multi-collection chat failed with HTTP ${HTTP_CODE}" >&2
+  exit 1
+fi
+
+ANSWER="$(echo "$BODY" | jq -r '.answer // empty')"
+if [[ -z "$ANSWER" ]]; then
+  echo "chat response has empty answer" >&2
+  exit 1
+fi
+echo "source collections: $(echo "$BODY" | jq -c '[.sources[]?.collection] | unique')"
+
+echo "== [2] Unknown workspace must be rejected =="
+CODE="$(curl -sS -m 30 -o /dev/null -w '%{http_code}' \
+  -X POST "${BASE_URL}/api/v1/orchestrator/chat" \
+  -H "Content-Type: application/json" \
+  -d "{\"session_id\": \"${SESSION_ID}\", \"query\": \"${QUERY}\", \"workspace\": \"__missing_workspace__\"}")"
+if [[ "$CODE" != "400" ]]; then
+  echo "expected HTTP 400 for unknown workspace, got ${CODE}" >&2
+  exit 1
+fi
+
+echo "multi-collection chat API passed."
@@ -0,0 +1,41 @@
+#!/usr/bin/env bash
+set -euo pipefail
+source "$(cd "$(dirname "$0")" && pwd)/_common.sh"
+
+ORCHESTRATOR_HOST="${ORCHESTRATOR_HOST:-${SERVICE_HOST}:${ORCHESTRATOR_SERVICE_PORT:-8080}}"
+BASE_URL="http://${ORCHESTRATOR_HOST}"
+
+SESSION_ID="${SESSION_ID:-session_1234678}"
+QUERY="${QUERY:-Noi dung tai lieu nay la gi?}"
+IMAGE_PATH="${IMAGE_PATH:-}"
+UUID="${UUID:-ai_sota_0022}"
+
+echo "== [1] Call orchestrator chat stream API =="
+BODY="$(curl -sS -N -m 300 \
+  -X POST "${BASE_URL}/api/v1/orchestrator/chat/stream" \
+  -H "Content-Type: application/json" \
+  -d "{
+    \"session_id\": \"${SESSION_ID}\",
+    \"query\": \"${QUERY}\",
+    \"image_path\": \"${IMAGE_PATH}\",
+    \"Uuid\": \"${UUID}\"
+  }")"
+
+echo "$BODY"
+
+if echo "$BODY" | grep -q '^event: error'; then
+  echo "chat stream returned error event" >&2
+  exit 1
+fi
+if ! echo "$BODY" | grep -q '^event: final'; then
+  echo "chat stream has no final event" >&2
+  exit 1
+fi
+
+ANSWER="$(echo "$BODY" | grep -A1 '^event: final' | sed -n 's/^data: //p' | jq -r '.answer // empty')"
+if [[ -z "$ANSWER" ]]; then
+  echo "chat stream final event has empty answer" >&2
+  exit 1
+fi
+
+echo "chat stream API passed."
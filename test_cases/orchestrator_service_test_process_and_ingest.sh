@@ -12,17 +12,22 @@ UPLINK_PORT="${UPLINK_PORT:-8000}"
 UPLINK_PATH="${UPLINK_PATH:-/download/ai_sota_0022.pdf}"
 URL_DOWNLOAD="${URL_DOWNLOAD:-http://${UPLINK_HOST}:${UPLINK_PORT}${UPLINK_PATH}}"
 TIMEOUT_SECONDS="${TIMEOUT_SECONDS:-900}"
-CURL_MAX_TIME="${CURL_MAX_TIME:-1900}"
+RESUME="${RESUME:-false}"
+FORCE="${FORCE:-false}"
+POLL_INTERVAL="${POLL_INTERVAL:-5}"
+POLL_MAX_SECONDS="${POLL_MAX_SECONDS:-1900}"
 
-echo "== [1] Call process-and-ingest API =="
-RAW="$(curl -sS -m "$CURL_MAX_TIME" -w $'\n%{http_code}' \
+echo "== [1] Submit process-and-ingest job =="
+RAW="$(curl -sS -m 20 -w $'\n%{http_code}' \
   -X POST "${BASE_URL}/api/v1/orchestrator/training-file/process-and-ingest" \
   -H "Content-Type: application/json" \
   -d "{
     \"uuid\": \"${UUID}\",
     \"url_download\": \"${URL_DOWNLOAD}\",
     \"lang\": \"${LANG_VALUE}\",
-    \"timeout_seconds\": ${TIMEOUT_SECONDS}
+    \"timeout_seconds\": ${TIMEOUT_SECONDS},
+    \"resume\": ${RESUME},
+    \"force\": ${FORCE}
   }")"
 
 HTTP_CODE="$(echo "$RAW" | tail -n1)"
@@ -31,14 +36,43 @@ BODY="$(echo "$RAW" | sed '$d')"
 echo "HTTP ${HTTP_CODE}"
 echo "$BODY" | jq .
 
-if [[ "$HTTP_CODE" != "200" ]]; then
+if [[ "$HTTP_CODE" != "202" ]]; then
   echo "process-and-ingest API failed with HTTP ${HTTP_CODE}" >&2
   exit 1
 fi
 
-SUCCESS="$(echo "$BODY" | jq -r '.success // empty')"
-RESP_UUID="$(echo "$BODY" | jq -r '.uuid // empty')"
-VERIFIED="$(echo "$BODY" | jq -r '.verified // empty')"
+JOB_ID="$(echo "$BODY" | jq -r '.job_id // empty')"
+if [[ -z "$JOB_ID" ]]; then
+  echo "job_id is empty" >&2
+  exit 1
+fi
+
+echo "== [2] Poll job ${JOB_ID} =="
+DEADLINE=$(( $(date +%s) + POLL_MAX_SECONDS ))
+STATUS=""
+while true; do
+  BODY="$(curl -sS -m 20 "${BASE_URL}/api/v1/orchestrator/jobs/${JOB_ID}")"
+  STATUS="$(echo "$BODY" | jq -r '.status // empty')"
+  echo "status=${STATUS} $(echo "$BODY" | jq -r '[.steps[] | "\(.name):\(.status)"] | join(" ")')"
+  if [[ "$STATUS" != "queued" && "$STATUS" != "running" ]]; then
+    break
+  fi
+  if (( $(date +%s) > DEADLINE )); then
+    echo "job ${JOB_ID} did not finish within ${POLL_MAX_SECONDS}s" >&2
+    exit 1
+  fi
+  sleep "$POLL_INTERVAL"
+done
+echo "$BODY" | jq .
+
+if [[ "$STATUS" != "succeeded" ]]; then
+  echo "process-and-ingest job ended with status ${STATUS}" >&2
+  exit 1
+fi
+
+SUCCESS="$(echo "$BODY" | jq -r '.result.success // empty')"
+RESP_UUID="$(echo "$BODY" | jq -r '.result.uuid // empty')"
+VERIFIED="$(echo "$BODY" | jq -r '.result.verified // empty')"
 
 if [[ "$SUCCESS" != "true" ]]; then
   echo "process-and-ingest success is not true" >&2
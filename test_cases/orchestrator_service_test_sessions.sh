@@ -0,0 +1,70 @@
+#!/usr/bin/env bash
+set -euo pipefail
+source "$(cd "$(dirname "$0")" && pwd)/_common.sh"
+
+ORCHESTRATOR_HOST="${ORCHESTRATOR_HOST:-${SERVICE_HOST}:${ORCHESTRATOR_SERVICE_PORT:-8080}}"
+BASE_URL="http://${ORCHESTRATOR_HOST}"
+
+SESSION_ID="${SESSION_ID:-session_1234678}"
+
+call() {
+  local method="$1" url="$2" body="${3:-}"
+  local raw
+  if [[ -n "$body" ]]; then
+    raw="$(curl -sS -m 20 -w $'\n%{http_code}' -X "$method" "$url" -H "Content-Type: application/json" -d "$body")"
+  else
+    raw="$(curl -sS -m 20 -w $'\n%{http_code}' -X "$method" "$url")"
+  fi
+  HTTP_CODE="$(echo "$raw" | tail -n1)"
+  BODY="$(echo "$raw" | sed '$d')"
+  echo "HTTP ${HTTP_CODE}"
+  echo "$BODY" | jq .
+}
+
+echo "== [1] List sessions =="
+call GET "${BASE_URL}/api/v1/orchestrator/sessions"
+if [[ "$HTTP_CODE" != "200" ]]; then
+  echo "list sessions failed with HTTP ${HTTP_CODE}" >&2
+  exit 1
+fi
+
+if ! echo "$BODY" | jq -e --arg id "$SESSION_ID" '.sessions[] | select(.session_id == $id)' >/dev/null; then
+  echo "session ${SESSION_ID} not found, run orchestrator_service_test_chat.sh first; skipping the rest."
+  exit 0
+fi
+
+echo "== [2] Get session history =="
+call GET "${BASE_URL}/api/v1/orchestrator/sessions/${SESSION_ID}"
+if [[ "$HTTP_CODE" != "200" ]]; then
+  echo "get session history failed with HTTP ${HTTP_CODE}" >&2
+  exit 1
+fi
+
+echo "== [3] Extend session TTL =="
+call POST "${BASE_URL}/api/v1/orchestrator/sessions/${SESSION_ID}/extend" '{"ttl_seconds": 3600}'
+if [[ "$HTTP_CODE" != "200" ]]; then
+  echo "extend session failed with HTTP ${HTTP_CODE}" >&2
+  exit 1
+fi
+
+echo "== [4] Clear session history =="
+call DELETE "${BASE_URL}/api/v1/orchestrator/sessions/${SESSION_ID}/history"
+if [[ "$HTTP_CODE" != "200" ]]; then
+  echo "clear session history failed with HTTP ${HTTP_CODE}" >&2
+  exit 1
+fi
+
+echo "== [5] Delete session =="
+call DELETE "${BASE_URL}/api/v1/orchestrator/sessions/${SESSION_ID}"
+if [[ "$HTTP_CODE" != "200" ]]; then
+  echo "delete session failed with HTTP ${HTTP_CODE}" >&2
+  exit 1
+fi
+
+call GET "${BASE_URL}/api/v1/orchestrator/sessions/${SESSION_ID}"
+if [[ "$HTTP_CODE" != "404" ]]; then
+  echo "deleted session still readable (HTTP ${HTTP_CODE})" >&2
+  exit 1
+fi
+
+echo "session API passed."
@@ -0,0 +1,97 @@
+"""Generate src/infra/bert_unicode_tables.hpp for the BERT basic tokenizer.
+
+The C++ side has no ICU, so lowercasing, accent stripping and the punctuation
+class come from Python's unicodedata instead:
+
+    python gen_bert_unicode_tables.py > src/infra/bert_unicode_tables.hpp
+"""
+
+import unicodedata
+
+# Blocks with letters that lowercase or decompose to a single base letter:
+# Latin-1, Latin Extended-A/B, Greek, Cyrillic and Latin Extended Additional
+# (Vietnamese).
+FOLD_RANGES = [(0x00C0, 0x024F), (0x0370, 0x03FF), (0x0400, 0x04FF), (0x1E00, 0x1EFF)]
+
+
+def fold(cp):
+    ch = chr(cp)
+    lower = ch.lower()
+    if len(lower) != 1:
+        lower = ch
+    base = "".join(c for c in unicodedata.normalize("NFD", lower) if unicodedata.category(c) != "Mn")
+    return base if len(base) == 1 else ch
+
+
+def fold_pairs():
+    pairs = []
+    for first, last in FOLD_RANGES:
+        for cp in range(first, last + 1):
+            folded = fold(cp)
+            if folded != chr(cp):
+                pairs.append((cp, ord(folded)))
+    return pairs
+
+
+def ranges(predicate):
+    out, start = [], None
+    for cp in range(0x80, 0x10000):
+        if predicate(chr(cp)):
+            if start is None:
+                start = cp
+        elif start is not None:
+            out.append((start, cp - 1))
+            start = None
+    return out
+
+
+def rows(items, per_row):
+    for i in range(0, len(items), per_row):
+        yield "    " + " ".join(items[i:i + per_row])
+
+
+def main():
+    pairs = fold_pairs()
+    punct = ranges(lambda c: unicodedata.category(c).startswith("P"))
+    marks = ranges(lambda c: unicodedata.category(c) == "Mn")
+
+    print("// Generated by gen_bert_unicode_tables.py (Unicode %s). Do not edit." % unicodedata.unidata_version)
+    print("#pragma once")
+    print()
+    print("#include <cstdint>")
+    print()
+    print("namespace bert_unicode {")
+    print()
+    print("struct Fold {")
+    print("    uint16_t from;")
+    print("    uint16_t to;")
+    print("};")
+    print()
+    print("struct Range {")
+    print("    uint16_t first;")
+    print("    uint16_t last;")
+    print("};")
+    print()
+    print("// Lowercase letter with its accents stripped, sorted by from.")
+    print("inline constexpr Fold kFolds[] = {")
+    for row in rows(["{0x%04X, 0x%04X}," % p for p in pairs], 6):
+        print(row)
+    print("};")
+    print()
+    print("// Non-ASCII punctuation (category P*) in the BMP, sorted.")
+    print("inline constexpr Range kPunctuation[] = {")
+    for row in rows(["{0x%04X, 0x%04X}," % r for r in punct], 6):
+        print(row)
+    print("};")
+    print()
+    print("// Non-spacing marks (category Mn) in the BMP, dropped after NFD.")
+    print("inline constexpr Range kNonSpacingMarks[] = {")
+    for row in rows(["{0x%04X, 0x%04X}," % r for r in marks], 6):
+        print(row)
+    print("};")
+    print()
+    print("}  // namespace bert_unicode")
+
+
+if __name__ == "__main__":
+    main()
@@ -3,6 +3,7 @@
 #include "../bootstrap/registry.hpp"
 #include "../infra/jina_text_encoder.hpp"
 #include "../infra/jina_vision_encoder.hpp"
+#include "../application/port/IReranker.hpp"
 #include <opencv2/opencv.hpp>
 #include <vector>
 #include <string>
@@ -148,4 +149,40 @@ int jina_embed_batch_image(JinaHandle handle, const uint8_t* imgs_data, int coun
     }
 }
 
+int jina_rerank(JinaHandle handle, const char* query, const char** documents, int count, float* out_scores) {
+    try {
+        g_last_error.clear();
+        if (!handle || !query || !documents || count <= 0 || !out_scores) return -1;
+
+        auto* container = static_cast<bootstrap::DIContainer*>(handle);
+        std::shared_ptr<IReranker> reranker;
+        try {
+            reranker = container->resolve<IReranker>();
+        } catch (const std::exception&) {
+            g_last_error = "cross_encoder_reranker is not configured";
+            return -3;
+        }
+
+        std::vector<std::string> batch;
+        batch.reserve(count);
+        for (int i = 0; i < count; ++i) {
+            batch.push_back(documents[i] ? documents[i] : "");
+        }
+
+        auto scores = reranker->score(query, batch);
+        if (static_cast<int>(scores.size()) != count) {
+            g_last_error = "reranker returned a wrong number of scores";
+            return -1;
+        }
+        std::memcpy(out_scores, scores.data(), count * sizeof(float));
+        return 0;
+    } catch (const std::exception& e) {
+        g_last_error = e.what();
+        return -1;
+    } catch (...) {
+        g_last_error = "unknown C++ exception during jina_rerank";
+        return -1;
+    }
+}
+
 }
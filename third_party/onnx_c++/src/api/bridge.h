@@ -48,6 +48,16 @@ int jina_embed_batch_text(JinaHandle handle, const char** texts, int count, floa
  */
 int jina_embed_batch_image(JinaHandle handle, const uint8_t* imgs_data, int count, int width, int height, int channels, float* out_data);
 
+/**
+ * 5. Rerank documents against a query with the cross-encoder
+ * @param query: C-string query
+ * @param documents: Array of C-strings
+ * @param count: Number of documents
+ * @param out_scores: Pre-allocated float array (size count), scores in [0,1]
+ * @return: 0 on success, -3 when no reranker is configured
+ */
+int jina_rerank(JinaHandle handle, const char* query, const char** documents, int count, float* out_scores);
+
 #ifdef __cplusplus
 }
 #endif
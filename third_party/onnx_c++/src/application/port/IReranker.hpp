@@ -0,0 +1,12 @@
+#pragma once
+
+#include <string>
+#include <vector>
+
+class IReranker {
+public:
+    virtual ~IReranker() = default;
+    // Returns one relevance score in [0,1] per document, in input order.
+    virtual std::vector<float> score(const std::string& query,
+                                     const std::vector<std::string>& documents) = 0;
+};
@@ -4,6 +4,7 @@
 #include "infra/onnx_session.hpp"
 #include "infra/jina_text_encoder.hpp"
 #include "infra/jina_vision_encoder.hpp"
+#include "infra/cross_encoder_reranker.hpp"
 #include "utils/configloader.hpp"
 
 #include <iostream>
@@ -16,6 +17,7 @@ void ServiceRegistry::registerServices(DIContainer& container, const std::string
     registerOnnxEnvironment(container);
     registerTextEncoder(container);
     registerVisionEncoder(container);
+    registerReranker(container);
 }
 
 void ServiceRegistry::registerConfigLoaders(DIContainer& container, const std::string& configPath) {
@@ -57,4 +59,22 @@ void ServiceRegistry::registerVisionEncoder(DIContainer& container) {
     );
 }
 
+void ServiceRegistry::registerReranker(DIContainer& container) {
+    auto config = container.resolve<ConfigLoader>();
+    if (!config->HasRerankerConfig()) {
+        std::cout << "bootstrap.registry: cross_encoder_reranker not configured, Rerank disabled" << std::endl;
+        return;
+    }
+    auto env = container.resolve<IOnnxEnvironment>();
+    const auto& rerankCfg = config->GetRerankerConfig();
+
+    auto* session = new OnnxSession(env.get());
+    session->initialize(rerankCfg.session_config);
+    session->loadModel(rerankCfg.model_path.c_str());
+
+    container.registerSingleton<IReranker, CrossEncoderReranker>(
+        static_cast<IOnnxSession*>(session), rerankCfg.vocab_path, rerankCfg.max_seq_len, rerankCfg.batch_size
+    );
+}
+
 }  // namespace bootstrap
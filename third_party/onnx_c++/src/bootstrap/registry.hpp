@@ -14,6 +14,7 @@ private:
     static void registerConfigLoaders(DIContainer& container, const std::string& configPath);
     static void registerTextEncoder(DIContainer& container);
     static void registerVisionEncoder(DIContainer& container);
+    static void registerReranker(DIContainer& container);
 };
 
 }  // namespace bootstrap
@@ -57,4 +57,12 @@ struct VisionEmbedConfig {
     int image_height = 224;
 };
 
+struct RerankerConfig {
+    std::string       model_path;
+    std::string       vocab_path  = "model/tokenizer/vocab.txt";
+    int               max_seq_len = 512;
+    int               batch_size  = 16;
+    OnnxSessionConfig session_config;
+};
+
 #endif
@@ -0,0 +1,215 @@
+#include "bert_tokenizer.hpp"
+
+#include "bert_unicode_tables.hpp"
+
+#include <algorithm>
+#include <fstream>
+#include <iterator>
+#include <stdexcept>
+#include <utility>
+
+namespace {
+
+constexpr size_t kMaxInputCharsPerWord = 100;
+
+// decodeUtf8 returns the code points of text; malformed bytes become U+FFFD.
+std::vector<char32_t> decodeUtf8(const std::string& text) {
+    std::vector<char32_t> out;
+    out.reserve(text.size());
+    size_t i = 0;
+    while (i < text.size()) {
+        const auto c = static_cast<unsigned char>(text[i]);
+        size_t len = 0;
+        char32_t cp = 0;
+        if (c < 0x80) {
+            len = 1;
+            cp = c;
+        } else if ((c & 0xE0) == 0xC0) {
+            len = 2;
+            cp = c & 0x1F;
+        } else if ((c & 0xF0) == 0xE0) {
+            len = 3;
+            cp = c & 0x0F;
+        } else if ((c & 0xF8) == 0xF0) {
+            len = 4;
+            cp = c & 0x07;
+        }
+        bool ok = len > 0 && i + len <= text.size();
+        for (size_t k = 1; ok && k < len; ++k) {
+            const auto cc = static_cast<unsigned char>(text[i + k]);
+            if ((cc & 0xC0) != 0x80) ok = false;
+            cp = (cp << 6) | (cc & 0x3F);
+        }
+        if (!ok) {
+            out.push_back(0xFFFD);
+            ++i;
+            continue;
+        }
+        out.push_back(cp);
+        i += len;
+    }
+    return out;
+}
+
+void appendUtf8(std::string& out, const char32_t cp) {
+    if (cp < 0x80) {
+        out += static_cast<char>(cp);
+    } else if (cp < 0x800) {
+        out += static_cast<char>(0xC0 | (cp >> 6));
+        out += static_cast<char>(0x80 | (cp & 0x3F));
+    } else if (cp < 0x10000) {
+        out += static_cast<char>(0xE0 | (cp >> 12));
+        out += static_cast<char>(0x80 | ((cp >> 6) & 0x3F));
+        out += static_cast<char>(0x80 | (cp & 0x3F));
+    } else {
+        out += static_cast<char>(0xF0 | (cp >> 18));
+        out += static_cast<char>(0x80 | ((cp >> 12) & 0x3F));
+        out += static_cast<char>(0x80 | ((cp >> 6) & 0x3F));
+        out += static_cast<char>(0x80 | (cp & 0x3F));
+    }
+}
+
+template <size_t N>
+bool inRanges(const bert_unicode::Range (&ranges)[N], const char32_t cp) {
+    if (cp > 0xFFFF) return false;
+    const auto it = std::upper_bound(std::begin(ranges), std::end(ranges), cp,
+                                     [](const char32_t v, const bert_unicode::Range& r) { return v < r.first; });
+    return it != std::begin(ranges) && cp <= std::prev(it)->last;
+}
+
+bool isWhitespace(const char32_t cp) {
+    return cp == ' ' || cp == '\t' || cp == '\n' || cp == '\r' || cp == 0x00A0 || cp == 0x1680 ||
+           (cp >= 0x2000 && cp <= 0x200A) || cp == 0x202F || cp == 0x205F || cp == 0x3000;
+}
+
+// isControl covers Cc and the Cf characters that show up in extracted text
+// (soft hyphen, zero-width and bidi marks, BOM). BERT drops both.
+bool isControl(const char32_t cp) {
+    if (cp == '\t' || cp == '\n' || cp == '\r') return false;
+    return cp < 0x20 || (cp >= 0x7F && cp <= 0x9F) || cp == 0x00AD || (cp >= 0x200B && cp <= 0x200F) ||
+           (cp >= 0x202A && cp <= 0x202E) || (cp >= 0x2060 && cp <= 0x2064) || cp == 0xFEFF;
+}
+
+bool isPunctuation(const char32_t cp) {
+    if ((cp >= 33 && cp <= 47) || (cp >= 58 && cp <= 64) || (cp >= 91 && cp <= 96) || (cp >= 123 && cp <= 126)) {
+        return true;
+    }
+    return inRanges(bert_unicode::kPunctuation, cp);
+}
+
+// isCJK matches the CJK Unified Ideograph blocks of BERT's _is_chinese_char.
+bool isCJK(const char32_t cp) {
+    return (cp >= 0x4E00 && cp <= 0x9FFF) || (cp >= 0x3400 && cp <= 0x4DBF) || (cp >= 0x20000 && cp <= 0x2A6DF) ||
+           (cp >= 0x2A700 && cp <= 0x2B73F) || (cp >= 0x2B740 && cp <= 0x2B81F) ||
+           (cp >= 0x2B820 && cp <= 0x2CEAF) || (cp >= 0xF900 && cp <= 0xFAFF) || (cp >= 0x2F800 && cp <= 0x2FA1F);
+}
+
+// fold lowercases cp and strips its accents, the per-character result of
+// lower() followed by NFD without non-spacing marks.
+char32_t fold(const char32_t cp) {
+    if (cp >= 'A' && cp <= 'Z') return cp + ('a' - 'A');
+    if (cp < 0x80 || cp > 0xFFFF) return cp;
+    const auto it = std::lower_bound(std::begin(bert_unicode::kFolds), std::end(bert_unicode::kFolds), cp,
+                                     [](const bert_unicode::Fold& f, const char32_t v) { return f.from < v; });
+    if (it != std::end(bert_unicode::kFolds) && it->from == cp) return it->to;
+    return cp;
+}
+
+}  // namespace
+
+BertTokenizer::BertTokenizer(const std::string& vocabPath) {
+    std::ifstream file(vocabPath);
+    if (!file.is_open()) {
+        throw std::runtime_error("third_party.onnx_c++.src.infra.bert_tokenizer: cannot open vocab file " + vocabPath);
+    }
+    std::string line;
+    int index = 0;
+    while (std::getline(file, line)) {
+        if (!line.empty() && line.back() == '\r') line.pop_back();
+        vocab_[line] = index++;
+    }
+    resolveSpecialTokens();
+}
+
+BertTokenizer::BertTokenizer(std::unordered_map<std::string, int> vocab) : vocab_(std::move(vocab)) {
+    resolveSpecialTokens();
+}
+
+void BertTokenizer::resolveSpecialTokens() {
+    if (auto it = vocab_.find("[CLS]"); it != vocab_.end()) clsTokenId_ = it->second;
+    if (auto it = vocab_.find("[SEP]"); it != vocab_.end()) sepTokenId_ = it->second;
+    if (auto it = vocab_.find("[UNK]"); it != vocab_.end()) unkTokenId_ = it->second;
+}
+
+std::vector<std::string> BertTokenizer::basicTokenize(const std::string& text) {
+    std::vector<std::string> words;
+    std::string current;
+    auto flush = [&]() {
+        if (!current.empty()) {
+            words.push_back(current);
+            current.clear();
+        }
+    };
+
+    for (const char32_t raw : decodeUtf8(text)) {
+        if (raw == 0 || raw == 0xFFFD || isControl(raw)) continue;
+        if (isWhitespace(raw)) {
+            flush();
+            continue;
+        }
+        const char32_t cp = fold(raw);
+        if (inRanges(bert_unicode::kNonSpacingMarks, cp)) continue;
+        if (isPunctuation(cp) || isCJK(cp)) {
+            flush();
+            std::string single;
+            appendUtf8(single, cp);
+            words.push_back(single);
+            continue;
+        }
+        appendUtf8(current, cp);
+    }
+    flush();
+    return words;
+}
+
+std::vector<int64_t> BertTokenizer::tokenize(const std::string& text) const {
+    std::vector<int64_t> ids;
+    for (const auto& word : basicTokenize(text)) {
+        // Byte offsets of the character boundaries, so pieces never split a
+        // UTF-8 sequence.
+        std::vector<size_t> bounds;
+        for (size_t i = 0; i < word.size(); ++i) {
+            if ((static_cast<unsigned char>(word[i]) & 0xC0) != 0x80) bounds.push_back(i);
+        }
+        if (bounds.size() > kMaxInputCharsPerWord) {
+            ids.push_back(unkTokenId_);
+            continue;
+        }
+        bounds.push_back(word.size());
+
+        std::vector<int64_t> pieces;
+        size_t start = 0;
+        bool bad = false;
+        while (start + 1 < bounds.size()) {
+            int64_t found = -1;
+            size_t end = bounds.size() - 1;
+            for (; end > start; --end) {
+                std::string sub = word.substr(bounds[start], bounds[end] - bounds[start]);
+                if (start > 0) sub = "##" + sub;
+                if (auto it = vocab_.find(sub); it != vocab_.end()) {
+                    found = it->second;
+                    break;
+                }
+            }
+            if (found < 0) {
+                bad = true;
+                break;
+            }
+            pieces.push_back(found);
+            start = end;
+        }
+        if (bad) ids.push_back(unkTokenId_);
+        else ids.insert(ids.end(), pieces.begin(), pieces.end());
+    }
+    return ids;
+}
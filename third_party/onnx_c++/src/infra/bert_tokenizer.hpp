@@ -0,0 +1,32 @@
+#pragma once
+
+#include <cstdint>
+#include <string>
+#include <unordered_map>
+#include <vector>
+
+// BertTokenizer is the uncased BERT tokenizer: basic tokenization (Unicode
+// lowercasing, NFD accent stripping, splitting on whitespace, punctuation
+// and CJK characters) followed by greedy longest-match WordPiece.
+class BertTokenizer {
+public:
+    explicit BertTokenizer(const std::string& vocabPath);
+    explicit BertTokenizer(std::unordered_map<std::string, int> vocab);
+
+    // basicTokenize returns the words WordPiece runs on, as UTF-8.
+    static std::vector<std::string> basicTokenize(const std::string& text);
+
+    std::vector<int64_t> tokenize(const std::string& text) const;
+
+    int64_t clsTokenId() const { return clsTokenId_; }
+    int64_t sepTokenId() const { return sepTokenId_; }
+    int64_t unkTokenId() const { return unkTokenId_; }
+
+private:
+    void resolveSpecialTokens();
+
+    std::unordered_map<std::string, int> vocab_;
+    int64_t clsTokenId_ = 101;
+    int64_t sepTokenId_ = 102;
+    int64_t unkTokenId_ = 100;
+};
@@ -0,0 +1,221 @@
+// Generated by gen_bert_unicode_tables.py (Unicode 14.0.0). Do not edit.
+#pragma once
+
+#include <cstdint>
+
+namespace bert_unicode {
+
+struct Fold {
+    uint16_t from;
+    uint16_t to;
+};
+
+struct Range {
+    uint16_t first;
+    uint16_t last;
+};
+
+// Lowercase letter with its accents stripped, sorted by from.
+inline constexpr Fold kFolds[] = {
+    {0x00C0, 0x0061}, {0x00C1, 0x0061}, {0x00C2, 0x0061}, {0x00C3, 0x0061}, {0x00C4, 0x0061}, {0x00C5, 0x0061},
+    {0x00C6, 0x00E6}, {0x00C7, 0x0063}, {0x00C8, 0x0065}, {0x00C9, 0x0065}, {0x00CA, 0x0065}, {0x00CB, 0x0065},
+    {0x00CC, 0x0069}, {0x00CD, 0x0069}, {0x00CE, 0x0069}, {0x00CF, 0x0069}, {0x00D0, 0x00F0}, {0x00D1, 0x006E},
+    {0x00D2, 0x006F}, {0x00D3, 0x006F}, {0x00D4, 0x006F}, {0x00D5, 0x006F}, {0x00D6, 0x006F}, {0x00D8, 0x00F8},
+    {0x00D9, 0x0075}, {0x00DA, 0x0075}, {0x00DB, 0x0075}, {0x00DC, 0x0075}, {0x00DD, 0x0079}, {0x00DE, 0x00FE},
+    {0x00E0, 0x0061}, {0x00E1, 0x0061}, {0x00E2, 0x0061}, {0x00E3, 0x0061}, {0x00E4, 0x0061}, {0x00E5, 0x0061},
+    {0x00E7, 0x0063}, {0x00E8, 0x0065}, {0x00E9, 0x0065}, {0x00EA, 0x0065}, {0x00EB, 0x0065}, {0x00EC, 0x0069},
+    {0x00ED, 0x0069}, {0x00EE, 0x0069}, {0x00EF, 0x0069}, {0x00F1, 0x006E}, {0x00F2, 0x006F}, {0x00F3, 0x006F},
+    {0x00F4, 0x006F}, {0x00F5, 0x006F}, {0x00F6, 0x006F}, {0x00F9, 0x0075}, {0x00FA, 0x0075}, {0x00FB, 0x0075},
+    {0x00FC, 0x0075}, {0x00FD, 0x0079}, {0x00FF, 0x0079}, {0x0100, 0x0061}, {0x0101, 0x0061}, {0x0102, 0x0061},
+    {0x0103, 0x0061}, {0x0104, 0x0061}, {0x0105, 0x0061}, {0x0106, 0x0063}, {0x0107, 0x0063}, {0x0108, 0x0063},
+    {0x0109, 0x0063}, {0x010A, 0x0063}, {0x010B, 0x0063}, {0x010C, 0x0063}, {0x010D, 0x0063}, {0x010E, 0x0064},
+    {0x010F, 0x0064}, {0x0110, 0x0111}, {0x0112, 0x0065}, {0x0113, 0x0065}, {0x0114, 0x0065}, {0x0115, 0x0065},
+    {0x0116, 0x0065}, {0x0117, 0x0065}, {0x0118, 0x0065}, {0x0119, 0x0065}, {0x011A, 0x0065}, {0x011B, 0x0065},
+    {0x011C, 0x0067}, {0x011D, 0x0067}, {0x011E, 0x0067}, {0x011F, 0x0067}, {0x0120, 0x0067}, {0x0121, 0x0067},
+    {0x0122, 0x0067}, {0x0123, 0x0067}, {0x0124, 0x0068}, {0x0125, 0x0068}, {0x0126, 0x0127}, {0x0128, 0x0069},
+    {0x0129, 0x0069}, {0x012A, 0x0069}, {0x012B, 0x0069}, {0x012C, 0x0069}, {0x012D, 0x0069}, {0x012E, 0x0069},
+    {0x012F, 0x0069}, {0x0130, 0x0049}, {0x0132, 0x0133}, {0x0134, 0x006A}, {0x0135, 0x006A}, {0x0136, 0x006B},
+    {0x0137, 0x006B}, {0x0139, 0x006C}, {0x013A, 0x006C}, {0x013B, 0x006C}, {0x013C, 0x006C}, {0x013D, 0x006C},
+    {0x013E, 0x006C}, {0x013F, 0x0140}, {0x0141, 0x0142}, {0x0143, 0x006E}, {0x0144, 0x006E}, {0x0145, 0x006E},
+    {0x0146, 0x006E}, {0x0147, 0x006E}, {0x0148, 0x006E}, {0x014A, 0x014B}, {0x014C, 0x006F}, {0x014D, 0x006F},
+    {0x014E, 0x006F}, {0x014F, 0x006F}, {0x0150, 0x006F}, {0x0151, 0x006F}, {0x0152, 0x0153}, {0x0154, 0x0072},
+    {0x0155, 0x0072}, {0x0156, 0x0072}, {0x0157, 0x0072}, {0x0158, 0x0072}, {0x0159, 0x0072}, {0x015A, 0x0073},
+    {0x015B, 0x0073}, {0x015C, 0x0073}, {0x015D, 0x0073}, {0x015E, 0x0073}, {0x015F, 0x0073}, {0x0160, 0x0073},
+    {0x0161, 0x0073}, {0x0162, 0x0074}, {0x0163, 0x0074}, {0x0164, 0x0074}, {0x0165, 0x0074}, {0x0166, 0x0167},
+    {0x0168, 0x0075}, {0x0169, 0x0075}, {0x016A, 0x0075}, {0x016B, 0x0075}, {0x016C, 0x0075}, {0x016D, 0x0075},
+    {0x016E, 0x0075}, {0x016F, 0x0075}, {0x0170, 0x0075}, {0x0171, 0x0075}, {0x0172, 0x0075}, {0x0173, 0x0075},
+    {0x0174, 0x0077}, {0x0175, 0x0077}, {0x0176, 0x0079}, {0x0177, 0x0079}, {0x0178, 0x0079}, {0x0179, 0x007A},
+    {0x017A, 0x007A}, {0x017B, 0x007A}, {0x017C, 0x007A}, {0x017D, 0x007A}, {0x017E, 0x007A}, {0x0181, 0x0253},
+    {0x0182, 0x0183}, {0x0184, 0x0185}, {0x0186, 0x0254}, {0x0187, 0x0188}, {0x0189, 0x0256}, {0x018A, 0x0257},
+    {0x018B, 0x018C}, {0x018E, 0x01DD}, {0x018F, 0x0259}, {0x0190, 0x025B}, {0x0191, 0x0192}, {0x0193, 0x0260},
+    {0x0194, 0x0263}, {0x0196, 0x0269}, {0x0197, 0x0268}, {0x0198, 0x0199}, {0x019C, 0x026F}, {0x019D, 0x0272},
+    {0x019F, 0x0275}, {0x01A0, 0x006F}, {0x01A1, 0x006F}, {0x01A2, 0x01A3}, {0x01A4, 0x01A5}, {0x01A6, 0x0280},
+    {0x01A7, 0x01A8}, {0x01A9, 0x0283}, {0x01AC, 0x01AD}, {0x01AE, 0x0288}, {0x01AF, 0x0075}, {0x01B0, 0x0075},
+    {0x01B1, 0x028A}, {0x01B2, 0x028B}, {0x01B3, 0x01B4}, {0x01B5, 0x01B6}, {0x01B7, 0x0292}, {0x01B8, 0x01B9},
+    {0x01BC, 0x01BD}, {0x01C4, 0x01C6}, {0x01C5, 0x01C6}, {0x01C7, 0x01C9}, {0x01C8, 0x01C9}, {0x01CA, 0x01CC},
+    {0x01CB, 0x01CC}, {0x01CD, 0x0061}, {0x01CE, 0x0061}, {0x01CF, 0x0069}, {0x01D0, 0x0069}, {0x01D1, 0x006F},
+    {0x01D2, 0x006F}, {0x01D3, 0x0075}, {0x01D4, 0x0075}, {0x01D5, 0x0075}, {0x01D6, 0x0075}, {0x01D7, 0x0075},
+    {0x01D8, 0x0075}, {0x01D9, 0x0075}, {0x01DA, 0x0075}, {0x01DB, 0x0075}, {0x01DC, 0x0075}, {0x01DE, 0x0061},
+    {0x01DF, 0x0061}, {0x01E0, 0x0061}, {0x01E1, 0x0061}, {0x01E2, 0x00E6}, {0x01E3, 0x00E6}, {0x01E4, 0x01E5},
+    {0x01E6, 0x0067}, {0x01E7, 0x0067}, {0x01E8, 0x006B}, {0x01E9, 0x006B}, {0x01EA, 0x006F}, {0x01EB, 0x006F},
+    {0x01EC, 0x006F}, {0x01ED, 0x006F}, {0x01EE, 0x0292}, {0x01EF, 0x0292}, {0x01F0, 0x006A}, {0x01F1, 0x01F3},
+    {0x01F2, 0x01F3}, {0x01F4, 0x0067}, {0x01F5, 0x0067}, {0x01F6, 0x0195}, {0x01F7, 0x01BF}, {0x01F8, 0x006E},
+    {0x01F9, 0x006E}, {0x01FA, 0x0061}, {0x01FB, 0x0061}, {0x01FC, 0x00E6}, {0x01FD, 0x00E6}, {0x01FE, 0x00F8},
+    {0x01FF, 0x00F8}, {0x0200, 0x0061}, {0x0201, 0x0061}, {0x0202, 0x0061}, {0x0203, 0x0061}, {0x0204, 0x0065},
+    {0x0205, 0x0065}, {0x0206, 0x0065}, {0x0207, 0x0065}, {0x0208, 0x0069}, {0x0209, 0x0069}, {0x020A, 0x0069},
+    {0x020B, 0x0069}, {0x020C, 0x006F}, {0x020D, 0x006F}, {0x020E, 0x006F}, {0x020F, 0x006F}, {0x0210, 0x0072},
+    {0x0211, 0x0072}, {0x0212, 0x0072}, {0x0213, 0x0072}, {0x0214, 0x0075}, {0x0215, 0x0075}, {0x0216, 0x0075},
+    {0x0217, 0x0075}, {0x0218, 0x0073}, {0x0219, 0x0073}, {0x021A, 0x0074}, {0x021B, 0x0074}, {0x021C, 0x021D},
+    {0x021E, 0x0068}, {0x021F, 0x0068}, {0x0220, 0x019E}, {0x0222, 0x0223}, {0x0224, 0x0225}, {0x0226, 0x0061},
+    {0x0227, 0x0061}, {0x0228, 0x0065}, {0x0229, 0x0065}, {0x022A, 0x006F}, {0x022B, 0x006F}, {0x022C, 0x006F},
+    {0x022D, 0x006F}, {0x022E, 0x006F}, {0x022F, 0x006F}, {0x0230, 0x006F}, {0x0231, 0x006F}, {0x0232, 0x0079},
+    {0x0233, 0x0079}, {0x023A, 0x2C65}, {0x023B, 0x023C}, {0x023D, 0x019A}, {0x023E, 0x2C66}, {0x0241, 0x0242},
+    {0x0243, 0x0180}, {0x0244, 0x0289}, {0x0245, 0x028C}, {0x0246, 0x0247}, {0x0248, 0x0249}, {0x024A, 0x024B},
+    {0x024C, 0x024D}, {0x024E, 0x024F}, {0x0370, 0x0371}, {0x0372, 0x0373}, {0x0374, 0x02B9}, {0x0376, 0x0377},
+    {0x037E, 0x003B}, {0x037F, 0x03F3}, {0x0385, 0x00A8}, {0x0386, 0x03B1}, {0x0387, 0x00B7}, {0x0388, 0x03B5},
+    {0x0389, 0x03B7}, {0x038A, 0x03B9}, {0x038C, 0x03BF}, {0x038E, 0x03C5}, {0x038F, 0x03C9}, {0x0390, 0x03B9},
+    {0x0391, 0x03B1}, {0x0392, 0x03B2}, {0x0393, 0x03B3}, {0x0394, 0x03B4}, {0x0395, 0x03B5}, {0x0396, 0x03B6},
+    {0x0397, 0x03B7}, {0x0398, 0x03B8}, {0x0399, 0x03B9}, {0x039A, 0x03BA}, {0x039B, 0x03BB}, {0x039C, 0x03BC},
+    {0x039D, 0x03BD}, {0x039E, 0x03BE}, {0x039F, 0x03BF}, {0x03A0, 0x03C0}, {0x03A1, 0x03C1}, {0x03A3, 0x03C3},
+    {0x03A4, 0x03C4}, {0x03A5, 0x03C5}, {0x03A6, 0x03C6}, {0x03A7, 0x03C7}, {0x03A8, 0x03C8}, {0x03A9, 0x03C9},
+    {0x03AA, 0x03B9}, {0x03AB, 0x03C5}, {0x03AC, 0x03B1}, {0x03AD, 0x03B5}, {0x03AE, 0x03B7}, {0x03AF, 0x03B9},
+    {0x03B0, 0x03C5}, {0x03CA, 0x03B9}, {0x03CB, 0x03C5}, {0x03CC, 0x03BF}, {0x03CD, 0x03C5}, {0x03CE, 0x03C9},
+    {0x03CF, 0x03D7}, {0x03D3, 0x03D2}, {0x03D4, 0x03D2}, {0x03D8, 0x03D9}, {0x03DA, 0x03DB}, {0x03DC, 0x03DD},
+    {0x03DE, 0x03DF}, {0x03E0, 0x03E1}, {0x03E2, 0x03E3}, {0x03E4, 0x03E5}, {0x03E6, 0x03E7}, {0x03E8, 0x03E9},
+    {0x03EA, 0x03EB}, {0x03EC, 0x03ED}, {0x03EE, 0x03EF}, {0x03F4, 0x03B8}, {0x03F7, 0x03F8}, {0x03F9, 0x03F2},
+    {0x03FA, 0x03FB}, {0x03FD, 0x037B}, {0x03FE, 0x037C}, {0x03FF, 0x037D}, {0x0400, 0x0435}, {0x0401, 0x0435},
+    {0x0402, 0x0452}, {0x0403, 0x0433}, {0x0404, 0x0454}, {0x0405, 0x0455}, {0x0406, 0x0456}, {0x0407, 0x0456},
+    {0x0408, 0x0458}, {0x0409, 0x0459}, {0x040A, 0x045A}, {0x040B, 0x045B}, {0x040C, 0x043A}, {0x040D, 0x0438},
+    {0x040E, 0x0443}, {0x040F, 0x045F}, {0x0410, 0x0430}, {0x0411, 0x0431}, {0x0412, 0x0432}, {0x0413, 0x0433},
+    {0x0414, 0x0434}, {0x0415, 0x0435}, {0x0416, 0x0436}, {0x0417, 0x0437}, {0x0418, 0x0438}, {0x0419, 0x0438},
+    {0x041A, 0x043A}, {0x041B, 0x043B}, {0x041C, 0x043C}, {0x041D, 0x043D}, {0x041E, 0x043E}, {0x041F, 0x043F},
+    {0x0420, 0x0440}, {0x0421, 0x0441}, {0x0422, 0x0442}, {0x0423, 0x0443}, {0x0424, 0x0444}, {0x0425, 0x0445},
+    {0x0426, 0x0446}, {0x0427, 0x0447}, {0x0428, 0x0448}, {0x0429, 0x0449}, {0x042A, 0x044A}, {0x042B, 0x044B},
+    {0x042C, 0x044C}, {0x042D, 0x044D}, {0x042E, 0x044E}, {0x042F, 0x044F}, {0x0439, 0x0438}, {0x0450, 0x0435},
+    {0x0451, 0x0435}, {0x0453, 0x0433}, {0x0457, 0x0456}, {0x045C, 0x043A}, {0x045D, 0x0438}, {0x045E, 0x0443},
+    {0x0460, 0x0461}, {0x0462, 0x0463}, {0x0464, 0x0465}, {0x0466, 0x0467}, {0x0468, 0x0469}, {0x046A, 0x046B},
+    {0x046C, 0x046D}, {0x046E, 0x046F}, {0x0470, 0x0471}, {0x0472, 0x0473}, {0x0474, 0x0475}, {0x0476, 0x0475},
+    {0x0477, 0x0475}, {0x0478, 0x0479}, {0x047A, 0x047B}, {0x047C, 0x047D}, {0x047E, 0x047F}, {0x0480, 0x0481},
+    {0x048A, 0x048B}, {0x048C, 0x048D}, {0x048E, 0x048F}, {0x0490, 0x0491}, {0x0492, 0x0493}, {0x0494, 0x0495},
+    {0x0496, 0x0497}, {0x0498, 0x0499}, {0x049A, 0x049B}, {0x049C, 0x049D}, {0x049E, 0x049F}, {0x04A0, 0x04A1},
+    {0x04A2, 0x04A3}, {0x04A4, 0x04A5}, {0x04A6, 0x04A7}, {0x04A8, 0x04A9}, {0x04AA, 0x04AB}, {0x04AC, 0x04AD},
+    {0x04AE, 0x04AF}, {0x04B0, 0x04B1}, {0x04B2, 0x04B3}, {0x04B4, 0x04B5}, {0x04B6, 0x04B7}, {0x04B8, 0x04B9},
+    {0x04BA, 0x04BB}, {0x04BC, 0x04BD}, {0x04BE, 0x04BF}, {0x04C0, 0x04CF}, {0x04C1, 0x0436}, {0x04C2, 0x0436},
+    {0x04C3, 0x04C4}, {0x04C5, 0x04C6}, {0x04C7, 0x04C8}, {0x04C9, 0x04CA}, {0x04CB, 0x04CC}, {0x04CD, 0x04CE},
+    {0x04D0, 0x0430}, {0x04D1, 0x0430}, {0x04D2, 0x0430}, {0x04D3, 0x0430}, {0x04D4, 0x04D5}, {0x04D6, 0x0435},
+    {0x04D7, 0x0435}, {0x04D8, 0x04D9}, {0x04DA, 0x04D9}, {0x04DB, 0x04D9}, {0x04DC, 0x0436}, {0x04DD, 0x0436},
+    {0x04DE, 0x0437}, {0x04DF, 0x0437}, {0x04E0, 0x04E1}, {0x04E2, 0x0438}, {0x04E3, 0x0438}, {0x04E4, 0x0438},
+    {0x04E5, 0x0438}, {0x04E6, 0x043E}, {0x04E7, 0x043E}, {0x04E8, 0x04E9}, {0x04EA, 0x04E9}, {0x04EB, 0x04E9},
+    {0x04EC, 0x044D}, {0x04ED, 0x044D}, {0x04EE, 0x0443}, {0x04EF, 0x0443}, {0x04F0, 0x0443}, {0x04F1, 0x0443},
+    {0x04F2, 0x0443}, {0x04F3, 0x0443}, {0x04F4, 0x0447}, {0x04F5, 0x0447}, {0x04F6, 0x04F7}, {0x04F8, 0x044B},
+    {0x04F9, 0x044B}, {0x04FA, 0x04FB}, {0x04FC, 0x04FD}, {0x04FE, 0x04FF}, {0x1E00, 0x0061}, {0x1E01, 0x0061},
+    {0x1E02, 0x0062}, {0x1E03, 0x0062}, {0x1E04, 0x0062}, {0x1E05, 0x0062}, {0x1E06, 0x0062}, {0x1E07, 0x0062},
+    {0x1E08, 0x0063}, {0x1E09, 0x0063}, {0x1E0A, 0x0064}, {0x1E0B, 0x0064}, {0x1E0C, 0x0064}, {0x1E0D, 0x0064},
+    {0x1E0E, 0x0064}, {0x1E0F, 0x0064}, {0x1E10, 0x0064}, {0x1E11, 0x0064}, {0x1E12, 0x0064}, {0x1E13, 0x0064},
+    {0x1E14, 0x0065}, {0x1E15, 0x0065}, {0x1E16, 0x0065}, {0x1E17, 0x0065}, {0x1E18, 0x0065}, {0x1E19, 0x0065},
+    {0x1E1A, 0x0065}, {0x1E1B, 0x0065}, {0x1E1C, 0x0065}, {0x1E1D, 0x0065}, {0x1E1E, 0x0066}, {0x1E1F, 0x0066},
+    {0x1E20, 0x0067}, {0x1E21, 0x0067}, {0x1E22, 0x0068}, {0x1E23, 0x0068}, {0x1E24, 0x0068}, {0x1E25, 0x0068},
+    {0x1E26, 0x0068}, {0x1E27, 0x0068}, {0x1E28, 0x0068}, {0x1E29, 0x0068}, {0x1E2A, 0x0068}, {0x1E2B, 0x0068},
+    {0x1E2C, 0x0069}, {0x1E2D, 0x0069}, {0x1E2E, 0x0069}, {0x1E2F, 0x0069}, {0x1E30, 0x006B}, {0x1E31, 0x006B},
+    {0x1E32, 0x006B}, {0x1E33, 0x006B}, {0x1E34, 0x006B}, {0x1E35, 0x006B}, {0x1E36, 0x006C}, {0x1E37, 0x006C},
+    {0x1E38, 0x006C}, {0x1E39, 0x006C}, {0x1E3A, 0x006C}, {0x1E3B, 0x006C}, {0x1E3C, 0x006C}, {0x1E3D, 0x006C},
+    {0x1E3E, 0x006D}, {0x1E3F, 0x006D}, {0x1E40, 0x006D}, {0x1E41, 0x006D}, {0x1E42, 0x006D}, {0x1E43, 0x006D},
+    {0x1E44, 0x006E}, {0x1E45, 0x006E}, {0x1E46, 0x006E}, {0x1E47, 0x006E}, {0x1E48, 0x006E}, {0x1E49, 0x006E},
+    {0x1E4A, 0x006E}, {0x1E4B, 0x006E}, {0x1E4C, 0x006F}, {0x1E4D, 0x006F}, {0x1E4E, 0x006F}, {0x1E4F, 0x006F},
+    {0x1E50, 0x006F}, {0x1E51, 0x006F}, {0x1E52, 0x006F}, {0x1E53, 0x006F}, {0x1E54, 0x0070}, {0x1E55, 0x0070},
+    {0x1E56, 0x0070}, {0x1E57, 0x0070}, {0x1E58, 0x0072}, {0x1E59, 0x0072}, {0x1E5A, 0x0072}, {0x1E5B, 0x0072},
+    {0x1E5C, 0x0072}, {0x1E5D, 0x0072}, {0x1E5E, 0x0072}, {0x1E5F, 0x0072}, {0x1E60, 0x0073}, {0x1E61, 0x0073},
+    {0x1E62, 0x0073}, {0x1E63, 0x0073}, {0x1E64, 0x0073}, {0x1E65, 0x0073}, {0x1E66, 0x0073}, {0x1E67, 0x0073},
+    {0x1E68, 0x0073}, {0x1E69, 0x0073}, {0x1E6A, 0x0074}, {0x1E6B, 0x0074}, {0x1E6C, 0x0074}, {0x1E6D, 0x0074},
+    {0x1E6E, 0x0074}, {0x1E6F, 0x0074}, {0x1E70, 0x0074}, {0x1E71, 0x0074}, {0x1E72, 0x0075}, {0x1E73, 0x0075},
+    {0x1E74, 0x0075}, {0x1E75, 0x0075}, {0x1E76, 0x0075}, {0x1E77, 0x0075}, {0x1E78, 0x0075}, {0x1E79, 0x0075},
+    {0x1E7A, 0x0075}, {0x1E7B, 0x0075}, {0x1E7C, 0x0076}, {0x1E7D, 0x0076}, {0x1E7E, 0x0076}, {0x1E7F, 0x0076},
+    {0x1E80, 0x0077}, {0x1E81, 0x0077}, {0x1E82, 0x0077}, {0x1E83, 0x0077}, {0x1E84, 0x0077}, {0x1E85, 0x0077},
+    {0x1E86, 0x0077}, {0x1E87, 0x0077}, {0x1E88, 0x0077}, {0x1E89, 0x0077}, {0x1E8A, 0x0078}, {0x1E8B, 0x0078},
+    {0x1E8C, 0x0078}, {0x1E8D, 0x0078}, {0x1E8E, 0x0079}, {0x1E8F, 0x0079}, {0x1E90, 0x007A}, {0x1E91, 0x007A},
+    {0x1E92, 0x007A}, {0x1E93, 0x007A}, {0x1E94, 0x007A}, {0x1E95, 0x007A}, {0x1E96, 0x0068}, {0x1E97, 0x0074},
+    {0x1E98, 0x0077}, {0x1E99, 0x0079}, {0x1E9B, 0x017F}, {0x1E9E, 0x00DF}, {0x1EA0, 0x0061}, {0x1EA1, 0x0061},
+    {0x1EA2, 0x0061}, {0x1EA3, 0x0061}, {0x1EA4, 0x0061}, {0x1EA5, 0x0061}, {0x1EA6, 0x0061}, {0x1EA7, 0x0061},
+    {0x1EA8, 0x0061}, {0x1EA9, 0x0061}, {0x1EAA, 0x0061}, {0x1EAB, 0x0061}, {0x1EAC, 0x0061}, {0x1EAD, 0x0061},
+    {0x1EAE, 0x0061}, {0x1EAF, 0x0061}, {0x1EB0, 0x0061}, {0x1EB1, 0x0061}, {0x1EB2, 0x0061}, {0x1EB3, 0x0061},
+    {0x1EB4, 0x0061}, {0x1EB5, 0x0061}, {0x1EB6, 0x0061}, {0x1EB7, 0x0061}, {0x1EB8, 0x0065}, {0x1EB9, 0x0065},
+    {0x1EBA, 0x0065}, {0x1EBB, 0x0065}, {0x1EBC, 0x0065}, {0x1EBD, 0x0065}, {0x1EBE, 0x0065}, {0x1EBF, 0x0065},
+    {0x1EC0, 0x0065}, {0x1EC1, 0x0065}, {0x1EC2, 0x0065}, {0x1EC3, 0x0065}, {0x1EC4, 0x0065}, {0x1EC5, 0x0065},
+    {0x1EC6, 0x0065}, {0x1EC7, 0x0065}, {0x1EC8, 0x0069}, {0x1EC9, 0x0069}, {0x1ECA, 0x0069}, {0x1ECB, 0x0069},
+    {0x1ECC, 0x006F}, {0x1ECD, 0x006F}, {0x1ECE, 0x006F}, {0x1ECF, 0x006F}, {0x1ED0, 0x006F}, {0x1ED1, 0x006F},
+    {0x1ED2, 0x006F}, {0x1ED3, 0x006F}, {0x1ED4, 0x006F}, {0x1ED5, 0x006F}, {0x1ED6, 0x006F}, {0x1ED7, 0x006F},
+    {0x1ED8, 0x006F}, {0x1ED9, 0x006F}, {0x1EDA, 0x006F}, {0x1EDB, 0x006F}, {0x1EDC, 0x006F}, {0x1EDD, 0x006F},
+    {0x1EDE, 0x006F}, {0x1EDF, 0x006F}, {0x1EE0, 0x006F}, {0x1EE1, 0x006F}, {0x1EE2, 0x006F}, {0x1EE3, 0x006F},
+    {0x1EE4, 0x0075}, {0x1EE5, 0x0075}, {0x1EE6, 0x0075}, {0x1EE7, 0x0075}, {0x1EE8, 0x0075}, {0x1EE9, 0x0075},
+    {0x1EEA, 0x0075}, {0x1EEB, 0x0075}, {0x1EEC, 0x0075}, {0x1EED, 0x0075}, {0x1EEE, 0x0075}, {0x1EEF, 0x0075},
+    {0x1EF0, 0x0075}, {0x1EF1, 0x0075}, {0x1EF2, 0x0079}, {0x1EF3, 0x0079}, {0x1EF4, 0x0079}, {0x1EF5, 0x0079},
+    {0x1EF6, 0x0079}, {0x1EF7, 0x0079}, {0x1EF8, 0x0079}, {0x1EF9, 0x0079}, {0x1EFA, 0x1EFB}, {0x1EFC, 0x1EFD},
+    {0x1EFE, 0x1EFF},
+};
+
+// Non-ASCII punctuation (category P*) in the BMP, sorted.
+inline constexpr Range kPunctuation[] = {
+    {0x00A1, 0x00A1}, {0x00A7, 0x00A7}, {0x00AB, 0x00AB}, {0x00B6, 0x00B7}, {0x00BB, 0x00BB}, {0x00BF, 0x00BF},
+    {0x037E, 0x037E}, {0x0387, 0x0387}, {0x055A, 0x055F}, {0x0589, 0x058A}, {0x05BE, 0x05BE}, {0x05C0, 0x05C0},
+    {0x05C3, 0x05C3}, {0x05C6, 0x05C6}, {0x05F3, 0x05F4}, {0x0609, 0x060A}, {0x060C, 0x060D}, {0x061B, 0x061B},
+    {0x061D, 0x061F}, {0x066A, 0x066D}, {0x06D4, 0x06D4}, {0x0700, 0x070D}, {0x07F7, 0x07F9}, {0x0830, 0x083E},
+    {0x085E, 0x085E}, {0x0964, 0x0965}, {0x0970, 0x0970}, {0x09FD, 0x09FD}, {0x0A76, 0x0A76}, {0x0AF0, 0x0AF0},
+    {0x0C77, 0x0C77}, {0x0C84, 0x0C84}, {0x0DF4, 0x0DF4}, {0x0E4F, 0x0E4F}, {0x0E5A, 0x0E5B}, {0x0F04, 0x0F12},
+    {0x0F14, 0x0F14}, {0x0F3A, 0x0F3D}, {0x0F85, 0x0F85}, {0x0FD0, 0x0FD4}, {0x0FD9, 0x0FDA}, {0x104A, 0x104F},
+    {0x10FB, 0x10FB}, {0x1360, 0x1368}, {0x1400, 0x1400}, {0x166E, 0x166E}, {0x169B, 0x169C}, {0x16EB, 0x16ED},
+    {0x1735, 0x1736}, {0x17D4, 0x17D6}, {0x17D8, 0x17DA}, {0x1800, 0x180A}, {0x1944, 0x1945}, {0x1A1E, 0x1A1F},
+    {0x1AA0, 0x1AA6}, {0x1AA8, 0x1AAD}, {0x1B5A, 0x1B60}, {0x1B7D, 0x1B7E}, {0x1BFC, 0x1BFF}, {0x1C3B, 0x1C3F},
+    {0x1C7E, 0x1C7F}, {0x1CC0, 0x1CC7}, {0x1CD3, 0x1CD3}, {0x2010, 0x2027}, {0x2030, 0x2043}, {0x2045, 0x2051},
+    {0x2053, 0x205E}, {0x207D, 0x207E}, {0x208D, 0x208E}, {0x2308, 0x230B}, {0x2329, 0x232A}, {0x2768, 0x2775},
+    {0x27C5, 0x27C6}, {0x27E6, 0x27EF}, {0x2983, 0x2998}, {0x29D8, 0x29DB}, {0x29FC, 0x29FD}, {0x2CF9, 0x2CFC},
+    {0x2CFE, 0x2CFF}, {0x2D70, 0x2D70}, {0x2E00, 0x2E2E}, {0x2E30, 0x2E4F}, {0x2E52, 0x2E5D}, {0x3001, 0x3003},
+    {0x3008, 0x3011}, {0x3014, 0x301F}, {0x3030, 0x3030}, {0x303D, 0x303D}, {0x30A0, 0x30A0}, {0x30FB, 0x30FB},
+    {0xA4FE, 0xA4FF}, {0xA60D, 0xA60F}, {0xA673, 0xA673}, {0xA67E, 0xA67E}, {0xA6F2, 0xA6F7}, {0xA874, 0xA877},
+    {0xA8CE, 0xA8CF}, {0xA8F8, 0xA8FA}, {0xA8FC, 0xA8FC}, {0xA92E, 0xA92F}, {0xA95F, 0xA95F}, {0xA9C1, 0xA9CD},
+    {0xA9DE, 0xA9DF}, {0xAA5C, 0xAA5F}, {0xAADE, 0xAADF}, {0xAAF0, 0xAAF1}, {0xABEB, 0xABEB}, {0xFD3E, 0xFD3F},
+    {0xFE10, 0xFE19}, {0xFE30, 0xFE52}, {0xFE54, 0xFE61}, {0xFE63, 0xFE63}, {0xFE68, 0xFE68}, {0xFE6A, 0xFE6B},
+    {0xFF01, 0xFF03}, {0xFF05, 0xFF0A}, {0xFF0C, 0xFF0F}, {0xFF1A, 0xFF1B}, {0xFF1F, 0xFF20}, {0xFF3B, 0xFF3D},
+    {0xFF3F, 0xFF3F}, {0xFF5B, 0xFF5B}, {0xFF5D, 0xFF5D}, {0xFF5F, 0xFF65},
+};
+
+// Non-spacing marks (category Mn) in the BMP, dropped after NFD.
+inline constexpr Range kNonSpacingMarks[] = {
+    {0x0300, 0x036F}, {0x0483, 0x0487}, {0x0591, 0x05BD}, {0x05BF, 0x05BF}, {0x05C1, 0x05C2}, {0x05C4, 0x05C5},
+    {0x05C7, 0x05C7}, {0x0610, 0x061A}, {0x064B, 0x065F}, {0x0670, 0x0670}, {0x06D6, 0x06DC}, {0x06DF, 0x06E4},
+    {0x06E7, 0x06E8}, {0x06EA, 0x06ED}, {0x0711, 0x0711}, {0x0730, 0x074A}, {0x07A6, 0x07B0}, {0x07EB, 0x07F3},
+    {0x07FD, 0x07FD}, {0x0816, 0x0819}, {0x081B, 0x0823}, {0x0825, 0x0827}, {0x0829, 0x082D}, {0x0859, 0x085B},
+    {0x0898, 0x089F}, {0x08CA, 0x08E1}, {0x08E3, 0x0902}, {0x093A, 0x093A}, {0x093C, 0x093C}, {0x0941, 0x0948},
+    {0x094D, 0x094D}, {0x0951, 0x0957}, {0x0962, 0x0963}, {0x0981, 0x0981}, {0x09BC, 0x09BC}, {0x09C1, 0x09C4},
+    {0x09CD, 0x09CD}, {0x09E2, 0x09E3}, {0x09FE, 0x09FE}, {0x0A01, 0x0A02}, {0x0A3C, 0x0A3C}, {0x0A41, 0x0A42},
+    {0x0A47, 0x0A48}, {0x0A4B, 0x0A4D}, {0x0A51, 0x0A51}, {0x0A70, 0x0A71}, {0x0A75, 0x0A75}, {0x0A81, 0x0A82},
+    {0x0ABC, 0x0ABC}, {0x0AC1, 0x0AC5}, {0x0AC7, 0x0AC8}, {0x0ACD, 0x0ACD}, {0x0AE2, 0x0AE3}, {0x0AFA, 0x0AFF},
+    {0x0B01, 0x0B01}, {0x0B3C, 0x0B3C}, {0x0B3F, 0x0B3F}, {0x0B41, 0x0B44}, {0x0B4D, 0x0B4D}, {0x0B55, 0x0B56},
+    {0x0B62, 0x0B63}, {0x0B82, 0x0B82}, {0x0BC0, 0x0BC0}, {0x0BCD, 0x0BCD}, {0x0C00, 0x0C00}, {0x0C04, 0x0C04},
+    {0x0C3C, 0x0C3C}, {0x0C3E, 0x0C40}, {0x0C46, 0x0C48}, {0x0C4A, 0x0C4D}, {0x0C55, 0x0C56}, {0x0C62, 0x0C63},
+    {0x0C81, 0x0C81}, {0x0CBC, 0x0CBC}, {0x0CBF, 0x0CBF}, {0x0CC6, 0x0CC6}, {0x0CCC, 0x0CCD}, {0x0CE2, 0x0CE3},
+    {0x0D00, 0x0D01}, {0x0D3B, 0x0D3C}, {0x0D41, 0x0D44}, {0x0D4D, 0x0D4D}, {0x0D62, 0x0D63}, {0x0D81, 0x0D81},
+    {0x0DCA, 0x0DCA}, {0x0DD2, 0x0DD4}, {0x0DD6, 0x0DD6}, {0x0E31, 0x0E31}, {0x0E34, 0x0E3A}, {0x0E47, 0x0E4E},
+    {0x0EB1, 0x0EB1}, {0x0EB4, 0x0EBC}, {0x0EC8, 0x0ECD}, {0x0F18, 0x0F19}, {0x0F35, 0x0F35}, {0x0F37, 0x0F37},
+    {0x0F39, 0x0F39}, {0x0F71, 0x0F7E}, {0x0F80, 0x0F84}, {0x0F86, 0x0F87}, {0x0F8D, 0x0F97}, {0x0F99, 0x0FBC},
+    {0x0FC6, 0x0FC6}, {0x102D, 0x1030}, {0x1032, 0x1037}, {0x1039, 0x103A}, {0x103D, 0x103E}, {0x1058, 0x1059},
+    {0x105E, 0x1060}, {0x1071, 0x1074}, {0x1082, 0x1082}, {0x1085, 0x1086}, {0x108D, 0x108D}, {0x109D, 0x109D},
+    {0x135D, 0x135F}, {0x1712, 0x1714}, {0x1732, 0x1733}, {0x1752, 0x1753}, {0x1772, 0x1773}, {0x17B4, 0x17B5},
+    {0x17B7, 0x17BD}, {0x17C6, 0x17C6}, {0x17C9, 0x17D3}, {0x17DD, 0x17DD}, {0x180B, 0x180D}, {0x180F, 0x180F},
+    {0x1885, 0x1886}, {0x18A9, 0x18A9}, {0x1920, 0x1922}, {0x1927, 0x1928}, {0x1932, 0x1932}, {0x1939, 0x193B},
+    {0x1A17, 0x1A18}, {0x1A1B, 0x1A1B}, {0x1A56, 0x1A56}, {0x1A58, 0x1A5E}, {0x1A60, 0x1A60}, {0x1A62, 0x1A62},
+    {0x1A65, 0x1A6C}, {0x1A73, 0x1A7C}, {0x1A7F, 0x1A7F}, {0x1AB0, 0x1ABD}, {0x1ABF, 0x1ACE}, {0x1B00, 0x1B03},
+    {0x1B34, 0x1B34}, {0x1B36, 0x1B3A}, {0x1B3C, 0x1B3C}, {0x1B42, 0x1B42}, {0x1B6B, 0x1B73}, {0x1B80, 0x1B81},
+    {0x1BA2, 0x1BA5}, {0x1BA8, 0x1BA9}, {0x1BAB, 0x1BAD}, {0x1BE6, 0x1BE6}, {0x1BE8, 0x1BE9}, {0x1BED, 0x1BED},
+    {0x1BEF, 0x1BF1}, {0x1C2C, 0x1C33}, {0x1C36, 0x1C37}, {0x1CD0, 0x1CD2}, {0x1CD4, 0x1CE0}, {0x1CE2, 0x1CE8},
+    {0x1CED, 0x1CED}, {0x1CF4, 0x1CF4}, {0x1CF8, 0x1CF9}, {0x1DC0, 0x1DFF}, {0x20D0, 0x20DC}, {0x20E1, 0x20E1},
+    {0x20E5, 0x20F0}, {0x2CEF, 0x2CF1}, {0x2D7F, 0x2D7F}, {0x2DE0, 0x2DFF}, {0x302A, 0x302D}, {0x3099, 0x309A},
+    {0xA66F, 0xA66F}, {0xA674, 0xA67D}, {0xA69E, 0xA69F}, {0xA6F0, 0xA6F1}, {0xA802, 0xA802}, {0xA806, 0xA806},
+    {0xA80B, 0xA80B}, {0xA825, 0xA826}, {0xA82C, 0xA82C}, {0xA8C4, 0xA8C5}, {0xA8E0, 0xA8F1}, {0xA8FF, 0xA8FF},
+    {0xA926, 0xA92D}, {0xA947, 0xA951}, {0xA980, 0xA982}, {0xA9B3, 0xA9B3}, {0xA9B6, 0xA9B9}, {0xA9BC, 0xA9BD},
+    {0xA9E5, 0xA9E5}, {0xAA29, 0xAA2E}, {0xAA31, 0xAA32}, {0xAA35, 0xAA36}, {0xAA43, 0xAA43}, {0xAA4C, 0xAA4C},
+    {0xAA7C, 0xAA7C}, {0xAAB0, 0xAAB0}, {0xAAB2, 0xAAB4}, {0xAAB7, 0xAAB8}, {0xAABE, 0xAABF}, {0xAAC1, 0xAAC1},
+    {0xAAEC, 0xAAED}, {0xAAF6, 0xAAF6}, {0xABE5, 0xABE5}, {0xABE8, 0xABE8}, {0xABED, 0xABED}, {0xFB1E, 0xFB1E},
+    {0xFE00, 0xFE0F}, {0xFE20, 0xFE2F},
+};
+
+}  // namespace bert_unicode
@@ -0,0 +1,145 @@
+#include "cross_encoder_reranker.hpp"
+
+#include <algorithm>
+#include <cmath>
+#include <iostream>
+#include <stdexcept>
+
+namespace {
+
+float sigmoid(const float x) {
+    return 1.0f / (1.0f + std::exp(-x));
+}
+
+}  // namespace
+
+CrossEncoderReranker::CrossEncoderReranker(IOnnxSession* session, const std::string& vocabPath,
+                                           const int maxSeqLen, const int batchSize)
+    : session_(session), maxSeqLen_(maxSeqLen), batchSize_(batchSize), tokenizer_(vocabPath) {
+    if (!session_) throw std::invalid_argument("third_party.onnx_c++.src.infra.cross_encoder_reranker: IOnnxSession cannot be null");
+    if (maxSeqLen_ < 8) throw std::invalid_argument("third_party.onnx_c++.src.infra.cross_encoder_reranker: maxSeqLen is too small");
+    if (batchSize_ <= 0) batchSize_ = 16;
+
+    for (size_t i = 0; i < session_->getInputCount(); ++i) {
+        inputNamesStorage_.push_back(session_->getInputName(i));
+    }
+    for (size_t i = 0; i < session_->getOutputCount(); ++i) {
+        outputNamesStorage_.push_back(session_->getOutputName(i));
+    }
+    for (const auto& name : inputNamesStorage_) inputNames_.push_back(name.c_str());
+    for (const auto& name : outputNamesStorage_) outputNames_.push_back(name.c_str());
+
+    std::cout << "third_party.onnx_c++.src.infra.cross_encoder_reranker: Reranker initialized ("
+              << inputNames_.size() << " inputs, " << outputNames_.size() << " outputs)" << std::endl;
+}
+
+CrossEncoderReranker::PairEncoding
+CrossEncoderReranker::encodePair(const std::vector<int64_t>& queryIds, const std::string& document) const {
+    // Budget: [CLS] q [SEP] d [SEP]. The query keeps at most half the window,
+    // the document gets the rest and is truncated first.
+    std::vector<int64_t> q = queryIds;
+    const size_t window = static_cast<size_t>(maxSeqLen_) - 3;
+    if (q.size() > window / 2) q.resize(window / 2);
+    std::vector<int64_t> d = tokenizer_.tokenize(document);
+    if (d.size() > window - q.size()) d.resize(window - q.size());
+
+    PairEncoding enc;
+    enc.input_ids.reserve(q.size() + d.size() + 3);
+    enc.input_ids.push_back(tokenizer_.clsTokenId());
+    enc.input_ids.insert(enc.input_ids.end(), q.begin(), q.end());
+    enc.input_ids.push_back(tokenizer_.sepTokenId());
+    enc.token_type_ids.assign(enc.input_ids.size(), 0);
+    enc.input_ids.insert(enc.input_ids.end(), d.begin(), d.end());
+    enc.input_ids.push_back(tokenizer_.sepTokenId());
+    enc.token_type_ids.resize(enc.input_ids.size(), 1);
+    enc.attention_mask.assign(enc.input_ids.size(), 1);
+    return enc;
+}
+
+std::vector<float> CrossEncoderReranker::scoreBatch(const std::vector<PairEncoding>& batch) {
+    size_t seqLen = 0;
+    for (const auto& enc : batch) seqLen = std::max(seqLen, enc.input_ids.size());
+
+    const auto batchSize = static_cast<int64_t>(batch.size());
+    std::vector<int64_t> ids(batch.size() * seqLen, 0);
+    std::vector<int64_t> mask(batch.size() * seqLen, 0);
+    std::vector<int64_t> types(batch.size() * seqLen, 0);
+    for (size_t b = 0; b < batch.size(); ++b) {
+        std::copy(batch[b].input_ids.begin(), batch[b].input_ids.end(), ids.begin() + b * seqLen);
+        std::copy(batch[b].attention_mask.begin(), batch[b].attention_mask.end(), mask.begin() + b * seqLen);
+        std::copy(batch[b].token_type_ids.begin(), batch[b].token_type_ids.end(), types.begin() + b * seqLen);
+    }
+
+    const std::vector<int64_t> shape = {batchSize, static_cast<int64_t>(seqLen)};
+    void* idsTensor   = session_->createInt64Tensor(shape, ids.data());
+    void* maskTensor  = session_->createInt64Tensor(shape, mask.data());
+    void* typesTensor = session_->createInt64Tensor(shape, types.data());
+    auto releaseInputs = [&]() {
+        session_->releaseTensor(idsTensor);
+        session_->releaseTensor(maskTensor);
+        session_->releaseTensor(typesTensor);
+    };
+
+    std::vector<void*> inputTensors;
+    for (const auto& name : inputNamesStorage_) {
+        if (name == "attention_mask") inputTensors.push_back(maskTensor);
+        else if (name == "token_type_ids") inputTensors.push_back(typesTensor);
+        else inputTensors.push_back(idsTensor);
+    }
+
+    std::vector<void*> outputTensors;
+    try {
+        session_->run(inputNames_, inputTensors, outputNames_, outputTensors);
+    } catch (...) {
+        releaseInputs();
+        throw;
+    }
+    if (outputTensors.empty()) {
+        releaseInputs();
+        throw std::runtime_error("third_party.onnx_c++.src.infra.cross_encoder_reranker: No output from reranker model");
+    }
+
+    float* logits = session_->getFloatTensorData(outputTensors[0]);
+    const auto outShape = session_->getTensorShape(outputTensors[0]);
+    // [batch] or [batch, 1] is a single relevance logit; [batch, 2] is a
+    // two-class head where index 1 is "relevant", whose softmax probability
+    // is the sigmoid of the logit difference.
+    const int64_t width = outShape.size() >= 2 ? outShape[1] : 1;
+
+    std::vector<float> scores;
+    scores.reserve(batch.size());
+    for (int64_t b = 0; b < batchSize; ++b) {
+        const float* row = logits + b * width;
+        scores.push_back(width == 2 ? sigmoid(row[1] - row[0]) : sigmoid(row[width - 1]));
+    }
+
+    releaseInputs();
+    for (auto* t : outputTensors) session_->releaseTensor(t);
+    return scores;
+}
+
+std::vector<float> CrossEncoderReranker::score(const std::string& query,
+                                               const std::vector<std::string>& documents) {
+    if (query.empty()) throw std::invalid_argument("third_party.onnx_c++.src.infra.cross_encoder_reranker: query cannot be empty");
+    if (documents.empty()) return {};
+
+    const auto queryIds = tokenizer_.tokenize(query);
+    std::vector<float> scores;
+    scores.reserve(documents.size());
+
+    std::vector<PairEncoding> batch;
+    batch.reserve(static_cast<size_t>(batchSize_));
+    for (const auto& doc : documents) {
+        batch.push_back(encodePair(queryIds, doc));
+        if (static_cast<int>(batch.size()) == batchSize_) {
+            auto part = scoreBatch(batch);
+            scores.insert(scores.end(), part.begin(), part.end());
+            batch.clear();
+        }
+    }
+    if (!batch.empty()) {
+        auto part = scoreBatch(batch);
+        scores.insert(scores.end(), part.begin(), part.end());
+    }
+    return scores;
+}
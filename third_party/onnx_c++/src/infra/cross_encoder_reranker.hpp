@@ -0,0 +1,43 @@
+#pragma once
+
+#include "application/port/IOnnxSession.hpp"
+#include "application/port/IReranker.hpp"
+#include "infra/bert_tokenizer.hpp"
+
+#include <string>
+#include <vector>
+
+// CrossEncoderReranker scores (query, document) pairs with an uncased
+// BERT-style cross-encoder exported to ONNX (for Vietnamese text, a
+// multilingual one such as amberoad/bert-multilingual-passage-reranking-msmarco).
+// Inputs are [CLS] query [SEP] document [SEP]; the logit is squashed with a
+// sigmoid.
+class CrossEncoderReranker : public IReranker {
+public:
+    CrossEncoderReranker(IOnnxSession* session, const std::string& vocabPath,
+                         int maxSeqLen = 512, int batchSize = 16);
+
+    std::vector<float> score(const std::string& query,
+                             const std::vector<std::string>& documents) override;
+
+private:
+    struct PairEncoding {
+        std::vector<int64_t> input_ids;
+        std::vector<int64_t> attention_mask;
+        std::vector<int64_t> token_type_ids;
+    };
+
+    PairEncoding encodePair(const std::vector<int64_t>& queryIds, const std::string& document) const;
+    std::vector<float> scoreBatch(const std::vector<PairEncoding>& batch);
+
+    IOnnxSession* session_;  // non-owning
+    int maxSeqLen_;
+    int batchSize_;
+
+    BertTokenizer tokenizer_;
+
+    std::vector<std::string> inputNamesStorage_;
+    std::vector<std::string> outputNamesStorage_;
+    std::vector<const char*> inputNames_;
+    std::vector<const char*> outputNames_;
+};
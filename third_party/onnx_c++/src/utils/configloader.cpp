@@ -4,6 +4,7 @@ ConfigLoader::ConfigLoader(const std::string& pathfile) {
     const YAML::Node config = YAML::LoadFile(pathfile);
     if (config["models"]["jina_text_encoder"])   parseTextEmbedding(config);
     if (config["models"]["jina_vision_encoder"]) parseVisionEmbedding(config);
+    if (config["models"]["cross_encoder_reranker"]) parseReranker(config);
 }
 
 void ConfigLoader::parseTextEmbedding(const YAML::Node& config_node) {
@@ -49,6 +50,18 @@ void ConfigLoader::parseVisionEmbedding(const YAML::Node& config_node) {
     loadCommonSession<VisionEmbedConfig>(root, &vision_embed_config_);
 }
 
+void ConfigLoader::parseReranker(const YAML::Node& config_node) {
+    auto root = config_node["models"]["cross_encoder_reranker"];
+
+    reranker_config_.model_path = root["model_path"].as<std::string>();
+    if (root["vocab_path"])  reranker_config_.vocab_path  = root["vocab_path"].as<std::string>();
+    if (root["max_seq_len"]) reranker_config_.max_seq_len = root["max_seq_len"].as<int>();
+    if (root["batch_size"])  reranker_config_.batch_size  = root["batch_size"].as<int>();
+
+    loadCommonSession<RerankerConfig>(root, &reranker_config_);
+    has_reranker_ = true;
+}
+
 const TextEmbedConfig& ConfigLoader::GetTextEmbedConfig() const {
     return text_embed_config_;
 }
@@ -56,3 +69,11 @@ const TextEmbedConfig& ConfigLoader::GetTextEmbedConfig() const {
 const VisionEmbedConfig& ConfigLoader::GetVisionEmbedConfig() const {
     return vision_embed_config_;
 }
+
+const RerankerConfig& ConfigLoader::GetRerankerConfig() const {
+    return reranker_config_;
+}
+
+bool ConfigLoader::HasRerankerConfig() const {
+    return has_reranker_;
+}
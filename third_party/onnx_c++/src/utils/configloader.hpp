@@ -12,10 +12,13 @@ public:
 
     [[nodiscard]] const TextEmbedConfig&   GetTextEmbedConfig()   const;
     [[nodiscard]] const VisionEmbedConfig& GetVisionEmbedConfig() const;
+    [[nodiscard]] const RerankerConfig&    GetRerankerConfig()    const;
+    [[nodiscard]] bool HasRerankerConfig() const;
 
 private:
     void parseTextEmbedding(const YAML::Node& config_node);
     void parseVisionEmbedding(const YAML::Node& config_node);
+    void parseReranker(const YAML::Node& config_node);
 
     template<typename T>
     static void loadCommonSession(const YAML::Node& node, T* cfg) {
@@ -48,6 +51,8 @@ private:
 
     TextEmbedConfig   text_embed_config_;
     VisionEmbedConfig vision_embed_config_;
+    RerankerConfig    reranker_config_;
+    bool              has_reranker_ = false;
 };
 
 #endif
@@ -0,0 +1,68 @@
+#include "infra/bert_tokenizer.hpp"
+
+#include <iostream>
+#include <string>
+#include <type_traits>
+#include <vector>
+
+namespace {
+
+int failures = 0;
+
+template <typename T>
+std::string join(const std::vector<T>& items) {
+    std::string out;
+    for (const auto& item : items) {
+        if (!out.empty()) out += " ";
+        if constexpr (std::is_same_v<T, std::string>) out += item;
+        else out += std::to_string(item);
+    }
+    return out;
+}
+
+template <typename T>
+void expect(const std::string& name, const std::vector<T>& got, const std::vector<T>& want) {
+    if (got == want) return;
+    ++failures;
+    std::cerr << name << ": got [" << join(got) << "], want [" << join(want) << "]\n";
+}
+
+void testBasicTokenizeVietnamese() {
+    expect<std::string>("vietnamese",
+                        BertTokenizer::basicTokenize("ĐƯỜNG Nguyễn Huệ, TP. Hồ Chí Minh: “Ố”"),
+                        {"đuong", "nguyen", "hue", ",", "tp", ".", "ho", "chi", "minh", ":", "“", "o", "”"});
+    // The same text with decomposed accents (NFD input).
+    expect<std::string>("decomposed", BertTokenizer::basicTokenize("Hu\xCC\x9B\xCC\x81"), {"hu"});
+}
+
+void testBasicTokenizeSplits() {
+    expect<std::string>("cjk", BertTokenizer::basicTokenize("ab中文cd"), {"ab", "中", "文", "cd"});
+    expect<std::string>("controls", BertTokenizer::basicTokenize("a\xE2\x80\x8B" "b\xC2\xA0" "c\tdé"), {"ab", "c", "de"});
+    expect<std::string>("malformed", BertTokenizer::basicTokenize("a\xFF" "b"), {"ab"});
+}
+
+void testTokenize() {
+    const BertTokenizer tokenizer({
+        {"[UNK]", 0}, {"[CLS]", 1}, {"[SEP]", 2},
+        {"đuong", 3}, {"nguyen", 4}, {"h", 5}, {"##ue", 6}, {",", 7},
+    });
+    expect<int64_t>("wordpiece", tokenizer.tokenize("Đường Nguyễn, Huệ xyz"), {3, 4, 7, 5, 6, 0});
+    if (tokenizer.clsTokenId() != 1 || tokenizer.sepTokenId() != 2 || tokenizer.unkTokenId() != 0) {
+        ++failures;
+        std::cerr << "special tokens were not read from the vocab\n";
+    }
+}
+
+}  // namespace
+
+int main() {
+    testBasicTokenizeVietnamese();
+    testBasicTokenizeSplits();
+    testTokenize();
+    if (failures > 0) {
+        std::cerr << failures << " check(s) failed\n";
+        return 1;
+    }
+    std::cout << "bert_tokenizer_test: ok\n";
+    return 0;
+}